@@ -17,24 +17,39 @@ limitations under the License.
 package roles
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"text/tabwriter"
 
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/account"
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	"github.com/openshift-online/ocm-cli/pkg/config"
 	"github.com/openshift-online/ocm-cli/pkg/dump"
 	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 )
 
 var args struct {
-	debug bool
+	debug  bool
+	mine   bool
+	output string
 }
 
 var Cmd = &cobra.Command{
 	Use:   "roles [flags] [ROLE_NAME]",
 	Short: "Retrieve information of the different roles",
-	Long:  "Get description of a role or list of all roles",
+	Long: "Get description of a role or list of all roles. Use '--mine' to list the role " +
+		"bindings of the current account instead, which helps to understand why an action " +
+		"is denied.",
+	Example: `  # List all the roles
+  ocm account roles
+  # List the roles bound to the current account
+  ocm account roles --mine
+  # Describe a role
+  ocm account roles OrganizationAdmin`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
 			return fmt.Errorf("Accepts at most 1 role name")
@@ -53,9 +68,35 @@ func init() {
 		false,
 		"Enable debug mode.",
 	)
+	flags.BoolVar(
+		&args.mine,
+		"mine",
+		false,
+		"List the role bindings of the current account instead of all the roles.",
+	)
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		"Output format of the lists, instead of the text. The only option is 'json'.",
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput([]string{"json"}))
 }
 
 func run(cmd *cobra.Command, argv []string) error {
+	if args.output != "" && args.output != "json" {
+		return fmt.Errorf("Invalid output format '%s', the only option is 'json'", args.output)
+	}
+	if len(argv) > 0 {
+		if args.mine {
+			return fmt.Errorf("Option '--mine' can't be used together with a role name")
+		}
+		if args.output != "" {
+			return fmt.Errorf("Option '--output' can't be used together with a role name, " +
+				"roles are always described in JSON")
+		}
+	}
 
 	// Load the configuration file:
 	cfg, err := config.Load()
@@ -82,8 +123,12 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 	defer connection.Close()
 
+	if args.mine {
+		return printRoleBindings(connection)
+	}
+
 	// No role name was provided; Print all roles.
-	var rolesList []string
+	var rolesList []*amv1.Role
 	if len(argv) < 1 {
 		pageIndex := 1
 		for {
@@ -92,10 +137,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			if err != nil {
 				return fmt.Errorf("Can't send request: %v", err)
 			}
-			response.Items().Each(func(item *amv1.Role) bool {
-				rolesList = append(rolesList, item.ID())
-				return true
-			})
+			rolesList = append(rolesList, response.Items().Slice()...)
 			pageIndex++
 
 			// Break on last page
@@ -105,9 +147,18 @@ func run(cmd *cobra.Command, argv []string) error {
 
 		}
 
+		if args.output == "json" {
+			buf := new(bytes.Buffer)
+			err = amv1.MarshalRoleList(rolesList, buf)
+			if err != nil {
+				return fmt.Errorf("Failed to marshal roles: %v", err)
+			}
+			return dump.Pretty(os.Stdout, buf.Bytes())
+		}
+
 		// Print each role:
 		for _, element := range rolesList {
-			fmt.Println(element)
+			fmt.Println(element.ID())
 		}
 
 	} else {
@@ -137,3 +188,40 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	return nil
 }
+
+// printRoleBindings prints the role bindings of the current account, together with the
+// organization or subscription that they apply to.
+func printRoleBindings(connection *sdk.Connection) error {
+	accountResponse, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return fmt.Errorf("Can't retrieve current user information: %v", err)
+	}
+	bindings, err := account.GetRoleBindings(connection, accountResponse.Body().ID())
+	if err != nil {
+		return err
+	}
+
+	if args.output == "json" {
+		buf := new(bytes.Buffer)
+		err = amv1.MarshalRoleBindingList(bindings, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal role bindings: %v", err)
+		}
+		return dump.Pretty(os.Stdout, buf.Bytes())
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ROLE\tTYPE\tRESOURCE\n")
+	for _, binding := range bindings {
+		// Bindings of type 'Application' don't apply to a particular resource:
+		resource := "-"
+		switch binding.Type() {
+		case "Organization":
+			resource = binding.Organization().ID()
+		case "Subscription":
+			resource = binding.Subscription().ID()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", binding.Role().ID(), binding.Type(), resource)
+	}
+	return writer.Flush()
+}
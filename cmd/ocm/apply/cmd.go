@@ -0,0 +1,30 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"github.com/openshift-online/ocm-cli/cmd/ocm/apply/idp"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "apply RESOURCE [flags]",
+	Short: "Apply the desired state of a resource from a file",
+	Long: "Create, update or delete resources so that they match the desired state described " +
+		"in a file.",
+}
+
+func init() {
+	Cmd.AddCommand(idp.Cmd)
+}
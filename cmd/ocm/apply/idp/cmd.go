@@ -0,0 +1,381 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	createidp "github.com/openshift-online/ocm-cli/cmd/ocm/create/idp"
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var args struct {
+	clusterKey   string
+	fromFile     string
+	prune        bool
+	dryRun       bool
+	retries      int
+	timeout      time.Duration
+	allowAnyUser bool
+}
+
+// Cmd is the 'ocm apply idp' command. It uses the same manifest files and builders than
+// 'ocm create idp'.
+var Cmd = &cobra.Command{
+	Use:   "idp --cluster={NAME|ID|EXTERNAL_ID} --from-file=FILE",
+	Short: "Apply the IDPs of a manifest file to a cluster",
+	Long: "Make the identity providers of a cluster match the ones described in a manifest file: " +
+		"identity providers that don't exist are created, and the ones that are different are " +
+		"updated. With the --prune flag identity providers that aren't in the file are deleted. " +
+		"Running it again with the same file doesn't change anything.\n\n" +
+		"The client secrets can't be read from the API, so a change of only the client secret " +
+		"isn't detected, use 'ocm edit idp --client-secret' for that.",
+	Example: `  # Create or update the identity providers described in a manifest file
+  ocm apply idp --cluster=mycluster --from-file=idps.yaml
+  # Print the changes that would be made, including the identity providers that would be deleted
+  ocm apply idp --cluster=mycluster --from-file=idps.yaml --prune --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID or external_id of the cluster to apply the IDPs to "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.StringVar(
+		&args.fromFile,
+		"from-file",
+		"",
+		"Name of a YAML or JSON file describing the identity providers, with the same format "+
+			"used by 'ocm create idp --from-file'. Each identity provider must have a name.",
+	)
+	//nolint:gosec
+	Cmd.MarkFlagRequired("from-file")
+	flags.BoolVar(
+		&args.prune,
+		"prune",
+		false,
+		"Delete the identity providers of the cluster that aren't in the file.",
+	)
+	flags.BoolVar(
+		&args.allowAnyUser,
+		"allow-any-github-user",
+		false,
+		"Allow any GitHub user to log in with the identity providers of the file that have "+
+			"neither organizations nor teams.",
+	)
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Print the changes that would be made, without actually making them.",
+	)
+	flags.IntVar(
+		&args.retries,
+		"retries",
+		3,
+		"Number of times to retry the creation of each identity provider when the API fails "+
+			"with a transient error.",
+	)
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		0,
+		"Maximum time to wait for each request to the API, for example '30s'. By default there "+
+			"is no limit.",
+	)
+}
+
+// Actions that can be applied to an identity provider:
+const (
+	applyCreate    = "created"
+	applyUpdate    = "updated"
+	applyUnchanged = "unchanged"
+	applyDelete    = "deleted"
+)
+
+// applyAction is a change that needs to be made to an identity provider of the cluster, so that it
+// matches the manifest.
+type applyAction struct {
+	kind string
+
+	// The identity provider as it is described in the manifest, for the create and update
+	// actions:
+	desired *cmv1.IdentityProvider
+
+	// The identity provider as it currently is in the cluster, for the update, unchanged and
+	// delete actions:
+	current *cmv1.IdentityProvider
+}
+
+// name returns the name of the identity provider that the action changes.
+func (a *applyAction) name() string {
+	if a.desired != nil {
+		return a.desired.Name()
+	}
+	return a.current.Name()
+}
+
+func runApply(cmd *cobra.Command, argv []string) error {
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Load the manifest before talking to the API, so that mistakes are reported quickly:
+	entries, err := createidp.ReadManifest(args.fromFile)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for i, entry := range entries {
+		if entry.Name() == "" {
+			return fmt.Errorf("Identity provider %d of manifest file '%s' doesn't have a name, "+
+				"it is required to apply it", i+1, args.fromFile)
+		}
+		if names[entry.Name()] {
+			return fmt.Errorf("Manifest file '%s' contains more than one identity provider "+
+				"named '%s'", args.fromFile, entry.Name())
+		}
+		names[entry.Name()] = true
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Get the client for the cluster management api
+	clusterCollection := connection.ClustersMgmt().V1().Clusters()
+	clusters := c.NewCache(connection)
+
+	ctx, cancel := apiContext()
+	cluster, err := clusters.Get(ctx, clusterKey)
+	cancel()
+	err = checkTimeout(ctx, err)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return fmt.Errorf("Cluster '%s' is not yet ready, its current state is '%s'",
+			clusterKey, cluster.State())
+	}
+
+	ctx, cancel = apiContext()
+	idps, err := c.GetIdentityProvidersContext(ctx, clusterCollection, cluster.ID())
+	cancel()
+	err = checkTimeout(ctx, err)
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %w", clusterKey, err)
+	}
+
+	actions, err := planApply(cluster, clusterKey, idps, entries)
+	if err != nil {
+		return err
+	}
+
+	if args.dryRun {
+		for _, action := range actions {
+			if action.kind == applyUnchanged {
+				fmt.Printf("Identity provider '%s' is unchanged\n", action.name())
+			} else {
+				fmt.Printf("Identity provider '%s' would be %s\n", action.name(), action.kind)
+			}
+		}
+		fmt.Printf("Dry run, no changes were made to cluster '%s': %s\n", clusterKey,
+			summarizeApply(actions))
+		return nil
+	}
+
+	idpsClient := clusterCollection.Cluster(cluster.ID()).IdentityProviders()
+	var applied []string
+	for _, action := range actions {
+		err = executeApply(idpsClient, action)
+		if err != nil {
+			if len(applied) > 0 {
+				return fmt.Errorf("Failed to apply IDP '%s' to cluster '%s', the following IDPs "+
+					"were already changed: %s: %w", action.name(), clusterKey,
+					strings.Join(applied, ", "), err)
+			}
+			return fmt.Errorf("Failed to apply IDP '%s' to cluster '%s': %w", action.name(),
+				clusterKey, err)
+		}
+		switch action.kind {
+		case applyUnchanged:
+			quiet.Printf("Identity provider '%s' is unchanged\n", action.name())
+		case applyCreate:
+			quiet.Printf("Identity provider '%s' has been %s\n", action.name(), action.kind)
+			createidp.PrintGithubCallbackURL(cluster, action.desired)
+		default:
+			quiet.Printf("Identity provider '%s' has been %s\n", action.name(), action.kind)
+		}
+		if action.kind != applyUnchanged {
+			applied = append(applied, action.name())
+		}
+	}
+	quiet.Printf("Applied manifest file '%s' to cluster '%s': %s\n", args.fromFile, clusterKey,
+		summarizeApply(actions))
+	return nil
+}
+
+// planApply builds the identity providers of the manifest and compares them with the ones of the
+// cluster to decide what needs to be changed. All the identity providers are built before
+// changing anything, so that invalid ones are detected before changing the cluster.
+func planApply(cluster *cmv1.Cluster, clusterKey string, idps []*cmv1.IdentityProvider,
+	entries []*createidp.ManifestIdentityProvider) ([]*applyAction, error) {
+	current := map[string]*cmv1.IdentityProvider{}
+	for _, idp := range idps {
+		current[idp.Name()] = idp
+	}
+
+	var actions []*applyAction
+	for _, entry := range entries {
+		desired, err := entry.Build(cluster, args.allowAnyUser)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to build IDP '%s' for cluster '%s': %w", entry.Name(),
+				clusterKey, err)
+		}
+		existing := current[entry.Name()]
+		delete(current, entry.Name())
+		switch {
+		case existing == nil:
+			actions = append(actions, &applyAction{kind: applyCreate, desired: desired})
+		case existing.Type() != desired.Type():
+			return nil, fmt.Errorf("Identity provider '%s' of cluster '%s' has type '%s' and "+
+				"can't be changed to '%s', delete it first", entry.Name(), clusterKey,
+				existing.Type(), desired.Type())
+		case githubIdpChanged(existing, desired):
+			actions = append(actions, &applyAction{kind: applyUpdate, desired: desired,
+				current: existing})
+		default:
+			actions = append(actions, &applyAction{kind: applyUnchanged, current: existing})
+		}
+	}
+
+	// Delete the remaining ones in the order that the cluster returned them:
+	if args.prune {
+		for _, idp := range idps {
+			if current[idp.Name()] != nil {
+				actions = append(actions, &applyAction{kind: applyDelete, current: idp})
+			}
+		}
+	}
+	return actions, nil
+}
+
+// executeApply sends the request that makes the given change.
+func executeApply(client *cmv1.IdentityProvidersClient, action *applyAction) error {
+	switch action.kind {
+	case applyCreate:
+		_, err := createidp.AddIdentityProvider(client, action.desired, args.retries, args.timeout)
+		return err
+	case applyUpdate:
+		ctx, cancel := apiContext()
+		defer cancel()
+		_, err := client.IdentityProvider(action.current.ID()).Update().
+			Body(action.desired).
+			SendContext(ctx)
+		return checkTimeout(ctx, err)
+	case applyDelete:
+		ctx, cancel := apiContext()
+		defer cancel()
+		_, err := client.IdentityProvider(action.current.ID()).Delete().SendContext(ctx)
+		return checkTimeout(ctx, err)
+	}
+	return nil
+}
+
+// apiContext returns the context for a request to the API, with the deadline given with the
+// '--timeout' flag.
+func apiContext() (context.Context, context.CancelFunc) {
+	return createidp.RequestContext(args.timeout)
+}
+
+// checkTimeout replaces the given error with a clearer one if it was caused by the deadline of the
+// given context.
+func checkTimeout(ctx context.Context, err error) error {
+	return createidp.CheckTimeout(ctx, err, args.timeout)
+}
+
+// githubIdpChanged checks if the given GitHub identity provider of the cluster is different to the
+// one described in the manifest. The client secret isn't compared because the API doesn't return
+// it. The order of the organizations and teams isn't relevant.
+func githubIdpChanged(current, desired *cmv1.IdentityProvider) bool {
+	if current.MappingMethod() != desired.MappingMethod() {
+		return true
+	}
+	currentGithub := current.Github()
+	desiredGithub := desired.Github()
+	return currentGithub.ClientID() != desiredGithub.ClientID() ||
+		currentGithub.Hostname() != desiredGithub.Hostname() ||
+		currentGithub.CA() != desiredGithub.CA() ||
+		!sameValues(currentGithub.Organizations(), desiredGithub.Organizations()) ||
+		!sameValues(currentGithub.Teams(), desiredGithub.Teams())
+}
+
+// sameValues checks if the given lists contain the same values, ignoring the order.
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// summarizeApply returns a text with the number of identity providers of each kind of action.
+func summarizeApply(actions []*applyAction) string {
+	counts := map[string]int{}
+	for _, action := range actions {
+		counts[action.kind]++
+	}
+	return fmt.Sprintf("%d created, %d updated, %d unchanged, %d deleted",
+		counts[applyCreate], counts[applyUpdate], counts[applyUnchanged], counts[applyDelete])
+}
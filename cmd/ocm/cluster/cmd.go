@@ -17,8 +17,13 @@ limitations under the License.
 package cluster
 
 import (
+	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/current"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/kubeconfig"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/login"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/logs"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/status"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/use"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster/waitready"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +35,11 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.AddCommand(current.Cmd)
+	Cmd.AddCommand(kubeconfig.Cmd)
 	Cmd.AddCommand(login.Cmd)
+	Cmd.AddCommand(logs.Cmd)
 	Cmd.AddCommand(status.Cmd)
+	Cmd.AddCommand(use.Cmd)
+	Cmd.AddCommand(waitready.Cmd)
 }
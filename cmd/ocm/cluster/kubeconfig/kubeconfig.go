@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+)
+
+var args struct {
+	outputFile string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "kubeconfig [flags] {NAME|ID|EXTERNAL_ID}",
+	Short: "Get the admin kubeconfig of a cluster",
+	Long: "Get the kubeconfig file of the administrator of a cluster, generated during the " +
+		"installation. It is written to the standard output, unless a file is given.",
+	Example: `  # Use the admin kubeconfig of the cluster named mycluster
+  ocm cluster kubeconfig mycluster --output-file=mycluster.kubeconfig
+  oc --kubeconfig=mycluster.kubeconfig get nodes`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVar(
+		&args.outputFile,
+		"output-file",
+		"",
+		"Name of the file where the kubeconfig will be written, instead of the standard "+
+			"output. It is created with permissions only for the current user, as it contains "+
+			"credentials.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := argv[0]
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	// The credentials are generated during the installation, so asking for them before it
+	// finishes only returns an error that doesn't explain that:
+	if cluster.State() != cmv1.ClusterStateReady {
+		return notAvailableError(clusterKey, cluster.State())
+	}
+
+	response, err := connection.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).
+		Credentials().Get().Send()
+	if err != nil {
+		if response != nil && response.Status() == http.StatusNotFound {
+			return notAvailableError(clusterKey, cluster.State())
+		}
+		return fmt.Errorf("Failed to get credentials of cluster '%s': %v", clusterKey, err)
+	}
+	kubeconfig := response.Body().Kubeconfig()
+	if kubeconfig == "" {
+		return notAvailableError(clusterKey, cluster.State())
+	}
+
+	if args.outputFile == "" {
+		fmt.Print(kubeconfig)
+		return nil
+	}
+	err = os.WriteFile(args.outputFile, []byte(kubeconfig), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write kubeconfig of cluster '%s': %v", clusterKey, err)
+	}
+	return nil
+}
+
+// notAvailableError returns the error that explains that the credentials of the cluster aren't
+// available yet.
+func notAvailableError(clusterKey string, state cmv1.ClusterState) error {
+	if state == cmv1.ClusterStateReady {
+		return fmt.Errorf("The credentials of cluster '%s' aren't available", clusterKey)
+	}
+	return fmt.Errorf("The credentials of cluster '%s' aren't available yet, its state is "+
+		"'%s'. They will be available when the installation finishes, use 'ocm cluster "+
+		"wait-ready %s' to wait for it", clusterKey, state, clusterKey)
+}
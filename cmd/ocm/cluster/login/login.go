@@ -24,6 +24,7 @@ import (
 
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 )
@@ -104,14 +105,14 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("failed to get cluster '%s': %v", clusterKey, err)
 	}
 
-	fmt.Printf("Will login to cluster:\n Name: %s\n ID: %s\n", cluster.Name(), cluster.ID())
+	quiet.Printf("Will login to cluster:\n Name: %s\n ID: %s\n", cluster.Name(), cluster.ID())
 
 	if args.console {
 		if len(cluster.Console().URL()) == 0 {
 			return fmt.Errorf("cannot find the console URL for cluster: %s", cluster.Name())
 		}
 
-		fmt.Printf(" Console URL: %s\n", cluster.Console().URL())
+		quiet.Printf(" Console URL: %s\n", cluster.Console().URL())
 
 		// Open the console url in the broswer, return any errors
 		return browser.OpenURL(cluster.Console().URL())
@@ -122,12 +123,12 @@ func run(cmd *cobra.Command, argv []string) error {
 			return fmt.Errorf("cannot find the console URL for cluster: %s", cluster.Name())
 		}
 
-		fmt.Printf(" Console URL: %s\n", cluster.Console().URL())
+		quiet.Printf(" Console URL: %s\n", cluster.Console().URL())
 
 		// Create token url from console URL and open browser
 		loginURL := strings.Replace(cluster.Console().URL(), "console-openshift-console", "oauth-openshift", 1)
 		loginURL += "/oauth/token/request"
-		fmt.Printf(" Login URL: %s\n", loginURL)
+		quiet.Printf(" Login URL: %s\n", loginURL)
 		return browser.OpenURL(loginURL)
 	}
 
@@ -0,0 +1,250 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+)
+
+var args struct {
+	logType  string
+	follow   bool
+	interval time.Duration
+}
+
+// Types of logs:
+const (
+	installType   = "install"
+	uninstallType = "uninstall"
+)
+
+var validTypes = []string{installType, uninstallType}
+
+var Cmd = &cobra.Command{
+	Use:   "logs [flags] {NAME|ID|EXTERNAL_ID}",
+	Short: "Show the install or uninstall logs of a cluster",
+	Long: "Show the logs of the installation or uninstallation of a cluster. With --follow " +
+		"the new lines are written as they are added, till the operation finishes.",
+	Example: `  # Follow the installation of the cluster named mycluster
+  ocm cluster logs mycluster --follow
+  # Show the uninstall logs of the cluster named mycluster
+  ocm cluster logs mycluster --type=uninstall`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVar(
+		&args.logType,
+		"type",
+		installType,
+		fmt.Sprintf("Type of logs. Options are %s.", validTypes),
+	)
+	Cmd.RegisterFlagCompletionFunc("type", typeCompletion)
+	flags.BoolVarP(
+		&args.follow,
+		"follow",
+		"f",
+		false,
+		"Write the new lines of the logs till the operation finishes.",
+	)
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		10*time.Second,
+		"Time to wait between checks for new lines, when following the logs.",
+	)
+}
+
+func typeCompletion(cmd *cobra.Command, args []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validTypes, cobra.ShellCompDirectiveNoFileComp
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	if args.logType != installType && args.logType != uninstallType {
+		return fmt.Errorf("Invalid log type '%s', options are %s", args.logType, validTypes)
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := argv[0]
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+	clusterClient := connection.ClustersMgmt().V1().Clusters().Cluster(cluster.ID())
+	logClient := clusterClient.Logs().Install()
+	if args.logType == uninstallType {
+		logClient = clusterClient.Logs().Uninstall()
+	}
+	tail := &logTail{
+		client: logClient,
+		writer: os.Stdout,
+	}
+
+	if !args.follow {
+		found, err := tail.read(false)
+		if err != nil {
+			return fmt.Errorf("Failed to get %s logs of cluster '%s': %v", args.logType,
+				clusterKey, err)
+		}
+		if !found {
+			return fmt.Errorf("The %s logs of cluster '%s' aren't available, its state is '%s'",
+				args.logType, clusterKey, cluster.State())
+		}
+		return nil
+	}
+
+	for {
+		// The state is checked before reading the logs, so that the lines written while the
+		// operation finishes are also written:
+		finished := false
+		response, err := clusterClient.Get().Send()
+		switch {
+		case response != nil && response.Status() == http.StatusNotFound:
+			finished = true
+		case err != nil:
+			return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		default:
+			finished = isFinished(args.logType, response.Body().State())
+		}
+
+		found, err := tail.read(!finished)
+		if err != nil {
+			return fmt.Errorf("Failed to get %s logs of cluster '%s': %v", args.logType,
+				clusterKey, err)
+		}
+		if finished {
+			if !found && tail.lines == 0 {
+				return fmt.Errorf("The %s logs of cluster '%s' aren't available",
+					args.logType, clusterKey)
+			}
+			return nil
+		}
+		time.Sleep(args.interval)
+	}
+}
+
+// isFinished checks if the operation that writes the given type of logs has finished when the
+// cluster is in the given state.
+func isFinished(logType string, state cmv1.ClusterState) bool {
+	if logType == uninstallType {
+		return state == cmv1.ClusterStateError
+	}
+	return c.IsFinalState(state)
+}
+
+// logTail reads the lines of a log that haven't been written yet, using the number of lines
+// already written as the offset.
+type logTail struct {
+	client *cmv1.LogClient
+	writer io.Writer
+
+	// lines is the number of complete lines written so far, and last is the last of them:
+	lines int
+	last  string
+}
+
+// read writes the new lines of the log. When more lines are expected the last one is written only
+// if it is complete, as otherwise it would be written twice. It returns false if the log doesn't
+// exist yet.
+func (t *logTail) read(more bool) (found bool, err error) {
+	content, found, err := t.get(t.lines)
+	if err != nil || !found {
+		return
+	}
+
+	// When the log is rotated it becomes shorter than the offset and the server returns nothing,
+	// the same than when there are no new lines. To tell them apart the last line that was
+	// written is requested again:
+	if content == "" && t.lines > 0 {
+		var previous string
+		previous, found, err = t.get(t.lines - 1)
+		if err != nil || !found {
+			return
+		}
+		if strings.SplitN(previous, "\n", 2)[0] == t.last {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "The log was rotated, writing it from the beginning\n")
+		t.lines = 0
+		t.last = ""
+		content, found, err = t.get(0)
+		if err != nil || !found {
+			return
+		}
+	}
+
+	if more {
+		content = content[:strings.LastIndex(content, "\n")+1]
+	}
+	if content == "" {
+		return
+	}
+	_, err = io.WriteString(t.writer, content)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	t.lines += strings.Count(content, "\n")
+	t.last = lines[len(lines)-1]
+	return
+}
+
+// get retrieves the log starting after the given number of lines.
+func (t *logTail) get(offset int) (content string, found bool, err error) {
+	request := t.client.Get()
+	if offset > 0 {
+		request = request.Offset(offset)
+	}
+	response, err := request.Send()
+	if response != nil && response.Status() == http.StatusNotFound {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return response.Body().Content(), true, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package use
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "use {NAME|ID|EXTERNAL_ID}",
+	Short: "Select the default cluster",
+	Long: "Select the cluster used by the commands that have a '--cluster' option when it isn't " +
+		"given. The cluster is saved to the configuration file, and a '--cluster' option given " +
+		"in the command line always takes precedence.",
+	Example: `  # Use the cluster named 'mycluster' by default
+  ocm cluster use mycluster
+  # List the machine pools of that cluster
+  ocm list machinepools`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: arguments.CompleteCluster,
+	RunE:              run,
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := argv[0]
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Check that the cluster exists, and save the identifier, as it doesn't change even if the
+	// cluster is renamed:
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("Can't load config file: %v", err)
+	}
+	cfg.Cluster = cluster.ID()
+	err = config.Save(cfg)
+	if err != nil {
+		return fmt.Errorf("Can't save config file: %v", err)
+	}
+
+	quiet.Printf("Using cluster '%s' (%s) by default\n", cluster.Name(), cluster.ID())
+	return nil
+}
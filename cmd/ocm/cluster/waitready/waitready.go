@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waitready
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+)
+
+var args struct {
+	timeout  time.Duration
+	interval time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "wait-ready [flags] {NAME|ID|EXTERNAL_ID}",
+	Short: "Wait for a cluster to be ready",
+	Long: "Wait till the installation of a cluster finishes. The command succeeds when the " +
+		"cluster is ready, and fails if it is in the error state, if it will not be ready " +
+		"without further action, like when it is hibernating or uninstalling, or if it " +
+		"isn't ready when the timeout expires.",
+	Example: `  # Wait at most two hours for the cluster named mycluster to be ready
+  ocm cluster wait-ready mycluster --timeout=2h`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		60*time.Minute,
+		"Maximum time to wait for the cluster to be ready.",
+	)
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"Time to wait between checks of the state of the cluster.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := argv[0]
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), args.timeout)
+	defer cancel()
+
+	cluster, err := c.GetClusterContext(ctx, connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	// Wait for the cluster, reporting the changes of the state:
+	cluster, err = c.WaitForCluster(ctx, connection.ClustersMgmt().V1().Clusters(), cluster.ID(),
+		args.interval, func(current *cmv1.Cluster) {
+			quiet.Printf("Cluster '%s' is %s\n", clusterKey, current.State())
+		})
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("Cluster '%s' isn't ready after %s", clusterKey, args.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to wait for cluster '%s': %v", clusterKey, err)
+	}
+
+	switch cluster.State() {
+	case cmv1.ClusterStateReady:
+		return nil
+	case cmv1.ClusterStateError:
+		if cluster.Status().ProvisionErrorMessage() != "" {
+			return fmt.Errorf("Cluster '%s' failed to install: %s (%s)", clusterKey,
+				cluster.Status().ProvisionErrorMessage(), cluster.Status().ProvisionErrorCode())
+		}
+		return fmt.Errorf("Cluster '%s' failed to install", clusterKey)
+	default:
+		return fmt.Errorf("Cluster '%s' will not be ready, its state is '%s'", clusterKey,
+			cluster.State())
+	}
+}
@@ -24,7 +24,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift-online/ocm-cli/cmd/ocm/config/get"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/config/listprofiles"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/config/set"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/config/view"
 	"github.com/openshift-online/ocm-cli/pkg/config"
 )
 
@@ -54,10 +56,18 @@ func longHelp() (ret string) {
 The location of the configuration file is gleaned from the 'OCM_CONFIG' environment variable,
 or ~/.ocm.json if that variable is unset. Currently using: %s
 
+A single configuration file can contain multiple profiles, for example one for production and
+another for staging. The profile is selected with the '--profile' flag or the 'OCM_PROFILE'
+environment variable, and if neither is given the 'default' profile is used. Use the
+"ocm config list-profiles" command to see the existing profiles.
+
 The following variables are supported:
 
 %s
 
+Use the "ocm config view" command to see the complete configuration of the active profile, with
+the tokens and secrets redacted.
+
 Note that "ocm config get access_token" gives whatever the file contains - may be missing or expired;
 you probably want "ocm token" command instead which will obtain a fresh token if needed.
 `, loc, configVarDocs())
@@ -73,4 +83,6 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(get.Cmd)
 	Cmd.AddCommand(set.Cmd)
+	Cmd.AddCommand(listprofiles.Cmd)
+	Cmd.AddCommand(view.Cmd)
 }
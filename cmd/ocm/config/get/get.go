@@ -57,35 +57,15 @@ func run(cmd *cobra.Command, argv []string) error {
 	// If the configuration file doesn't exist yet assume that all the configuration settings
 	// are empty:
 	if cfg == nil {
-		fmt.Printf("\n")
-		return nil
+		cfg = &config.Config{}
 	}
 
 	// Print the value of the requested configuration setting:
-	switch argv[0] {
-	case "access_token":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.AccessToken)
-	case "client_id":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.ClientID)
-	case "client_secret":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.ClientSecret)
-	case "insecure":
-		fmt.Fprintf(os.Stdout, "%v\n", cfg.Insecure)
-	case "password":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.Password)
-	case "refresh_token":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.RefreshToken)
-	case "scopes":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.Scopes)
-	case "token_url":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.TokenURL)
-	case "url":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.URL)
-	case "pager":
-		fmt.Fprintf(os.Stdout, "%s\n", cfg.Pager)
-	default:
-		return fmt.Errorf("Unknown setting")
+	value, err := cfg.Get(argv[0])
+	if err != nil {
+		return err
 	}
+	fmt.Fprintf(os.Stdout, "%s\n", value)
 
 	return nil
 }
@@ -0,0 +1,58 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listprofiles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/profile"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "list-profiles",
+	Short: "Prints the names of the profiles of the config file",
+	Long: "Prints the names of the profiles of the config file, one per line. The active profile, " +
+		"selected with the '--profile' flag or the 'OCM_PROFILE' environment variable, is marked " +
+		"with an asterisk.",
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	// Load the names of the profiles:
+	names, err := config.Profiles()
+	if err != nil {
+		return fmt.Errorf("Can't load config file: %v", err)
+	}
+
+	// Print the names, marking the active one. Note that the active profile may not exist yet,
+	// as it is created the first time that its settings are saved.
+	active := profile.Name()
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Fprintf(os.Stdout, "%s %s\n", marker, name)
+	}
+
+	return nil
+}
@@ -18,7 +18,6 @@ package set
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/spf13/cobra"
 
@@ -59,35 +58,11 @@ func run(cmd *cobra.Command, argv []string) error {
 		cfg = &config.Config{}
 	}
 
-	// Copy the value given in the command line to the configuration:
-	name := argv[0]
-	value := argv[1]
-	switch name {
-	case "access_token":
-		cfg.AccessToken = value
-	case "client_id":
-		cfg.ClientID = value
-	case "client_secret":
-		cfg.ClientSecret = value
-	case "insecure":
-		cfg.Insecure, err = strconv.ParseBool(value)
-		if err != nil {
-			return fmt.Errorf("Failed to set insecure: %v", value)
-		}
-	case "password":
-		cfg.Password = value
-	case "refresh_token":
-		cfg.RefreshToken = value
-	case "scopes":
-		return fmt.Errorf("Setting scopes is unsupported")
-	case "token_url":
-		cfg.TokenURL = value
-	case "url":
-		cfg.URL = value
-	case "pager":
-		cfg.Pager = value
-	default:
-		return fmt.Errorf("Unknown setting")
+	// Copy the value given in the command line to the configuration, checking that it is valid
+	// for that setting:
+	err = cfg.Set(argv[0], argv[1])
+	if err != nil {
+		return err
 	}
 
 	// Save the configuration:
@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
+	"github.com/openshift-online/ocm-cli/pkg/profile"
+)
+
+var args struct {
+	output string
+}
+
+var validOutputs = []string{"json", "yaml"}
+
+var Cmd = &cobra.Command{
+	Use:   "view",
+	Short: "Prints the configuration with the credentials redacted",
+	Long: "Prints the location of the config file, the active profile and its configuration. " +
+		"The tokens, the password and the client secret are replaced by '" + config.Redacted +
+		"', so that the output can be shared when debugging authentication problems.",
+	Example: `  # Print the configuration of the staging profile in YAML
+  ocm config view --profile staging --output yaml`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"json",
+		fmt.Sprintf("Output format. Options are %s.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+}
+
+// view contains the fields that are written by the command. The settings are written with the
+// same names that they have in the config file.
+type view struct {
+	File    string                 `json:"file" yaml:"file"`
+	Profile string                 `json:"profile" yaml:"profile"`
+	Config  map[string]interface{} `json:"config" yaml:"config"`
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	if args.output != "json" && args.output != "yaml" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
+	file, err := config.Location()
+	if err != nil {
+		return fmt.Errorf("Can't find config file: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("Can't load config file: %v", err)
+	}
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	// Convert the settings to a map, so that they have the names of the config file also when
+	// they are written in YAML:
+	data, err := json.Marshal(cfg.Redacted())
+	if err != nil {
+		return fmt.Errorf("Failed to marshal config: %v", err)
+	}
+	settings := map[string]interface{}{}
+	err = json.Unmarshal(data, &settings)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal config: %v", err)
+	}
+	result := view{
+		File:    file,
+		Profile: profile.Name(),
+		Config:  settings,
+	}
+
+	if args.output == "json" {
+		body, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal config: %v", err)
+		}
+		return dump.Pretty(os.Stdout, body)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	err = encoder.Encode(result)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal config: %v", err)
+	}
+	return encoder.Close()
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,6 +30,7 @@ import (
 	"github.com/openshift-online/ocm-cli/cmd/ocm/edit/ingress"
 	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	"github.com/openshift-online/ocm-cli/pkg/provider"
 	"github.com/openshift-online/ocm-cli/pkg/utils"
@@ -53,6 +55,7 @@ var args struct {
 	// flags
 	interactive bool
 	dryRun      bool
+	fromFile    string
 
 	region                string
 	version               string
@@ -109,6 +112,10 @@ var Cmd = &cobra.Command{
 	Long: fmt.Sprintf("Create managed OpenShift Dedicated v4 clusters via OCM.\n"+
 		"\n"+
 		"NAME %s", clusterNameHelp),
+	Example: `  # Create a cluster on AWS with the default version
+  ocm create cluster --provider=aws --region=us-east-1 mycluster
+  # Check the cluster described in a manifest file, without creating it
+  ocm create cluster --from-file=mycluster.yaml --dry-run`,
 	PreRunE: preRun,
 	RunE:    run,
 }
@@ -120,7 +127,16 @@ func init() {
 		&args.dryRun,
 		"dry-run",
 		false,
-		"Simulate creating the cluster.",
+		"Simulate creating the cluster. The server validates it, and its JSON description is "+
+			"written to the standard output instead of creating it.",
+	)
+	fs.StringVar(
+		&args.fromFile,
+		"from-file",
+		"",
+		"Name of a YAML or JSON file containing the complete description of the cluster, in the "+
+			"format used by the API. It can't be used together with the flags that describe the "+
+			"cluster.",
 	)
 
 	arguments.AddProviderFlag(fs, &args.provider)
@@ -139,9 +155,9 @@ func init() {
 		&args.region,
 		"region",
 		"",
-		"The cloud provider region to create the cluster in. See `ocm list regions`.",
+		"The cloud provider region to create the cluster in. See `ocm list regions`. "+
+			"Required, unless the cluster is described with --from-file.",
 	)
-	Cmd.MarkFlagRequired("region")
 	Cmd.RegisterFlagCompletionFunc("region", arguments.MakeCompleteFunc(getRegionOptions))
 
 	fs.StringVar(
@@ -405,6 +421,15 @@ func minComputeNodes(ccs bool, multiAZ bool) (min int) {
 }
 
 func preRun(cmd *cobra.Command, argv []string) error {
+	// The manifest contains the complete description of the cluster, so there is nothing to
+	// prompt for:
+	if args.fromFile != "" {
+		return checkManifestFlags(cmd, argv)
+	}
+	if !cmd.Flags().Changed("region") {
+		return fmt.Errorf("required flag(s) \"region\" not set")
+	}
+
 	var err error
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
@@ -534,6 +559,10 @@ func preRun(cmd *cobra.Command, argv []string) error {
 }
 
 func run(cmd *cobra.Command, argv []string) error {
+	if args.fromFile != "" {
+		return runFromFile()
+	}
+
 	// TODO: can we reuse the connection from preRun()?
 	// TODO: call config.Save (https://github.com/openshift-online/ocm-cli/issues/153).
 	connection, err := ocm.NewConnection().Build()
@@ -579,15 +608,26 @@ func run(cmd *cobra.Command, argv []string) error {
 		DefaultIngress:     defaultIngress,
 	}
 
+	err = c.CheckNetworkOverlap(ipNetString(args.machineCIDR), ipNetString(args.serviceCIDR),
+		ipNetString(args.podCIDR))
+	if err != nil {
+		return err
+	}
+
 	cluster, err := c.CreateCluster(connection.ClustersMgmt().V1(), clusterConfig, args.dryRun)
 	if err != nil {
 		return fmt.Errorf("Failed to create cluster: %v", err)
 	}
 
-	// Print the result:
+	// Print the result. In dry run mode the server only validates the cluster, so what is
+	// printed is the description that would have been sent to create it:
 	if cluster == nil {
 		if args.dryRun {
-			fmt.Println("dry run: Would be successful.")
+			clusterSpec, err := c.BuildCluster(clusterConfig)
+			if err != nil {
+				return err
+			}
+			return printClusterSpec(clusterSpec)
 		}
 	} else {
 		err = c.PrintClusterDescription(connection, cluster)
@@ -599,6 +639,35 @@ func run(cmd *cobra.Command, argv []string) error {
 	return nil
 }
 
+// printClusterSpec writes to the standard output the JSON description of the cluster that would be
+// sent to the server, without the secrets, and to the standard error the result of the dry run.
+func printClusterSpec(clusterSpec *cmv1.Cluster) error {
+	buf := new(bytes.Buffer)
+	err := cmv1.MarshalCluster(clusterSpec, buf)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal cluster: %v", err)
+	}
+	body, err := utils.RedactSecrets(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("Failed to marshal cluster: %v", err)
+	}
+	err = dump.Pretty(os.Stdout, body)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "dry run: Would be successful.")
+	return nil
+}
+
+// ipNetString returns the text representation of the given network, or an empty string if it
+// hasn't been given.
+func ipNetString(network net.IPNet) string {
+	if network.IP == nil {
+		return ""
+	}
+	return network.String()
+}
+
 func buildDefaultIngressSpec() (c.DefaultIngressSpec, error) {
 	defaultIngress := c.NewDefaultIngressSpec()
 	if args.defaultIngressRouteSelectors != "" {
@@ -641,7 +710,8 @@ func promptName(argv []string) error {
 			Message: "cluster name",
 			Help:    clusterNameHelp,
 		}
-		return survey.AskOne(prompt, &args.clusterName, survey.WithValidator(survey.Required))
+		return survey.AskOne(prompt, &args.clusterName, survey.WithValidator(survey.Required),
+			arguments.PromptStdio())
 	}
 
 	return fmt.Errorf("A cluster name must be specified")
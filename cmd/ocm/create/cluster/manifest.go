@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestCompatibleFlags are the flags that can be used together with '--from-file', all the
+// others describe the cluster and that is already done by the manifest.
+var manifestCompatibleFlags = map[string]bool{
+	"from-file": true,
+	"dry-run":   true,
+}
+
+// checkManifestFlags checks that the command line doesn't contain values that are also part of
+// the manifest.
+func checkManifestFlags(cmd *cobra.Command, argv []string) error {
+	if len(argv) > 0 {
+		return fmt.Errorf("The cluster name can't be given together with --from-file, it must " +
+			"be in the 'name' field of the manifest")
+	}
+	var conflict string
+	local := cmd.LocalFlags()
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if conflict == "" && local.Lookup(flag.Name) != nil && !manifestCompatibleFlags[flag.Name] {
+			conflict = flag.Name
+		}
+	})
+	if conflict != "" {
+		return fmt.Errorf("The --%s flag can't be used together with --from-file", conflict)
+	}
+	return nil
+}
+
+// loadManifest reads the cluster from the given YAML or JSON file. The content uses the same
+// format as the API, for example:
+//
+//	name: mycluster
+//	cloud_provider:
+//	  id: aws
+//	region:
+//	  id: us-east-1
+//	network:
+//	  machine_cidr: 10.0.0.0/16
+//	  service_cidr: 172.30.0.0/16
+//	  pod_cidr: 10.128.0.0/14
+//	nodes:
+//	  compute: 4
+func loadManifest(file string) (*cmv1.Cluster, error) {
+	// #nosec G304
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read manifest file '%s': %v", file, err)
+	}
+
+	// The SDK only knows how to read JSON, so YAML is converted first. As JSON is a subset of
+	// YAML this works for both formats:
+	var raw interface{}
+	err = yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest file '%s': %v", file, err)
+	}
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("Manifest file '%s' isn't valid: it must contain an object", file)
+	}
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest file '%s': %v", file, err)
+	}
+	cluster, err := cmv1.UnmarshalCluster(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest file '%s': %v", file, err)
+	}
+
+	if cluster.Name() == "" {
+		return nil, fmt.Errorf("Manifest file '%s' isn't valid: it must contain the 'name' of "+
+			"the cluster", file)
+	}
+	network := cluster.Network()
+	err = c.CheckNetworkOverlap(network.MachineCIDR(), network.ServiceCIDR(), network.PodCIDR())
+	if err != nil {
+		return nil, fmt.Errorf("Manifest file '%s' isn't valid: %v", file, err)
+	}
+	return cluster, nil
+}
+
+// runFromFile creates the cluster described in the file given with the '--from-file' flag.
+func runFromFile() error {
+	clusterSpec, err := loadManifest(args.fromFile)
+	if err != nil {
+		return err
+	}
+
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	cluster, err := c.AddCluster(connection.ClustersMgmt().V1(), clusterSpec, args.dryRun)
+	if err != nil {
+		return fmt.Errorf("Failed to create cluster: %v", err)
+	}
+	if cluster == nil {
+		if args.dryRun {
+			return printClusterSpec(clusterSpec)
+		}
+		return nil
+	}
+	return c.PrintClusterDescription(connection, cluster)
+}
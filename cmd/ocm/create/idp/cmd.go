@@ -14,16 +14,27 @@ limitations under the License.
 package idp
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var args struct {
@@ -32,40 +43,71 @@ var args struct {
 	idpType string
 	idpName string
 
-	clientID      string
-	clientSecret  string
-	mappingMethod string
+	clientID         string
+	clientSecret     string
+	clientSecretFile string
+	mappingMethod    string
+	caFile           string
+	idpURL           string
 
 	// GitHub
 	githubHostname      string
 	githubOrganizations string
 	githubTeams         string
+	githubOrgsFile      string
+	githubTeamsFile     string
+	githubAppFile       string
+	githubValidateOrgs  bool
+	githubCheckHostname bool
+	githubOpenBrowser   bool
+	githubAllowAnyUser  bool
 
 	// Google
 	googleHostedDomain string
 
 	// LDAP
-	ldapURL          string
 	ldapBindDN       string
 	ldapBindPassword string
 	ldapIDs          string
 	ldapUsernames    string
 	ldapDisplayNames string
 	ldapEmails       string
+	ldapInsecure     bool
 
 	// OpenID
 	openidIssuerURL   string
 	openidEmail       string
 	openidName        string
 	openidUsername    string
+	openidGroups      string
 	openidExtraScopes string
+	openidDiscover    bool
 
 	// HTPasswd
-	htpasswdUsername string
-	htpasswdPassword string
+	htpasswdUsernames []string
+	htpasswdPasswords []string
+	htpasswdFile      string
+	yes               bool
+
+	nonInteractive bool
+	promptTimeout  time.Duration
+	fromFile       string
+	dryRun         bool
+	force          bool
+	wait           bool
+	waitTimeout    time.Duration
+	retries        int
+	timeout        time.Duration
+	output         string
 }
 
-var validIdps = []string{"github", "google", "ldap", "openid", "htpasswd"}
+// clusters contains the clusters retrieved by the command, so that the prompts and checks that need
+// the cluster don't retrieve it again.
+var clusters *c.Cache
+
+var validIdps = []string{"github", "gitlab", "google", "ldap", "openid", "htpasswd"}
+
+var validMappingMethods = c.MappingMethods
 
 var Cmd = &cobra.Command{
 	Use:   "idp --cluster={NAME|ID|EXTERNAL_ID}",
@@ -74,7 +116,25 @@ var Cmd = &cobra.Command{
 	Example: `  # Add a GitHub identity provider to a cluster named "mycluster"
   ocm create idp --type=github --cluster=mycluster
   # Add an identity provider following interactive prompts
-  ocm create idp --cluster=mycluster`,
+  ocm create idp --cluster=mycluster
+  # Add a GitHub identity provider failing instead of prompting for missing values
+  ocm create idp --type=github --cluster=mycluster --non-interactive \
+  --client-id=abc --client-secret=xyz --organizations=myorg
+  # Add a GitHub identity provider described in a manifest file
+  ocm create idp --cluster=mycluster --from-file=github.yaml
+  # Print the identity provider that would be added, without adding it
+  ocm create idp --cluster=mycluster --from-file=github.yaml --dry-run
+  # Add two GitHub identity providers, one for the organization and one for the team
+  ocm create idp --type=github --cluster=mycluster --name=github \
+  --organizations=myorg --teams=otherorg/myteam
+  # Add an htpasswd identity provider with two users
+  ocm create idp --type=htpasswd --cluster=mycluster \
+  --username=alice --password=... --username=bob --password=...
+  # Add an htpasswd identity provider with the users of an htpasswd file
+  ocm create idp --type=htpasswd --cluster=mycluster --users-file=users.htpasswd
+  # Write errors as a JSON object with a stable code, for use in scripts
+  ocm create idp --type=github --cluster=mycluster --non-interactive --output=json \
+  --client-id=abc --client-secret=xyz --organizations=myorg`,
 	Args: cobra.NoArgs,
 	RunE: run,
 }
@@ -88,10 +148,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to add the IdP to (required).",
+		"Name or ID or external_id of the cluster to add the IdP to "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 
 	flags.StringVarP(
 		&args.idpType,
@@ -113,8 +174,10 @@ func init() {
 		&args.mappingMethod,
 		"mapping-method",
 		"claim",
-		"Specifies how new identities are mapped to users when they log in",
+		fmt.Sprintf("Specifies how new identities are mapped to users when they log in. Options are %s",
+			validMappingMethods),
 	)
+	Cmd.RegisterFlagCompletionFunc("mapping-method", arguments.CompleteMappingMethod)
 	flags.StringVar(
 		&args.clientID,
 		"client-id",
@@ -125,7 +188,30 @@ func init() {
 		&args.clientSecret,
 		"client-secret",
 		"",
-		"Client Secret from the registered application.\n",
+		"Client Secret from the registered application.",
+	)
+	flags.StringVar(
+		&args.clientSecretFile,
+		"client-secret-file",
+		"",
+		"GitHub: Name of a file containing the Client Secret, or '-' to read it from the standard "+
+			"input. The OCM_GITHUB_CLIENT_SECRET environment variable is used if neither this flag "+
+			"nor --client-secret are given.",
+	)
+	flags.StringVar(
+		&args.githubAppFile,
+		"app-credentials-file",
+		"",
+		"GitHub: Name of a file containing the JSON response of the GitHub API to the conversion "+
+			"of a GitHub App manifest, or '-' to read it from the standard input. The Client ID "+
+			"and Client Secret are taken from it, instead of from --client-id and --client-secret.",
+	)
+	flags.StringVar(
+		&args.caFile,
+		"ca-file",
+		"",
+		"Name of a file containing the PEM encoded certificate bundle that will be used to "+
+			"validate the TLS certificates of the identity provider.\n",
 	)
 
 	// GitHub
@@ -141,12 +227,55 @@ func init() {
 		"",
 		"GitHub: Only users that are members of at least one of the listed organizations will be allowed to log in.",
 	)
+	Cmd.RegisterFlagCompletionFunc("organizations", organizationsCompletion)
 	flags.StringVar(
 		&args.githubTeams,
 		"teams",
 		"",
 		"GitHub: Only users that are members of at least one of the listed teams will be allowed to log in. "+
-			"The format is <org>/<team>.\n",
+			"The format is <org>/<team>. If --organizations is also used then a second identity "+
+			"provider, with the '-teams' suffix, is created for the teams.",
+	)
+	flags.StringVar(
+		&args.githubOrgsFile,
+		"organizations-file",
+		"",
+		"GitHub: Name of a file containing the organizations, separated by new lines or commas. "+
+			"They are added to the ones given with --organizations.",
+	)
+	flags.StringVar(
+		&args.githubTeamsFile,
+		"teams-file",
+		"",
+		"GitHub: Name of a file containing the teams, separated by new lines or commas. "+
+			"They are added to the ones given with --teams.",
+	)
+	flags.BoolVar(
+		&args.githubValidateOrgs,
+		"validate-orgs",
+		false,
+		"GitHub: Check that the organizations and teams exist before creating the identity provider.",
+	)
+	flags.BoolVar(
+		&args.githubCheckHostname,
+		"check-hostname",
+		false,
+		"GitHub: Check that the GitHub Enterprise hostname is reachable and that its certificate "+
+			"is valid, using the CA given with --ca-file if any, before creating the identity provider.",
+	)
+	flags.BoolVar(
+		&args.githubOpenBrowser,
+		"open-browser",
+		false,
+		"GitHub: Open the application registration page in the browser. Ignored in non-interactive mode.",
+	)
+	flags.BoolVar(
+		&args.githubAllowAnyUser,
+		"allow-any-github-user",
+		false,
+		"GitHub: Allow any GitHub user to log in, instead of only the members of the organizations "+
+			"or teams. Required in non-interactive mode when neither --organizations nor --teams "+
+			"are given.\n",
 	)
 
 	// Google
@@ -157,13 +286,16 @@ func init() {
 		"Google: Restrict users to a Google Apps domain.\n",
 	)
 
-	// LDAP
+	// GitLab
 	flags.StringVar(
-		&args.ldapURL,
+		&args.idpURL,
 		"url",
 		"",
-		"LDAP: An RFC 2255 URL which specifies the LDAP search parameters to use.",
+		"GitLab: The URL of the GitLab instance, it must use the https scheme.\n"+
+			"LDAP: An RFC 2255 URL which specifies the LDAP search parameters to use.",
 	)
+
+	// LDAP
 	flags.StringVar(
 		&args.ldapBindDN,
 		"bind-dn",
@@ -174,7 +306,7 @@ func init() {
 		&args.ldapBindPassword,
 		"bind-password",
 		"",
-		"LDAP: Password to bind with during the search phase.",
+		"LDAP: Password to bind with during the search phase. Requires --bind-dn.",
 	)
 	flags.StringVar(
 		&args.ldapIDs,
@@ -197,8 +329,14 @@ func init() {
 	flags.StringVar(
 		&args.ldapEmails,
 		"email-attributes",
-		"",
-		"LDAP: The list of attributes whose values should be used as the email address.\n",
+		"mail",
+		"LDAP: The list of attributes whose values should be used as the email address.",
+	)
+	flags.BoolVar(
+		&args.ldapInsecure,
+		"insecure",
+		false,
+		"LDAP: Connect to the server without TLS. Can't be used with ldaps:// URLs.\n",
 	)
 
 	// OpenID
@@ -225,32 +363,143 @@ func init() {
 		&args.openidUsername,
 		"username-claims",
 		"",
-		"OpenID: List of claims to use as the preferred username when provisioning a user.\n",
+		"OpenID: List of claims to use as the preferred username when provisioning a user.",
+	)
+	flags.StringVar(
+		&args.openidGroups,
+		"groups-claims",
+		"",
+		"OpenID: List of claims to use as the groups names.",
 	)
 	flags.StringVar(
 		&args.openidExtraScopes,
 		"extra-scopes",
 		"",
-		"OpenID: List of extra scopes to request when provisioning a user.\n",
+		"OpenID: List of extra scopes to request when provisioning a user.",
+	)
+	flags.BoolVar(
+		&args.openidDiscover,
+		"discover",
+		false,
+		"OpenID: Use the discovery document of the issuer to calculate the default claims and "+
+			"extra scopes.\n",
 	)
 
 	// HTPasswd
-	flags.StringVar(
-		&args.htpasswdUsername,
+	flags.StringArrayVar(
+		&args.htpasswdUsernames,
 		"username",
+		nil,
+		"HTPasswd: Username. Can be repeated, together with --password, to add multiple users.",
+	)
+	flags.StringArrayVar(
+		&args.htpasswdPasswords,
+		"password",
+		nil,
+		"HTPasswd: Password of the user given in the corresponding --username flag.",
+	)
+	flags.StringVar(
+		&args.htpasswdFile,
+		"users-file",
 		"",
-		"HTPasswd: Username.\n",
+		"HTPasswd: Name of an htpasswd file containing the users, with bcrypt hashed passwords.",
+	)
+	flags.BoolVarP(
+		&args.yes,
+		"yes",
+		"y",
+		false,
+		"HTPasswd: Create the users of the htpasswd file without asking for confirmation.\n",
+	)
+
+	flags.BoolVar(
+		&args.nonInteractive,
+		"non-interactive",
+		false,
+		"Never prompt for missing values, fail instead. Useful for scripts and CI pipelines.",
+	)
+	flags.DurationVar(
+		&args.promptTimeout,
+		"prompt-timeout",
+		0,
+		"Maximum time to wait for the answer to each prompt, for example '60s'. By default "+
+			"there is no limit.",
 	)
 
 	flags.StringVar(
-		&args.htpasswdPassword,
-		"password",
+		&args.fromFile,
+		"from-file",
 		"",
-		"HTPasswd: Password.\n",
+		"Name of a YAML or JSON file describing the identity provider. Values can reference "+
+			"environment variables, for example 'client_secret: $GITHUB_CLIENT_SECRET'.",
+	)
+
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Print the identity provider that would be created, without actually creating it.",
+	)
+	flags.BoolVar(
+		&args.force,
+		"force",
+		false,
+		"Don't check if an identity provider with the same name already exists.",
+	)
+	flags.BoolVar(
+		&args.wait,
+		"wait",
+		false,
+		"Wait for the cluster to be ready before adding the identity provider.",
+	)
+	flags.DurationVar(
+		&args.waitTimeout,
+		"wait-timeout",
+		60*time.Minute,
+		"Maximum time to wait for the cluster to be ready, when --wait is used.",
+	)
+	flags.IntVar(
+		&args.retries,
+		"retries",
+		3,
+		"Number of times to retry the creation of the identity provider when the API fails "+
+			"with a transient error.",
+	)
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		0,
+		"Maximum time to wait for each request to the API, for example '30s'. It applies to each "+
+			"of the attempts to create the identity provider, not to all of them. By default there "+
+			"is no limit.",
 	)
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Format of the errors, for use in scripts. Options are %s. The error is "+
+			"written to the standard output as an object containing a stable 'code' and the "+
+			"'message'.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
 }
 
 func run(cmd *cobra.Command, argv []string) error {
+	if args.output != "" && args.output != "json" {
+		return fmt.Errorf("Invalid output format '%s', valid options are %s", args.output, validOutputs)
+	}
+	err := create(cmd, argv)
+	if err != nil && args.output == "json" {
+		writeErr := writeJSONError(os.Stdout, err)
+		if writeErr != nil {
+			return fmt.Errorf("Failed to write error: %v", writeErr)
+		}
+	}
+	return err
+}
+
+func create(cmd *cobra.Command, argv []string) error {
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
@@ -271,56 +520,111 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	// Get the client for the cluster management api
 	clusterCollection := connection.ClustersMgmt().V1().Clusters()
+	clusters = c.NewCache(connection)
 
-	cluster, err := c.GetCluster(connection, clusterKey)
+	ctx, cancel := apiContext()
+	cluster, err := clusters.Get(ctx, clusterKey)
+	cancel()
+	err = checkTimeout(ctx, err)
 	if err != nil {
-		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
 
+	if cluster.State() != cmv1.ClusterStateReady && args.wait {
+		cluster, err = waitForCluster(clusterCollection, cluster)
+		if err != nil {
+			return fmt.Errorf("Failed to wait for cluster '%s': %w", clusterKey, err)
+		}
+		clusters.Update(cluster)
+	}
 	if cluster.State() != cmv1.ClusterStateReady {
-		return fmt.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		return fmt.Errorf("Cluster '%s' is not yet ready, its current state is '%s'. Wait for the "+
+			"installation to complete, or use the --wait flag", clusterKey, cluster.State())
 	}
 
-	idps, err := c.GetIdentityProviders(clusterCollection, cluster.ID())
+	ctx, cancel = apiContext()
+	idps, err := c.GetIdentityProvidersContext(ctx, clusterCollection, cluster.ID())
+	cancel()
+	err = checkTimeout(ctx, err)
 	if err != nil {
-		return fmt.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %w", clusterKey, err)
+	}
+
+	// Load the IDP information from the manifest file, if given
+	if args.fromFile != "" {
+		entries, err := loadManifest(cmd.Flags(), args.fromFile)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 1 {
+			return createFromManifest(clusterCollection, cluster, clusterKey, idps, entries)
+		}
+		applyManifest(entries[0])
 	}
 
 	// Grab all the IDP information interactively if necessary
 	idpType := args.idpType
 
 	if idpType == "" {
+		if args.nonInteractive {
+			return nonInteractiveError("type")
+		}
 		prompt := &survey.Select{
 			Message: "Type of identity provider:",
 			Options: validIdps,
 		}
-		err = survey.AskOne(prompt, &idpType)
+		err = askOne(prompt, &idpType)
 		if err != nil {
-			return fmt.Errorf("Failed to get a valid IDP type")
+			return promptError(err, "Failed to get a valid IDP type")
 		}
 	}
 
 	idpName := args.idpName
 
-	if idpName == "" {
+	if idpName == "" && !args.nonInteractive {
 		prompt := &survey.Input{
 			Message: "Name of the identity provider:",
 		}
-		err = survey.AskOne(prompt, &idpName)
+		err = askOne(prompt, &idpName)
 		if err != nil {
-			return fmt.Errorf("Failed to get a valid IDP name")
+			return promptError(err, "Failed to get a valid IDP name")
 		}
 	}
 
 	var idpBuilder cmv1.IdentityProviderBuilder
 	if idpName == "" {
 		idpName = getNextName(idpType, idps)
+		// The name is part of the OAuth callback URL:
+		errs := validation.IsDNS1123Label(idpName)
+		if len(errs) > 0 {
+			return fmt.Errorf("Generated IDP name '%s' isn't valid: %s", idpName, strings.Join(errs, ", "))
+		}
+		if !args.dryRun {
+			quiet.Printf("Using generated IDP name '%s'\n", idpName)
+		}
+	}
+	if !args.force {
+		for _, idp := range idps {
+			if idp.Name() == idpName {
+				return fmt.Errorf("An identity provider named '%s' already exists on cluster '%s'",
+					idpName, clusterKey)
+			}
+		}
 	}
 
 	message := ""
 	switch idpType {
 	case "github":
+		err = loadGithubListFiles()
+		if err != nil {
+			return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
+		}
+		if args.githubOrganizations != "" && args.githubTeams != "" {
+			return createGithubIdpPair(clusterCollection, cluster, clusterKey, idpName, idps)
+		}
 		idpBuilder, err = buildGithubIdp(cluster, idpName)
+	case "gitlab":
+		idpBuilder, err = buildGitlabIdp(cluster, idpName)
 	case "google":
 		idpBuilder, err = buildGoogleIdp(cluster, idpName)
 	case "ldap":
@@ -333,46 +637,173 @@ func run(cmd *cobra.Command, argv []string) error {
 		err = fmt.Errorf("Invalid IDP type '%s'", idpType)
 	}
 	if err != nil {
-		return fmt.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
 	}
 
-	fmt.Printf("Configuring IDP for cluster '%s'\n", clusterKey)
-
 	idp, err := idpBuilder.Build()
 	if err != nil {
-		return fmt.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
 	}
 
-	_, err = clusterCollection.Cluster(cluster.ID()).
-		IdentityProviders().
-		Add().
-		Body(idp).
-		Send()
+	if args.dryRun {
+		buf := new(bytes.Buffer)
+		err = cmv1.MarshalIdentityProvider(idp, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal IDP for cluster '%s': %v", clusterKey, err)
+		}
+		body, err := utils.RedactSecrets(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("Failed to marshal IDP for cluster '%s': %v", clusterKey, err)
+		}
+		return dump.Pretty(os.Stdout, body)
+	}
+
+	quiet.Printf("Configuring IDP for cluster '%s'\n", clusterKey)
+
+	_, err = addIdentityProvider(clusterCollection.Cluster(cluster.ID()).IdentityProviders(), idp)
 	if err != nil {
-		return fmt.Errorf("Failed to add IDP to cluster '%s': %v", clusterKey, err)
+		return fmt.Errorf("Failed to add IDP to cluster '%s': %w", clusterKey, err)
 	}
 
-	fmt.Printf(
+	quiet.Printf(
 		"Identity Provider '%s' has been created.\nYou need to ensure that there is a list "+
 			"of cluster administrators defined.\nSee 'ocm create user --help' for more "+
-			"information.\nTo login into the console, open %s and click on %s.\n%s",
-		idpName, cluster.Console().URL(), idpName, message,
+			"information.\nTo login into the console, open %s and click on %s.\n",
+		idpName, cluster.Console().URL(), idpName,
 	)
+
+	// The message may contain generated passwords, and this is the only place where they are
+	// shown, so it is a result of the command that is written also in quiet mode:
+	fmt.Fprint(os.Stdout, message)
+	printCallbackURL(cluster, idpType, idpName)
 	return nil
 }
 
+// getCallbackURL returns the URL that the OAuth server of the cluster uses to receive the
+// authorization responses for the given identity provider.
+func getCallbackURL(cluster *cmv1.Cluster, idpName string) string {
+	return c.GetClusterOauthURL(cluster) + "/oauth2callback/" + idpName
+}
+
+// printCallbackURL prints the callback URL of the identity providers that are OAuth applications,
+// so that it can be compared to the one registered in the application.
+func printCallbackURL(cluster *cmv1.Cluster, idpType string, idpName string) {
+	switch idpType {
+	case "github", "gitlab", "google", "openid":
+	default:
+		return
+	}
+	hostname := ""
+	if idpType == "github" {
+		hostname = args.githubHostname
+	}
+	printCallbackURLWith(cluster, idpName, hostname)
+}
+
+// PrintGithubCallbackURL prints the callback URL of the given GitHub identity provider, so that it
+// can be compared to the one registered in the application.
+func PrintGithubCallbackURL(cluster *cmv1.Cluster, idp *cmv1.IdentityProvider) {
+	printCallbackURLWith(cluster, idp.Name(), idp.Github().Hostname())
+}
+
+func printCallbackURLWith(cluster *cmv1.Cluster, idpName string, hostname string) {
+	quiet.Printf("The OAuth callback URL is %s\n", getCallbackURL(cluster, idpName))
+	if hostname != "" {
+		quiet.Printf("Make sure that it is the callback URL of the application registered in '%s'.\n",
+			hostname)
+	}
+}
+
+// getMappingMethod checks that the given mapping method is one of the methods supported by the
+// identity providers.
+func getMappingMethod(value string) (cmv1.IdentityProviderMappingMethod, error) {
+	method, err := c.ParseMappingMethod(value)
+	if err != nil {
+		return "", newIDPError(errorCodeInvalidMappingMethod, "%v", err)
+	}
+	return method, nil
+}
+
+// nonInteractiveError returns the error used when a value is missing and prompting for it has been
+// disabled with the '--non-interactive' flag.
+func nonInteractiveError(flagName string) error {
+	return newIDPError(errorCodeMissingValue, "--%s flag is required in non-interactive mode", flagName)
+}
+
+// readCAFile reads the certificate bundle from the given file, and checks that it contains at least
+// one valid PEM encoded certificate.
+func readCAFile(caFile string) (string, error) {
+	// #nosec G304
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read CA file '%s': %v", caFile, err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return "", fmt.Errorf("CA file '%s' doesn't contain any valid PEM encoded certificate", caFile)
+	}
+	return string(data), nil
+}
+
+// apiContext returns the context for a request to the API, with the deadline given with the
+// '--timeout' flag.
+func apiContext() (context.Context, context.CancelFunc) {
+	return RequestContext(args.timeout)
+}
+
+// RequestContext returns the context for a request to the API, with the given deadline. A zero
+// timeout means that there is no deadline.
+func RequestContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// checkTimeout replaces the given error with a clearer one if it was caused by the deadline of the
+// given context. The ctx.Err() check is needed because some helpers don't wrap the errors that
+// they return.
+func checkTimeout(ctx context.Context, err error) error {
+	return CheckTimeout(ctx, err, args.timeout)
+}
+
+// CheckTimeout is like checkTimeout, for contexts created by RequestContext with the given
+// timeout.
+func CheckTimeout(ctx context.Context, err error, timeout time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+		return newIDPError(errorCodeTimeout,
+			"request timed out after %s, use the --timeout flag to wait longer", timeout)
+	}
+	return err
+}
+
+// waitForCluster polls the cluster till it is ready, or till it is in a state where it will
+// never be ready, and returns its latest version.
+func waitForCluster(collection *cmv1.ClustersClient, cluster *cmv1.Cluster) (*cmv1.Cluster, error) {
+	quiet.Printf("Waiting for cluster '%s' to be ready...\n", cluster.Name())
+	ctx, cancel := context.WithTimeout(context.Background(), args.waitTimeout)
+	defer cancel()
+	return c.WaitForCluster(ctx, collection, cluster.ID(), 30*time.Second, nil)
+}
+
+// getNextName returns a name like `github-2` that isn't used by any of the given identity
+// providers, incrementing the highest suffix used by the identity providers of the same type.
 func getNextName(idpType string, idps []*cmv1.IdentityProvider) string {
 	nextSuffix := 0
+	prefix := idpType + "-"
 	for _, idp := range idps {
-		if strings.Contains(idp.Name(), idpType) {
-			lastSuffix, err := strconv.Atoi(strings.Split(idp.Name(), "-")[1])
-			if err != nil {
-				continue
-			}
-			if lastSuffix >= nextSuffix {
-				nextSuffix = lastSuffix
-			}
+		if !strings.HasPrefix(idp.Name(), prefix) {
+			continue
+		}
+		lastSuffix, err := strconv.Atoi(strings.TrimPrefix(idp.Name(), prefix))
+		if err != nil {
+			continue
+		}
+		if lastSuffix >= nextSuffix {
+			nextSuffix = lastSuffix
 		}
 	}
-	return fmt.Sprintf("%s-%d", idpType, nextSuffix+1)
+	return fmt.Sprintf("%s%d", prefix, nextSuffix+1)
 }
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the errors that can be written in a format that is easy to process by
+// scripts, using the '--output=json' flag.
+
+package idp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Codes of the errors written with the '--output=json' flag. These are part of the interface
+// used by scripts, so existing codes must never be changed.
+const (
+	errorCodeGeneric              = "error"
+	errorCodeMutuallyExclusive    = "mutually_exclusive"
+	errorCodeInvalidHostname      = "invalid_hostname"
+	errorCodeInvalidMappingMethod = "invalid_mapping_method"
+	errorCodeMissingValue         = "missing_value"
+	errorCodeTimeout              = "timeout"
+	errorCodeUnreachableHostname  = "unreachable_hostname"
+	errorCodeInvalidCertificate   = "invalid_certificate"
+	errorCodeInvalidOrganization  = "invalid_github_organization"
+)
+
+var validOutputs = []string{"json"}
+
+// idpError is an error that has a code that scripts can use to decide what to do.
+type idpError struct {
+	code    string
+	message string
+}
+
+func (e *idpError) Error() string {
+	return e.message
+}
+
+// newIDPError creates an error with the given code and message.
+func newIDPError(code string, format string, a ...interface{}) error {
+	return &idpError{
+		code:    code,
+		message: fmt.Sprintf(format, a...),
+	}
+}
+
+// errorCode returns the code of the given error, or the generic code if it doesn't have one.
+func errorCode(err error) string {
+	var codeErr *idpError
+	if errors.As(err, &codeErr) {
+		return codeErr.code
+	}
+	return errorCodeGeneric
+}
+
+// writeJSONError writes the given error to the given writer as a JSON object containing the code
+// and the complete message, for example:
+//
+//	{
+//	  "code": "mutually_exclusive",
+//	  "message": "Failed to create IDP for cluster 'mycluster': GitHub IDP only allows ..."
+//	}
+func writeJSONError(writer io.Writer, err error) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{
+		Code:    errorCode(err),
+		Message: err.Error(),
+	})
+}
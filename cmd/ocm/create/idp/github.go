@@ -14,41 +14,132 @@ limitations under the License.
 package idp
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
-	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
 )
 
 func buildGithubIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
-	clientID := args.clientID
-	clientSecret := args.clientSecret
-	organizations := args.githubOrganizations
-	teams := args.githubTeams
+	clientID, clientSecret, err := getGithubCredentials()
+	if err != nil {
+		return idpBuilder, err
+	}
+	options := githubOptionsFromArgs()
+	options.mappingMethod = args.mappingMethod
+	options.clientID = clientID
+	options.clientSecret = clientSecret
+	options.hostname = args.githubHostname
+	options.organizations = args.githubOrganizations
+	options.teams = args.githubTeams
+	return buildGithubIdpWith(cluster, idpName, options)
+}
+
+// githubOptions contains the settings of a GitHub identity provider, so that it can be built from
+// values that don't come from the command line, like the entries of a manifest file.
+type githubOptions struct {
+	mappingMethod string
+	clientID      string
+	clientSecret  string
+	hostname      string
+	organizations string
+	teams         string
+
+	// Behavior of the command, that other commands building GitHub identity providers set
+	// with their own flags:
+	caFile         string
+	allowAnyUser   bool
+	checkHostname  bool
+	validateOrgs   bool
+	openBrowser    bool
+	nonInteractive bool
+}
+
+// githubOptionsFromArgs returns the options that control the behavior of the command when
+// building GitHub identity providers, as given in the command line.
+func githubOptionsFromArgs() githubOptions {
+	return githubOptions{
+		caFile:         args.caFile,
+		allowAnyUser:   args.githubAllowAnyUser,
+		checkHostname:  args.githubCheckHostname,
+		validateOrgs:   args.githubValidateOrgs,
+		openBrowser:    args.githubOpenBrowser,
+		nonInteractive: args.nonInteractive,
+	}
+}
+
+// buildGithubIdpWith builds a GitHub identity provider using the given options instead of the
+// ones from the command line.
+func buildGithubIdpWith(cluster *cmv1.Cluster, idpName string,
+	options githubOptions) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	mappingMethod, err := getMappingMethod(options.mappingMethod)
+	if err != nil {
+		return idpBuilder, err
+	}
+
+	clientID := options.clientID
+	clientSecret := options.clientSecret
+	organizations := options.organizations
+	teams := options.teams
 	teamsOrOrgs := ""
 
 	if organizations != "" && teams != "" {
-		return idpBuilder, errors.New("GitHub IDP only allows either organizations or teams, but not both")
+		return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+			"GitHub IDP only allows either organizations or teams, but not both")
+	}
+
+	if options.nonInteractive {
+		switch {
+		case clientID == "":
+			return idpBuilder, nonInteractiveError("client-id")
+		case clientSecret == "":
+			return idpBuilder, nonInteractiveError("client-secret")
+		case organizations == "" && teams == "" && !options.allowAnyUser:
+			return idpBuilder, newIDPError(errorCodeMissingValue,
+				"Either --organizations or --teams flag is required in non-interactive mode, use "+
+					"--allow-any-github-user to allow any GitHub user to log in")
+		}
+	}
+	if options.allowAnyUser && (organizations != "" || teams != "") {
+		return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+			"The --allow-any-github-user flag can't be used together with --organizations or --teams")
 	}
 
-	isInteractive := clientID == "" || clientSecret == "" || (organizations == "" && teams == "")
+	isInteractive := clientID == "" || clientSecret == "" ||
+		(organizations == "" && teams == "" && !options.allowAnyUser)
 
 	if isInteractive {
-		fmt.Println("To use GitHub as an identity provider, you must first register the application:")
+		quiet.Println("To use GitHub as an identity provider, you must first register the application:")
 
-		if organizations == "" && teams == "" {
+		if organizations == "" && teams == "" && !options.allowAnyUser {
 			prompt := &survey.Input{
 				Message: "List of GitHub organizations or teams " +
 					"that will have access to this cluster:",
 			}
-			err = survey.AskOne(prompt, &teamsOrOrgs)
+			err = askOne(prompt, &teamsOrOrgs)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a GitHub organization or team name")
+				return idpBuilder, promptError(err, "Expected a GitHub organization or team name")
+			}
+			if strings.TrimSpace(teamsOrOrgs) == "" {
+				err = confirmAnyGithubUser()
+				if err != nil {
+					return idpBuilder, err
+				}
 			}
 		}
 
@@ -65,9 +156,16 @@ func buildGithubIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 		// If a single organization was listed, use that to register the application
 		if organizations != "" && !strings.Contains(organizations, ",") {
 			registerURLBase = fmt.Sprintf("https://github.com/organizations/%s/settings/applications/new", organizations)
-		} else if teams != "" && !strings.Contains(teams, ",") {
-			teamOrg := strings.Split(teams, "/")[0]
-			registerURLBase = fmt.Sprintf("https://github.com/organizations/%s/settings/applications/new", teamOrg)
+		} else if teams != "" {
+			teamOrgs := getGithubTeamOrganizations(utils.SplitList(teams))
+			if len(teamOrgs) == 1 {
+				registerURLBase = fmt.Sprintf("https://github.com/organizations/%s/settings/applications/new",
+					teamOrgs[0])
+			} else if len(teamOrgs) > 1 {
+				quiet.Printf("* The teams belong to more than one organization (%s), so the application "+
+					"must be registered in your account instead of in an organization\n",
+					strings.Join(teamOrgs, ", "))
+			}
 		}
 
 		registerURL, err := url.Parse(registerURLBase)
@@ -77,36 +175,59 @@ func buildGithubIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 
 		// Populate fields in the GitHub registration form
 		consoleURL := cluster.Console().URL()
-		oauthURL := c.GetClusterOauthURL(cluster)
 		urlParams := url.Values{}
 		urlParams.Add("oauth_application[name]", cluster.Name())
 		urlParams.Add("oauth_application[url]", consoleURL)
-		urlParams.Add("oauth_application[callback_url]", oauthURL+"/oauth2callback/"+idpName)
+		urlParams.Add("oauth_application[callback_url]", getCallbackURL(cluster, idpName))
 
 		registerURL.RawQuery = urlParams.Encode()
 
-		fmt.Println("* Open the following URL:", registerURL.String())
-		fmt.Println("* Click on 'Register application'")
+		quiet.Println("* Open the following URL:", registerURL.String())
+		if options.openBrowser && !options.nonInteractive {
+			err = browser.OpenURL(registerURL.String())
+			if err != nil {
+				quiet.Println("  Failed to open the URL in the browser, please open it manually")
+			}
+		}
+		quiet.Println("* Click on 'Register application'")
 
 		if clientID == "" {
 			prompt := &survey.Input{
 				Message: "Copy the Client ID provided by GitHub:",
 			}
-			err = survey.AskOne(prompt, &clientID)
+			err = askOne(prompt, &clientID)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a GitHub application Client ID")
+				return idpBuilder, promptError(err, "Expected a GitHub application Client ID")
 			}
 		}
 
 		if clientSecret == "" {
-			prompt := &survey.Input{
+			prompt := &survey.Password{
 				Message: "Copy the Client Secret provided by GitHub:",
 			}
-			err = survey.AskOne(prompt, &clientSecret)
+			err = askOne(prompt, &clientSecret)
+			if err != nil {
+				return idpBuilder, promptError(err, "Expected a GitHub application Client Secret")
+			}
+		}
+	}
+
+	var organizationList, teamList []string
+	if organizations != "" {
+		organizationList = utils.SplitList(organizations)
+		for _, organization := range organizationList {
+			err = utils.ValidateGithubLogin(organization)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a GitHub application Client Secret")
+				return idpBuilder, newIDPError(errorCodeInvalidOrganization,
+					"Expected a valid GitHub organization: %v", err)
 			}
 		}
+	} else if teams != "" {
+		teamList = utils.SplitList(teams)
+		err = validateGithubTeams(teamList)
+		if err != nil {
+			return idpBuilder, err
+		}
 	}
 
 	// Create GitHub IDP
@@ -114,28 +235,373 @@ func buildGithubIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 		ClientID(clientID).
 		ClientSecret(clientSecret)
 
-	if args.githubHostname != "" {
-		_, err = url.ParseRequestURI(args.githubHostname)
+	if options.hostname != "" {
+		err = utils.ValidateHostname(options.hostname)
 		if err != nil {
-			return idpBuilder, fmt.Errorf("Expected a valid Hostname: %v", err)
+			return idpBuilder, newIDPError(errorCodeInvalidHostname,
+				"Expected a valid GitHub Enterprise hostname: %v", err)
 		}
 		// Set the hostname, if any
-		githubIDP = githubIDP.Hostname(args.githubHostname)
+		githubIDP = githubIDP.Hostname(options.hostname)
+	}
+
+	ca := ""
+	if options.caFile != "" {
+		// Public GitHub uses well known certificates, only enterprise instances need a custom CA
+		if options.hostname == "" {
+			return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+				"The --ca-file flag can only be used together with --hostname")
+		}
+		ca, err = readCAFile(options.caFile)
+		if err != nil {
+			return idpBuilder, err
+		}
+		githubIDP = githubIDP.CA(ca)
+	}
+
+	if options.checkHostname {
+		if options.hostname == "" {
+			return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+				"The --check-hostname flag can only be used together with --hostname")
+		}
+		ctx, cancel := apiContext()
+		err = checkGithubHostname(ctx, options.hostname, ca)
+		cancel()
+		err = checkTimeout(ctx, err)
+		if err != nil {
+			return idpBuilder, err
+		}
+	}
+
+	if options.validateOrgs {
+		client := newGithubClient(options.hostname, clientID, clientSecret)
+		ctx, cancel := apiContext()
+		err = validateGithubOrganizations(ctx, client, organizationList, teamList)
+		cancel()
+		err = checkTimeout(ctx, err)
+		if err != nil {
+			return idpBuilder, err
+		}
 	}
 
 	// Set organizations or teams in the IDP object
-	if organizations != "" {
-		githubIDP = githubIDP.Organizations(strings.Split(organizations, ",")...)
-	} else if teams != "" {
-		githubIDP = githubIDP.Teams(strings.Split(teams, ",")...)
+	if len(organizationList) > 0 {
+		githubIDP = githubIDP.Organizations(organizationList...)
+	} else if len(teamList) > 0 {
+		githubIDP = githubIDP.Teams(teamList...)
 	}
 
 	// Create new IDP with GitHub provider
 	idpBuilder.
 		Type("GithubIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
 		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(args.mappingMethod)).
+		MappingMethod(mappingMethod).
 		Github(githubIDP)
 
 	return
 }
+
+// createGithubIdpPair creates two GitHub identity providers, as organizations and teams can't be
+// used in the same one: the first one, with the given name, for the organizations, and the second,
+// with the '-teams' suffix, for the teams. If the second can't be created then the first is
+// deleted, so that the cluster isn't left with only one of them.
+func createGithubIdpPair(collection *cmv1.ClustersClient, cluster *cmv1.Cluster, clusterKey string,
+	idpName string, idps []*cmv1.IdentityProvider) error {
+	teamsName := idpName + "-teams"
+	if !args.force {
+		for _, idp := range idps {
+			if idp.Name() == teamsName {
+				return fmt.Errorf("An identity provider named '%s' already exists on cluster '%s'",
+					teamsName, clusterKey)
+			}
+		}
+	}
+
+	clientID, clientSecret, err := getGithubCredentials()
+	if err != nil {
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
+	}
+	options := githubOptionsFromArgs()
+	options.mappingMethod = args.mappingMethod
+	options.clientID = clientID
+	options.clientSecret = clientSecret
+	options.hostname = args.githubHostname
+	options.organizations = args.githubOrganizations
+	orgsBuilder, err := buildGithubIdpWith(cluster, idpName, options)
+	if err != nil {
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
+	}
+	orgsIdp, err := orgsBuilder.Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
+	}
+
+	// Use the same application for both, so that the credentials are requested only once:
+	options.clientID = orgsIdp.Github().ClientID()
+	options.clientSecret = orgsIdp.Github().ClientSecret()
+	options.organizations = ""
+	options.teams = args.githubTeams
+	teamsBuilder, err := buildGithubIdpWith(cluster, teamsName, options)
+	if err != nil {
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
+	}
+	teamsIdp, err := teamsBuilder.Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create IDP for cluster '%s': %w", clusterKey, err)
+	}
+
+	if args.dryRun {
+		buf := new(bytes.Buffer)
+		err = cmv1.MarshalIdentityProviderList([]*cmv1.IdentityProvider{orgsIdp, teamsIdp}, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal IDPs for cluster '%s': %v", clusterKey, err)
+		}
+		body, err := utils.RedactSecrets(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("Failed to marshal IDPs for cluster '%s': %v", clusterKey, err)
+		}
+		return dump.Pretty(os.Stdout, body)
+	}
+
+	quiet.Printf("Configuring IDPs for cluster '%s'\n", clusterKey)
+
+	idpsClient := collection.Cluster(cluster.ID()).IdentityProviders()
+	created, err := addIdentityProvider(idpsClient, orgsIdp)
+	if err != nil {
+		return fmt.Errorf("Failed to add IDP '%s' to cluster '%s': %w", idpName, clusterKey, err)
+	}
+	_, err = addIdentityProvider(idpsClient, teamsIdp)
+	if err != nil {
+		ctx, cancel := apiContext()
+		defer cancel()
+		_, rollbackErr := idpsClient.IdentityProvider(created.ID()).Delete().SendContext(ctx)
+		if rollbackErr != nil {
+			return fmt.Errorf("Failed to add IDP '%s' to cluster '%s': %v, and failed to delete "+
+				"IDP '%s' afterwards: %v", teamsName, clusterKey, err, idpName, rollbackErr)
+		}
+		return fmt.Errorf("Failed to add IDP '%s' to cluster '%s', IDP '%s' has been deleted: %w",
+			teamsName, clusterKey, idpName, err)
+	}
+
+	quiet.Printf(
+		"Identity Providers '%s' and '%s' have been created.\nYou need to ensure that there is "+
+			"a list of cluster administrators defined.\nSee 'ocm create user --help' for more "+
+			"information.\nTo login into the console, open %s and click on %s or %s.\n",
+		idpName, teamsName, cluster.Console().URL(), idpName, teamsName,
+	)
+	printCallbackURL(cluster, "github", idpName)
+	printCallbackURL(cluster, "github", teamsName)
+	return nil
+}
+
+// getGithubTeamOrganizations returns the distinct organizations of the given <org>/<team> list,
+// in the order that they first appear.
+func getGithubTeamOrganizations(teams []string) []string {
+	var organizations []string
+	seen := map[string]bool{}
+	for _, team := range teams {
+		organization := strings.Split(team, "/")[0]
+		if !seen[organization] {
+			seen[organization] = true
+			organizations = append(organizations, organization)
+		}
+	}
+	return organizations
+}
+
+// validateGithubTeams checks that all the given teams have the <org>/<team> format, and that the
+// organizations are valid GitHub logins.
+func validateGithubTeams(teams []string) error {
+	for _, team := range teams {
+		err := utils.ValidateGithubTeam(team)
+		if errors.Is(err, utils.ErrGithubTeamFormat) {
+			return fmt.Errorf("Invalid GitHub team '%s': %v", team, err)
+		}
+		if err != nil {
+			return newIDPError(errorCodeInvalidOrganization, "Invalid GitHub team '%s': %v", team, err)
+		}
+	}
+	return nil
+}
+
+// loadGithubListFiles adds the organizations and teams from the files given with the
+// '--organizations-file' and '--teams-file' flags to the ones given in the command line.
+func loadGithubListFiles() error {
+	var err error
+	args.githubOrganizations, err = appendListFile(args.githubOrganizations, args.githubOrgsFile)
+	if err != nil {
+		return err
+	}
+	args.githubTeams, err = appendListFile(args.githubTeams, args.githubTeamsFile)
+	return err
+}
+
+// appendListFile adds the values of the given file, separated by new lines or commas, to the
+// given comma separated list.
+func appendListFile(list string, file string) (string, error) {
+	if file == "" {
+		return list, nil
+	}
+	// #nosec G304
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read file '%s': %v", file, err)
+	}
+	values := utils.SplitList(list)
+	values = append(values, utils.SplitList(strings.ReplaceAll(string(data), "\n", ","))...)
+	if len(values) == 0 {
+		return "", fmt.Errorf("File '%s' doesn't contain any value", file)
+	}
+	return strings.Join(values, ","), nil
+}
+
+// githubClientSecretEnv is the environment variable that contains the client secret of the
+// GitHub application when it isn't given explicitly in the command line.
+const githubClientSecretEnv = "OCM_GITHUB_CLIENT_SECRET"
+
+// githubAppCredentials contains the fields of the response of the GitHub API that converts the
+// code obtained with the manifest flow into a GitHub App. The rest of the fields, like the private
+// key, aren't needed by the identity provider.
+type githubAppCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// getGithubCredentials returns the client identifier and secret of the GitHub application, taken
+// from the file given with the '--app-credentials-file' flag, or else from the '--client-id' flag
+// and the sources of the client secret.
+func getGithubCredentials() (clientID string, clientSecret string, err error) {
+	if args.githubAppFile == "" {
+		clientSecret, err = getGithubClientSecret()
+		return args.clientID, clientSecret, err
+	}
+	if args.clientID != "" || args.clientSecret != "" || args.clientSecretFile != "" {
+		return "", "", newIDPError(errorCodeMutuallyExclusive,
+			"The --app-credentials-file flag can't be used together with --client-id, "+
+				"--client-secret or --client-secret-file")
+	}
+	var data []byte
+	if args.githubAppFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		// #nosec G304
+		data, err = os.ReadFile(args.githubAppFile)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to read GitHub App credentials file '%s': %v",
+			args.githubAppFile, err)
+	}
+	var credentials githubAppCredentials
+	err = json.Unmarshal(data, &credentials)
+	if err != nil {
+		return "", "", fmt.Errorf("GitHub App credentials file '%s' isn't valid JSON: %v",
+			args.githubAppFile, err)
+	}
+	var missing []string
+	if credentials.ClientID == "" {
+		missing = append(missing, "'client_id'")
+	}
+	if credentials.ClientSecret == "" {
+		missing = append(missing, "'client_secret'")
+	}
+	if len(missing) > 0 {
+		return "", "", newIDPError(errorCodeMissingValue,
+			"GitHub App credentials file '%s' doesn't contain the %s of the application",
+			args.githubAppFile, strings.Join(missing, " and "))
+	}
+	return credentials.ClientID, credentials.ClientSecret, nil
+}
+
+// getGithubClientSecret returns the client secret given with the '--client-secret' flag, or else
+// the content of the file given with the '--client-secret-file' flag, or else the value of the
+// environment variable. An empty string means that it should be requested interactively.
+func getGithubClientSecret() (string, error) {
+	if args.clientSecret != "" {
+		return args.clientSecret, nil
+	}
+	if args.clientSecretFile != "" {
+		var data []byte
+		var err error
+		if args.clientSecretFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			// #nosec G304
+			data, err = os.ReadFile(args.clientSecretFile)
+		}
+		if err != nil {
+			return "", fmt.Errorf("Failed to read client secret file '%s': %v", args.clientSecretFile, err)
+		}
+		clientSecret := strings.TrimSpace(string(data))
+		if clientSecret == "" {
+			return "", fmt.Errorf("Client secret file '%s' is empty", args.clientSecretFile)
+		}
+		return clientSecret, nil
+	}
+	return os.Getenv(githubClientSecretEnv), nil
+}
+
+// confirmAnyGithubUser asks the user to confirm that an identity provider without organizations or
+// teams, that allows any GitHub user to log in, should be created.
+func confirmAnyGithubUser() error {
+	confirmed := false
+	prompt := &survey.Confirm{
+		Message: "No organizations or teams were given, so any GitHub user will be able to log " +
+			"in to the cluster. Are you sure?",
+		Default: false,
+	}
+	err := askOne(prompt, &confirmed)
+	if err != nil {
+		return promptError(err, "Expected a confirmation")
+	}
+	if !confirmed {
+		return errors.New("Expected a GitHub organization or team name")
+	}
+	return nil
+}
+
+// githubCompletionTimeout is the maximum time that the completion of organizations waits for the
+// GitHub API, so that the shell doesn't hang.
+const githubCompletionTimeout = 5 * time.Second
+
+// organizationsCompletion completes the organization that is being typed with the organizations
+// whose name starts with it, using the client credentials already given in the command line, with
+// the same precedence used to create the identity provider. This is best effort: without
+// credentials, or if the GitHub API fails, there are no candidates.
+func organizationsCompletion(cmd *cobra.Command, argv []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+
+	// The credentials can't be read from the standard input, as that would block the shell:
+	if args.clientSecretFile == "-" || args.githubAppFile == "-" {
+		return nil, directive
+	}
+	clientID, clientSecret, err := getGithubCredentials()
+	if err != nil || clientID == "" || clientSecret == "" {
+		return nil, directive
+	}
+
+	// Only the last organization of the comma separated list is completed:
+	previous := ""
+	current := toComplete
+	index := strings.LastIndex(toComplete, ",")
+	if index >= 0 {
+		previous = toComplete[:index+1]
+		current = toComplete[index+1:]
+	}
+	if current == "" {
+		return nil, directive
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubCompletionTimeout)
+	defer cancel()
+	client := newGithubClient(args.githubHostname, clientID, clientSecret)
+	organizations, err := client.searchOrganizations(ctx, current)
+	if err != nil {
+		return nil, directive
+	}
+	completions := make([]string, len(organizations))
+	for i, organization := range organizations {
+		completions[i] = previous + organization
+	}
+	return completions, directive
+}
@@ -0,0 +1,254 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to talk to the GitHub API.
+
+package idp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openshift-online/ocm-cli/pkg/debug"
+	"github.com/openshift-online/ocm-cli/pkg/keepalive"
+	"github.com/openshift-online/ocm-cli/pkg/proxy"
+)
+
+// githubClient knows how to send requests to the GitHub API, authenticated with the credentials of
+// the OAuth application that will be used by the identity provider.
+type githubClient struct {
+	apiURL       string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+}
+
+// newGithubClient creates a client for the public GitHub API, or for the API of the GitHub
+// Enterprise instance if a hostname is given.
+func newGithubClient(hostname, clientID, clientSecret string) *githubClient {
+	apiURL := "https://api.github.com"
+	if hostname != "" {
+		// The hostname may have been given as a complete URL:
+		if parsed, err := url.Parse(hostname); err == nil && parsed.Host != "" {
+			hostname = parsed.Host
+		}
+		apiURL = fmt.Sprintf("https://%s/api/v3", hostname)
+	}
+	return &githubClient{
+		apiURL:       apiURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient: &http.Client{
+			Transport: debug.WrapTransport(proxy.WrapTransport(keepalive.WrapTransport(http.DefaultTransport))),
+			Timeout:   30 * time.Second,
+		},
+	}
+}
+
+// get sends a GET request for the given API path, authenticated with the credentials of the
+// application if available.
+func (c *githubClient) get(ctx context.Context, path string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	if c.clientID != "" && c.clientSecret != "" {
+		request.SetBasicAuth(c.clientID, c.clientSecret)
+	}
+	return c.httpClient.Do(request)
+}
+
+// exists checks if the given API path exists and is visible to the application. Only a 404
+// response means that it doesn't exist: rejected credentials, rate limits and forbidden requests
+// are reported as errors, as it isn't possible to know if the object exists.
+func (c *githubClient) exists(ctx context.Context, path string) (bool, error) {
+	response, err := c.get(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+	_, err = io.Copy(io.Discard, response.Body)
+	if err != nil {
+		return false, err
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusUnauthorized:
+		return false, fmt.Errorf("GitHub rejected the client credentials, check the client " +
+			"identifier and secret")
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if response.Header.Get("X-RateLimit-Remaining") == "0" {
+			return false, fmt.Errorf("the GitHub API rate limit has been exceeded%s",
+				rateLimitReset(response))
+		}
+		return false, fmt.Errorf("GitHub forbids access to '%s', the application may not "+
+			"have permission to see it", path)
+	default:
+		return false, fmt.Errorf("unexpected status code %d from %s", response.StatusCode, c.apiURL)
+	}
+}
+
+// rateLimitReset returns the text that explains when the rate limit of the GitHub API will be
+// reset, calculated from the 'X-RateLimit-Reset' header of the response, or an empty string if the
+// header isn't present.
+func rateLimitReset(response *http.Response) string {
+	seconds, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(", it will be reset at %s", time.Unix(seconds, 0).UTC().Format(time.RFC3339))
+}
+
+// searchOrganizations returns the names of the organizations that start with the given prefix,
+// using the search API, as there is no endpoint that lists only the organizations that are
+// relevant to the application.
+func (c *githubClient) searchOrganizations(ctx context.Context, prefix string) ([]string, error) {
+	query := url.Values{}
+	query.Set("q", prefix+" in:login type:org")
+	query.Set("per_page", "100")
+	response, err := c.get(ctx, "/search/users?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", response.StatusCode, c.apiURL)
+	}
+	var result struct {
+		Items []struct {
+			Login string `json:"login"`
+		} `json:"items"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	// The search also matches logins that contain the prefix in other positions:
+	var names []string
+	for _, item := range result.Items {
+		if strings.HasPrefix(strings.ToLower(item.Login), strings.ToLower(prefix)) {
+			names = append(names, item.Login)
+		}
+	}
+	return names, nil
+}
+
+// validateGithubOrganizations checks that all the given organizations and teams exist and are
+// visible to the application, and returns an error listing the ones that aren't.
+func validateGithubOrganizations(ctx context.Context, client *githubClient,
+	organizations []string, teams []string) error {
+	var missing []string
+	for _, organization := range organizations {
+		found, err := client.exists(ctx, "/orgs/"+url.PathEscape(organization))
+		if err != nil {
+			return fmt.Errorf("Failed to check GitHub organization '%s': %v", organization, err)
+		}
+		if !found {
+			missing = append(missing, organization)
+		}
+	}
+	for _, team := range teams {
+		chunks := strings.SplitN(team, "/", 2)
+		if len(chunks) != 2 {
+			missing = append(missing, team)
+			continue
+		}
+		path := fmt.Sprintf("/orgs/%s/teams/%s", url.PathEscape(chunks[0]), url.PathEscape(chunks[1]))
+		found, err := client.exists(ctx, path)
+		if err != nil {
+			return fmt.Errorf("Failed to check GitHub team '%s': %v", team, err)
+		}
+		if !found {
+			missing = append(missing, team)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("The following GitHub organizations or teams don't exist or aren't "+
+			"visible to the application: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkGithubHostname performs a TLS handshake with the given GitHub Enterprise hostname, to check
+// that it is reachable and that its certificate is valid. If a CA bundle is given it is used
+// instead of the system certificates, like the identity provider will do. Connection and
+// certificate problems are reported with different codes, as they are fixed in different places.
+func checkGithubHostname(ctx context.Context, hostname string, ca string) error {
+	address := hostname
+	host, _, err := net.SplitHostPort(hostname)
+	if err != nil {
+		host = hostname
+		address = net.JoinHostPort(hostname, "443")
+	}
+	config := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+	if ca != "" {
+		config.RootCAs = x509.NewCertPool()
+		config.RootCAs.AppendCertsFromPEM([]byte(ca))
+	}
+	dialer := &tls.Dialer{
+		Config: config,
+	}
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err == nil {
+		return connection.Close()
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &unknownAuthorityErr):
+		message := "the certificate is signed by an unknown authority"
+		if ca == "" {
+			message += ", use the --ca-file flag to give the CA bundle"
+		}
+		return newIDPError(errorCodeInvalidCertificate,
+			"GitHub Enterprise hostname '%s' has an invalid certificate: %s", hostname, message)
+	case errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return newIDPError(errorCodeInvalidCertificate,
+			"GitHub Enterprise hostname '%s' has an invalid certificate: %v", hostname, err)
+	case errors.As(err, &dnsErr):
+		return newIDPError(errorCodeUnreachableHostname,
+			"GitHub Enterprise hostname '%s' can't be resolved: %v", hostname, err)
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return newIDPError(errorCodeUnreachableHostname,
+			"GitHub Enterprise hostname '%s' refused the connection to '%s'", hostname, address)
+	default:
+		return newIDPError(errorCodeUnreachableHostname,
+			"GitHub Enterprise hostname '%s' isn't reachable: %v", hostname, err)
+	}
+}
@@ -0,0 +1,106 @@
+package idp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateGithubOrganizations(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "Existing organization",
+			status:   http.StatusOK,
+			expected: "",
+		},
+		{
+			name:     "Missing organization",
+			status:   http.StatusNotFound,
+			expected: "don't exist or aren't visible to the application: myorg",
+		},
+		{
+			name:     "Rejected credentials",
+			status:   http.StatusUnauthorized,
+			expected: "GitHub rejected the client credentials",
+		},
+		{
+			name:   "Rate limit exceeded",
+			status: http.StatusForbidden,
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "1700000000",
+			},
+			expected: "rate limit has been exceeded, it will be reset at 2023-11-14T22:13:20Z",
+		},
+		{
+			name:     "Forbidden",
+			status:   http.StatusForbidden,
+			expected: "GitHub forbids access to '/orgs/myorg'",
+		},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range test.headers {
+					w.Header().Set(name, value)
+				}
+				w.WriteHeader(test.status)
+			},
+		))
+		client := &githubClient{
+			apiURL:     server.URL,
+			httpClient: server.Client(),
+		}
+		err := validateGithubOrganizations(context.Background(), client, []string{"myorg"}, nil)
+		server.Close()
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%s: expected no error, got '%v'", test.name, err)
+		case test.expected != "" && err == nil:
+			t.Errorf("%s: expected an error containing '%s', got nothing", test.name, test.expected)
+		case test.expected != "" && !strings.Contains(err.Error(), test.expected):
+			t.Errorf("%s: expected an error containing '%s', got '%v'", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestSearchOrganizations(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("q")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"items": [
+					{"login": "acme"},
+					{"login": "Acme-Labs"},
+					{"login": "not-acme"}
+				]
+			}`))
+		},
+	))
+	defer server.Close()
+	client := &githubClient{
+		apiURL:     server.URL,
+		httpClient: server.Client(),
+	}
+	names, err := client.searchOrganizations(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("expected no error, got '%v'", err)
+	}
+	if query != "acme in:login type:org" {
+		t.Errorf("expected the search to be scoped to the prefix, got '%s'", query)
+	}
+	if !reflect.DeepEqual(names, []string{"acme", "Acme-Labs"}) {
+		t.Errorf("expected only the organizations that start with the prefix, got %q", names)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+func buildGitlabIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	mappingMethod, err := getMappingMethod(args.mappingMethod)
+	if err != nil {
+		return idpBuilder, err
+	}
+
+	clientID := args.clientID
+	clientSecret := args.clientSecret
+	gitlabURL := args.idpURL
+
+	if args.nonInteractive {
+		switch {
+		case clientID == "":
+			return idpBuilder, nonInteractiveError("client-id")
+		case clientSecret == "":
+			return idpBuilder, nonInteractiveError("client-secret")
+		case gitlabURL == "":
+			return idpBuilder, nonInteractiveError("url")
+		}
+	}
+
+	isInteractive := clientID == "" || clientSecret == "" || gitlabURL == ""
+
+	if isInteractive {
+		if gitlabURL == "" {
+			prompt := &survey.Input{
+				Message: "URL of the GitLab instance:",
+				Default: "https://gitlab.com",
+			}
+			err = askOne(prompt, &gitlabURL)
+			if err != nil {
+				return idpBuilder, promptError(err, "Expected a valid GitLab URL")
+			}
+		}
+
+		quiet.Println("To use GitLab as an identity provider, you must first register the application:")
+		quiet.Println("* Open the following URL:", gitlabURL+"/-/profile/applications")
+		quiet.Println("* Use the following URL for the Redirect URI:",
+			getCallbackURL(cluster, idpName))
+		quiet.Println("* Select the 'openid' scope and click on 'Save application'")
+
+		if clientID == "" {
+			prompt := &survey.Input{
+				Message: "Copy the Application ID provided by GitLab:",
+			}
+			err = askOne(prompt, &clientID)
+			if err != nil {
+				return idpBuilder, promptError(err, "Expected a GitLab application ID")
+			}
+		}
+
+		if clientSecret == "" {
+			prompt := &survey.Input{
+				Message: "Copy the Secret provided by GitLab:",
+			}
+			err = askOne(prompt, &clientSecret)
+			if err != nil {
+				return idpBuilder, promptError(err, "Expected a GitLab application Secret")
+			}
+		}
+	}
+
+	parsedGitlabURL, err := url.ParseRequestURI(gitlabURL)
+	if err != nil {
+		return idpBuilder, fmt.Errorf("Expected a valid GitLab URL: %v", err)
+	}
+	if parsedGitlabURL.Scheme != "https" {
+		return idpBuilder, errors.New("Expected GitLab URL to use an https:// scheme")
+	}
+	err = utils.ValidateHostname(parsedGitlabURL.Host)
+	if err != nil {
+		return idpBuilder, newIDPError(errorCodeInvalidHostname, "Expected a valid GitLab URL: %v", err)
+	}
+
+	// Create GitLab IDP
+	gitlabIDP := cmv1.NewGitlabIdentityProvider().
+		ClientID(clientID).
+		ClientSecret(clientSecret).
+		URL(gitlabURL)
+
+	if args.caFile != "" {
+		ca, err := readCAFile(args.caFile)
+		if err != nil {
+			return idpBuilder, err
+		}
+		gitlabIDP = gitlabIDP.CA(ca)
+	}
+
+	// Create new IDP with GitLab provider
+	idpBuilder.
+		Type("GitlabIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(idpName).
+		MappingMethod(mappingMethod).
+		Gitlab(gitlabIDP)
+
+	return
+}
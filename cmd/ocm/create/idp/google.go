@@ -14,43 +14,58 @@ limitations under the License.
 package idp
 
 import (
-	"errors"
 	"fmt"
-	"net/url"
+	"strings"
 
-	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/AlecAivazis/survey/v2"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 )
 
 func buildGoogleIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	mappingMethod, err := getMappingMethod(args.mappingMethod)
+	if err != nil {
+		return idpBuilder, err
+	}
+
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 	hostedDomain := args.googleHostedDomain
 
+	if args.nonInteractive {
+		switch {
+		case clientID == "":
+			return idpBuilder, nonInteractiveError("client-id")
+		case clientSecret == "":
+			return idpBuilder, nonInteractiveError("client-secret")
+		case mappingMethod != cmv1.IdentityProviderMappingMethodLookup && hostedDomain == "":
+			return idpBuilder, nonInteractiveError("hosted-domain")
+		}
+	}
+
 	isInteractive := clientID == "" ||
 		clientSecret == "" ||
-		(args.mappingMethod != "lookup" && hostedDomain == "")
+		(mappingMethod != cmv1.IdentityProviderMappingMethodLookup && hostedDomain == "")
 
 	if isInteractive {
-		fmt.Println("To use Google as an identity provider, you must first register the application:")
+		quiet.Println("To use Google as an identity provider, you must first register the application:")
 		instructionsURL := "https://console.developers.google.com/projectcreate"
-		fmt.Println("* Open the following URL:", instructionsURL)
-		fmt.Println("* Follow the instructions to register your application")
-
-		oauthURL := c.GetClusterOauthURL(cluster)
+		quiet.Println("* Open the following URL:", instructionsURL)
+		quiet.Println("* Follow the instructions to register your application")
 
-		fmt.Println("* When creating the OAuth client ID, use the following URL for the Authorized redirect URI: ",
-			oauthURL+"/oauth2callback/"+idpName)
+		quiet.Println("* When creating the OAuth client ID, use the following URL for the Authorized redirect URI: ",
+			getCallbackURL(cluster, idpName))
 
 		if clientID == "" {
 			prompt := &survey.Input{
 				Message: "Copy the Client ID provided by Google:",
 			}
-			err = survey.AskOne(prompt, &clientID)
+			err = askOne(prompt, &clientID)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a Google application Client ID")
+				return idpBuilder, promptError(err, "Expected a Google application Client ID")
 			}
 		}
 
@@ -58,32 +73,39 @@ func buildGoogleIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "Copy the Client Secret provided by Google:",
 			}
-			err = survey.AskOne(prompt, &clientSecret)
+			err = askOne(prompt, &clientSecret)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a Google application Client Secret")
+				return idpBuilder, promptError(err, "Expected a Google application Client Secret")
 			}
 		}
 
-		if args.mappingMethod != "lookup" && hostedDomain == "" {
+		if mappingMethod != cmv1.IdentityProviderMappingMethodLookup && hostedDomain == "" {
 			prompt := &survey.Input{
 				Message: "Hosted Domain to restrict users:",
 			}
-			err = survey.AskOne(prompt, &hostedDomain)
+			err = askOne(prompt, &hostedDomain)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a valid Hosted Domain")
+				return idpBuilder, promptError(err, "Expected a valid Hosted Domain")
 			}
 		}
 	}
 
+	// OpenShift only allows Google identity providers without a hosted domain when users are
+	// mapped with the 'lookup' method, as otherwise any Google user could log in:
+	if mappingMethod != cmv1.IdentityProviderMappingMethodLookup && hostedDomain == "" {
+		return idpBuilder, fmt.Errorf("A hosted domain is required unless the mapping method is '%s'",
+			cmv1.IdentityProviderMappingMethodLookup)
+	}
+
 	// Create Google IDP
 	googleIDP := cmv1.NewGoogleIdentityProvider().
 		ClientID(clientID).
 		ClientSecret(clientSecret)
 
 	if hostedDomain != "" {
-		_, err = url.ParseRequestURI(hostedDomain)
-		if err != nil {
-			return idpBuilder, fmt.Errorf("Expected a valid Hosted Domain: %v", err)
+		errs := validation.IsDNS1123Subdomain(hostedDomain)
+		if len(errs) > 0 {
+			return idpBuilder, fmt.Errorf("Expected a valid Hosted Domain: %s", strings.Join(errs, ", "))
 		}
 		// Set the hosted domain, if any
 		googleIDP = googleIDP.HostedDomain(hostedDomain)
@@ -93,7 +115,7 @@ func buildGoogleIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 	idpBuilder.
 		Type("GoogleIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
 		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(args.mappingMethod)).
+		MappingMethod(mappingMethod).
 		Google(googleIDP)
 
 	return
@@ -14,8 +14,12 @@ limitations under the License.
 package idp
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"unicode"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
@@ -28,52 +32,221 @@ func buildHtpasswdIdp(cluster *cmv1.Cluster, idpName string) (cmv1.IdentityProvi
 	message := fmt.Sprintf("Securely store your username and password.\n" +
 		"If you lose these credentials, you will have to delete and recreate the IDP.\n")
 
-	username := args.htpasswdUsername
-	password := args.htpasswdPassword
+	mappingMethod, err := getMappingMethod(args.mappingMethod)
+	if err != nil {
+		return idpBuilder, "", err
+	}
+
+	usernames := args.htpasswdUsernames
+	passwords := args.htpasswdPasswords
+	if len(passwords) > len(usernames) {
+		return idpBuilder, "", errors.New("Each --password flag must have a corresponding --username flag")
+	}
+
+	var fileUsers []htpasswdEntry
+	if args.htpasswdFile != "" {
+		fileUsers, err = readHtpasswdFile(args.htpasswdFile)
+		if err != nil {
+			return idpBuilder, "", err
+		}
+	}
 
-	if username == "" {
+	if len(usernames) == 0 && len(fileUsers) == 0 {
+		if args.nonInteractive {
+			return idpBuilder, "", nonInteractiveError("username")
+		}
+		username := ""
 		prompt := &survey.Input{
 			Message: "Enter username:",
 		}
-		err := survey.AskOne(prompt, &username)
+		err := askOne(prompt, &username)
 		if err != nil {
-			return idpBuilder, "", errors.New("Expected a username")
+			return idpBuilder, "", promptError(err, "Expected a username")
 		}
+		usernames = []string{username}
 	}
 
-	if password == "" {
-		prompt := &survey.Password{
-			Message: "Enter password or leave empty to generate:",
+	// Users given in the command line, with a password prompt or a generated password when the
+	// password isn't given:
+	seen := map[string]bool{}
+	var users []*cmv1.HTPasswdUserBuilder
+	for i, username := range usernames {
+		if username == "" {
+			return idpBuilder, "", errors.New("Expected a username")
 		}
-		err := survey.AskOne(prompt, &password)
-		if err != nil {
-			return idpBuilder, "", errors.New("Expected a password")
+		if seen[username] {
+			return idpBuilder, "", fmt.Errorf("Username '%s' is used more than once", username)
 		}
+		seen[username] = true
+
+		password := ""
+		if i < len(passwords) {
+			password = passwords[i]
+		}
+		if password == "" && !args.nonInteractive {
+			prompt := &survey.Password{
+				Message: fmt.Sprintf("Enter password for user '%s' or leave empty to generate:", username),
+			}
+			err := askOne(prompt, &password)
+			if err != nil {
+				return idpBuilder, "", promptError(err, "Expected a password")
+			}
+		}
+		if password == "" {
+			generator, err := pwdgen.NewWithDefault()
+			if err != nil {
+				return idpBuilder, "", errors.New("Failed to initialize password generator")
+			}
+			generatedPwd, err := generator.Generate()
+			if err != nil {
+				return idpBuilder, "", errors.New("Failed to generate a password")
+			}
+			password = *generatedPwd
+			message += "You can now log in with the username '" + username + "' and the password '" +
+				password + "'.\n"
+		} else {
+			err = validateHtpasswdPassword(password)
+			if err != nil {
+				return idpBuilder, "", fmt.Errorf("Invalid password for user '%s': %v", username, err)
+			}
+		}
+		users = append(users, cmv1.NewHTPasswdUser().Username(username).Password(password))
 	}
-	if password == "" {
-		generator, err := pwdgen.NewWithDefault()
-		if err != nil {
-			return idpBuilder, "", errors.New("Failed to initialize password generator")
+
+	// Users from the htpasswd file, that already have hashed passwords:
+	for _, entry := range fileUsers {
+		if seen[entry.username] {
+			return idpBuilder, "", fmt.Errorf("Username '%s' is used more than once", entry.username)
 		}
-		generatedPwd, err := generator.Generate()
+		seen[entry.username] = true
+		users = append(users, cmv1.NewHTPasswdUser().Username(entry.username).HashedPassword(entry.hash))
+	}
+
+	// Show the users imported from the file before asking for confirmation, as a wrong file could
+	// add many unexpected users. This is displayed even in quiet mode, as otherwise the user would
+	// have to confirm without knowing what is going to be created:
+	if len(fileUsers) > 0 && !args.dryRun && !args.yes {
+		fileUsernames := make([]string, len(fileUsers))
+		for i, entry := range fileUsers {
+			fileUsernames[i] = entry.username
+		}
+		fmt.Fprintf(os.Stderr, "The following %d users from file '%s' will be created: %s\n",
+			len(fileUsers), args.htpasswdFile, strings.Join(fileUsernames, ", "))
+		if args.nonInteractive {
+			return idpBuilder, "", nonInteractiveError("yes")
+		}
+		confirmed := false
+		prompt := &survey.Confirm{
+			Message: "Create the users?",
+		}
+		err = askOne(prompt, &confirmed)
 		if err != nil {
-			return idpBuilder, "", errors.New("Failed to generate a password")
+			return idpBuilder, "", promptError(err, "Expected a confirmation")
+		}
+		if !confirmed {
+			return idpBuilder, "", errors.New("Creation of the users has been cancelled")
 		}
-		password = *generatedPwd
-		message += "You can now log in with the provided username and the password '" + password + "'.\n"
 	}
 
 	// Create HTPasswd IDP
 	htpasswdIDP := cmv1.NewHTPasswdIdentityProvider().
-		Username(username).
-		Password(password)
+		Users(cmv1.NewHTPasswdUserList().Items(users...))
 
 	// Create new IDP with HTPasswd provider
 	idpBuilder.
 		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
 		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(args.mappingMethod)).
+		MappingMethod(mappingMethod).
 		Htpasswd(htpasswdIDP)
 
 	return idpBuilder, message, nil
 }
+
+// htpasswdEntry is a user read from an htpasswd file.
+type htpasswdEntry struct {
+	username string
+	hash     string
+}
+
+// readHtpasswdFile reads the users from the given htpasswd file. Empty lines and lines starting
+// with '#' are ignored. Only bcrypt hashed passwords are supported, and they are sent as they are.
+func readHtpasswdFile(file string) ([]htpasswdEntry, error) {
+	// #nosec G304
+	content, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read htpasswd file '%s': %v", file, err)
+	}
+	defer content.Close()
+
+	var entries []htpasswdEntry
+	scanner := bufio.NewScanner(content)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		username, hash, found := strings.Cut(text, ":")
+		if !found || username == "" || hash == "" {
+			return nil, fmt.Errorf("Line %d of htpasswd file '%s' isn't valid: expected "+
+				"'username:hash'", line, file)
+		}
+		if !isBcryptHash(hash) {
+			return nil, fmt.Errorf("Line %d of htpasswd file '%s' isn't valid: password of user "+
+				"'%s' must be hashed with bcrypt", line, file, username)
+		}
+		entries = append(entries, htpasswdEntry{
+			username: username,
+			hash:     hash,
+		})
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read htpasswd file '%s': %v", file, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("Htpasswd file '%s' doesn't contain any user", file)
+	}
+	return entries, nil
+}
+
+// isBcryptHash checks if the given value looks like a password hashed with bcrypt, as generated
+// by `htpasswd -B`.
+func isBcryptHash(value string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(value, prefix) {
+			return len(value) == 60
+		}
+	}
+	return false
+}
+
+// validateHtpasswdPassword checks that the given password satisfies the complexity rules of
+// OpenShift: at least 14 ASCII characters without whitespace, containing uppercase letters,
+// lowercase letters and digits or symbols.
+func validateHtpasswdPassword(password string) error {
+	if len(password) < 14 {
+		return errors.New("password must be at least 14 characters long")
+	}
+	var hasUpper, hasLower, hasDigitOrSymbol bool
+	for _, char := range password {
+		switch {
+		case char > unicode.MaxASCII:
+			return errors.New("password must contain only ASCII characters")
+		case unicode.IsSpace(char):
+			return errors.New("password must not contain whitespace")
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		default:
+			hasDigitOrSymbol = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasDigitOrSymbol {
+		return errors.New("password must contain uppercase letters, lowercase letters, and " +
+			"digits or symbols")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package idp
+
+import (
+	"testing"
+)
+
+func TestValidateHtpasswdPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		expected string
+	}{
+		{
+			name:     "Valid password",
+			password: "Secret-Password-123",
+			expected: "",
+		},
+		{
+			name:     "Too short",
+			password: "Secret-123",
+			expected: "password must be at least 14 characters long",
+		},
+		{
+			name:     "Not ASCII",
+			password: "Sécret-Password-123",
+			expected: "password must contain only ASCII characters",
+		},
+		{
+			name:     "Whitespace",
+			password: "Secret Password 123",
+			expected: "password must not contain whitespace",
+		},
+		{
+			name:     "Missing uppercase letters",
+			password: "secret-password-123",
+			expected: "password must contain uppercase letters, lowercase letters, and digits or symbols",
+		},
+		{
+			name:     "Missing lowercase letters",
+			password: "SECRET-PASSWORD-123",
+			expected: "password must contain uppercase letters, lowercase letters, and digits or symbols",
+		},
+		{
+			name:     "Missing digits and symbols",
+			password: "SecretPasswordAbc",
+			expected: "password must contain uppercase letters, lowercase letters, and digits or symbols",
+		},
+	}
+
+	for _, test := range tests {
+		err := validateHtpasswdPassword(test.password)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%s: expected no error, got '%v'", test.name, err)
+		case test.expected != "" && err == nil:
+			t.Errorf("%s: expected error '%s', got nothing", test.name, test.expected)
+		case test.expected != "" && err.Error() != test.expected:
+			t.Errorf("%s: expected error '%s', got '%v'", test.name, test.expected, err)
+		}
+	}
+}
@@ -17,33 +17,48 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"strings"
 
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/AlecAivazis/survey/v2"
 )
 
 func buildLdapIdp(_ *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
-	ldapURL := args.ldapURL
+	mappingMethod, err := getMappingMethod(args.mappingMethod)
+	if err != nil {
+		return idpBuilder, err
+	}
+
+	ldapURL := args.idpURL
 	ldapIDs := args.ldapIDs
 
+	if args.nonInteractive {
+		switch {
+		case ldapURL == "":
+			return idpBuilder, nonInteractiveError("url")
+		case ldapIDs == "":
+			return idpBuilder, nonInteractiveError("id-attributes")
+		}
+	}
+
 	isInteractive := ldapURL == "" || ldapIDs == ""
 
 	if isInteractive {
-		fmt.Println("To use LDAP as an identity provider, you must first register the application:")
+		quiet.Println("To use LDAP as an identity provider, you must first register the application:")
 		instructionsURL := "https://docs.openshift.com/dedicated/osd_install_access_delete_cluster/" +
 			"config-identity-providers.html#config-ldap-idp_config-identity-providers"
-		fmt.Println("* Open the following URL:", instructionsURL)
-		fmt.Println("* Follow the instructions to register your application")
+		quiet.Println("* Open the following URL:", instructionsURL)
+		quiet.Println("* Follow the instructions to register your application")
 
 		if ldapURL == "" {
 			prompt := &survey.Input{
 				Message: "URL which specifies the LDAP search parameters to use:",
 			}
-			err = survey.AskOne(prompt, &ldapURL)
+			err = askOne(prompt, &ldapURL)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a valid LDAP URL")
+				return idpBuilder, promptError(err, "Expected a valid LDAP URL")
 			}
 		}
 
@@ -51,9 +66,9 @@ func buildLdapIdp(_ *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProv
 			prompt := &survey.Input{
 				Message: "List of attributes whose values should be used as the user ID:",
 			}
-			err = survey.AskOne(prompt, &ldapIDs)
+			err = askOne(prompt, &ldapIDs)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a valid comma-separated list of attributes")
+				return idpBuilder, promptError(err, "Expected a valid comma-separated list of attributes")
 			}
 		}
 	}
@@ -65,26 +80,40 @@ func buildLdapIdp(_ *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProv
 	if parsedLdapURL.Scheme != "ldap" && parsedLdapURL.Scheme != "ldaps" {
 		return idpBuilder, errors.New("Expected LDAP URL to have an ldap:// or ldaps:// scheme")
 	}
+	if args.ldapInsecure && parsedLdapURL.Scheme == "ldaps" {
+		return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+			"The --insecure flag can't be used with an ldaps:// URL")
+	}
 
-	// Create LDAP attributes
+	// Create LDAP attributes, using the default attributes for the lists that are empty
 	ldapAttributes := cmv1.NewLDAPAttributes().
-		ID(strings.Split(ldapIDs, ",")...)
-
-	if args.ldapUsernames != "" {
-		ldapAttributes = ldapAttributes.PreferredUsername(strings.Split(args.ldapUsernames, ",")...)
-	}
-	if args.ldapDisplayNames != "" {
-		ldapAttributes = ldapAttributes.Name(strings.Split(args.ldapDisplayNames, ",")...)
-	}
-	if args.ldapEmails != "" {
-		ldapAttributes = ldapAttributes.Email(strings.Split(args.ldapEmails, ",")...)
-	}
+		ID(ldapAttributeList(ldapIDs, "dn")...).
+		PreferredUsername(ldapAttributeList(args.ldapUsernames, "uid")...).
+		Name(ldapAttributeList(args.ldapDisplayNames, "cn")...).
+		Email(ldapAttributeList(args.ldapEmails, "mail")...)
 
 	// Create LDAP IDP
 	ldapIDP := cmv1.NewLDAPIdentityProvider().
 		URL(ldapURL).
+		Insecure(args.ldapInsecure).
 		Attributes(ldapAttributes)
 
+	if args.caFile != "" {
+		if args.ldapInsecure {
+			return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+				"The --ca-file flag can't be used together with --insecure")
+		}
+		ca, err := readCAFile(args.caFile)
+		if err != nil {
+			return idpBuilder, err
+		}
+		ldapIDP = ldapIDP.CA(ca)
+	}
+
+	if args.ldapBindPassword != "" && args.ldapBindDN == "" {
+		return idpBuilder, newIDPError(errorCodeMutuallyExclusive,
+			"The --bind-password flag can only be used together with --bind-dn")
+	}
 	if args.ldapBindDN != "" {
 		ldapIDP = ldapIDP.BindDN(args.ldapBindDN)
 		if args.ldapBindPassword != "" {
@@ -96,8 +125,18 @@ func buildLdapIdp(_ *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProv
 	idpBuilder.
 		Type("LDAPIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
 		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(args.mappingMethod)).
+		MappingMethod(mappingMethod).
 		LDAP(ldapIDP)
 
 	return
 }
+
+// ldapAttributeList splits the given comma separated list of attributes, returning the default
+// attribute if the list is empty.
+func ldapAttributeList(value string, defaultAttribute string) []string {
+	attributes := utils.SplitList(value)
+	if len(attributes) == 0 {
+		attributes = []string{defaultAttribute}
+	}
+	return attributes
+}
@@ -0,0 +1,362 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift-online/ocm-cli/pkg/dump"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// manifest is the content of the file given with the '--from-file' flag. It can be written in
+// YAML or JSON, and it is validated against the schema in manifest_schema.json. It contains either
+// one identity provider, for example:
+//
+//	type: github
+//	name: github-1
+//	mapping_method: claim
+//	github:
+//	  client_id: abc
+//	  client_secret: $GITHUB_CLIENT_SECRET
+//	  organizations:
+//	  - myorg
+//
+// Or a list of identity providers, that are created in the given order:
+//
+//	identity_providers:
+//	- name: github-orgs
+//	  mapping_method: claim
+//	  github:
+//	    ...
+//	- name: github-teams
+//	  mapping_method: lookup
+//	  github:
+//	    ...
+type manifest struct {
+	idpManifest       `yaml:",inline"`
+	IdentityProviders []idpManifest `yaml:"identity_providers"`
+}
+
+type idpManifest struct {
+	Type          string          `yaml:"type"`
+	Name          string          `yaml:"name"`
+	MappingMethod string          `yaml:"mapping_method"`
+	Github        *githubManifest `yaml:"github"`
+}
+
+type githubManifest struct {
+	ClientID      string   `yaml:"client_id"`
+	ClientSecret  string   `yaml:"client_secret"`
+	Hostname      string   `yaml:"hostname"`
+	Organizations []string `yaml:"organizations"`
+	Teams         []string `yaml:"teams"`
+}
+
+// manifestFlags are the flags that contain values that are also part of the manifest, so they
+// can't be used together with the '--from-file' flag.
+var manifestFlags = []string{
+	"type",
+	"name",
+	"mapping-method",
+	"client-id",
+	"client-secret",
+	"app-credentials-file",
+	"hostname",
+	"organizations",
+	"teams",
+	"organizations-file",
+	"teams-file",
+}
+
+// loadManifest checks that none of the values of the manifest is also given in the command line,
+// and then reads the manifest from the given file.
+func loadManifest(flags *pflag.FlagSet, file string) ([]idpManifest, error) {
+	for _, name := range manifestFlags {
+		if flags.Changed(name) {
+			return nil, newIDPError(errorCodeMutuallyExclusive,
+				"The --%s flag can't be used together with --from-file", name)
+		}
+	}
+	return readManifest(file)
+}
+
+// readManifest reads the manifest from the given file and returns the identity providers that it
+// contains, after checking that each of them is valid.
+func readManifest(file string) ([]idpManifest, error) {
+	// #nosec G304
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read manifest file '%s': %v", file, err)
+	}
+	var raw interface{}
+	err = yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest file '%s': %v", file, err)
+	}
+	err = validateManifest(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Manifest file '%s' isn't valid: %w", file, err)
+	}
+	var content manifest
+	err = yaml.Unmarshal(data, &content)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse manifest file '%s': %v", file, err)
+	}
+
+	entries := content.IdentityProviders
+	if len(entries) > 0 && (content.Type != "" || content.Name != "" || content.MappingMethod != "" ||
+		content.Github != nil) {
+		return nil, fmt.Errorf("Manifest file '%s' must contain either one identity provider or "+
+			"the 'identity_providers' list, but not both", file)
+	}
+	if len(entries) == 0 {
+		entries = []idpManifest{content.idpManifest}
+	}
+	for i := range entries {
+		err = checkManifestEntry(&entries[i])
+		if err != nil {
+			if len(content.IdentityProviders) > 0 {
+				return nil, fmt.Errorf("Identity provider %d of manifest file '%s' isn't valid: %w",
+					i+1, file, err)
+			}
+			return nil, fmt.Errorf("Manifest file '%s' isn't valid: %w", file, err)
+		}
+	}
+	return entries, nil
+}
+
+// checkManifestEntry checks that the given identity provider of the manifest is valid, and
+// expands the environment variables that it references.
+func checkManifestEntry(entry *idpManifest) error {
+	if entry.Type == "" && entry.Github != nil {
+		entry.Type = "github"
+	}
+	if entry.Type != "github" {
+		return errors.New("it must describe a 'github' identity provider")
+	}
+	github := entry.Github
+	if github == nil {
+		return errors.New("it doesn't contain the 'github' section")
+	}
+	if len(github.Organizations) > 0 && len(github.Teams) > 0 {
+		return newIDPError(errorCodeMutuallyExclusive,
+			"GitHub IDP only allows either organizations or teams, but not both")
+	}
+	if entry.MappingMethod == "" {
+		entry.MappingMethod = string(cmv1.IdentityProviderMappingMethodClaim)
+	}
+	_, err := getMappingMethod(entry.MappingMethod)
+	if err != nil {
+		return err
+	}
+	github.ClientSecret, err = expandManifestValue(github.ClientSecret)
+	if err != nil {
+		return fmt.Errorf("failed to expand client secret: %v", err)
+	}
+	return nil
+}
+
+// manifestGithubOptions returns the options used to build the GitHub identity provider of the
+// given entry of the manifest, with the behavior given in the options. Values missing from the
+// manifest are errors, never prompts.
+func manifestGithubOptions(entry idpManifest, options githubOptions) githubOptions {
+	options.mappingMethod = entry.MappingMethod
+	options.clientID = entry.Github.ClientID
+	options.clientSecret = entry.Github.ClientSecret
+	options.hostname = entry.Github.Hostname
+	options.organizations = strings.Join(entry.Github.Organizations, ",")
+	options.teams = strings.Join(entry.Github.Teams, ",")
+	options.nonInteractive = true
+	return options
+}
+
+// checkManifestUsers checks that the given entry of the manifest restricts the users that can
+// log in, unless that has been explicitly allowed.
+func checkManifestUsers(entry idpManifest, allowAnyUser bool) error {
+	if len(entry.Github.Organizations) == 0 && len(entry.Github.Teams) == 0 && !allowAnyUser {
+		return newIDPError(errorCodeMissingValue,
+			"Either 'organizations' or 'teams' is required, use --allow-any-github-user to "+
+				"allow any GitHub user to log in")
+	}
+	return nil
+}
+
+// ManifestIdentityProvider is one of the identity providers of a manifest file, as used by the
+// commands other than 'ocm create idp' that read the same files, like 'ocm apply idp'.
+type ManifestIdentityProvider struct {
+	entry idpManifest
+}
+
+// ReadManifest reads the manifest from the given file and returns the identity providers that it
+// contains, after checking that each of them is valid.
+func ReadManifest(file string) ([]*ManifestIdentityProvider, error) {
+	entries, err := readManifest(file)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ManifestIdentityProvider, len(entries))
+	for i, entry := range entries {
+		result[i] = &ManifestIdentityProvider{
+			entry: entry,
+		}
+	}
+	return result, nil
+}
+
+// Name returns the name of the identity provider, or an empty string if the manifest doesn't
+// contain it.
+func (m *ManifestIdentityProvider) Name() string {
+	return m.entry.Name
+}
+
+// Build builds the identity provider for the given cluster. Identity providers without
+// organizations or teams are rejected unless allowAnyUser is true, like with the
+// '--allow-any-github-user' flag.
+func (m *ManifestIdentityProvider) Build(cluster *cmv1.Cluster,
+	allowAnyUser bool) (*cmv1.IdentityProvider, error) {
+	err := checkManifestUsers(m.entry, allowAnyUser)
+	if err != nil {
+		return nil, err
+	}
+	idpBuilder, err := buildGithubIdpWith(cluster, m.entry.Name,
+		manifestGithubOptions(m.entry, githubOptions{
+			allowAnyUser: allowAnyUser,
+		}))
+	if err != nil {
+		return nil, err
+	}
+	return idpBuilder.Build()
+}
+
+// applyManifest copies the values of the given identity provider of the manifest to the command
+// line arguments, so that the builders use them exactly as if they had been given as flags.
+func applyManifest(entry idpManifest) {
+	args.idpType = entry.Type
+	args.idpName = entry.Name
+	args.mappingMethod = entry.MappingMethod
+	args.clientID = entry.Github.ClientID
+	args.clientSecret = entry.Github.ClientSecret
+	args.githubHostname = entry.Github.Hostname
+	args.githubOrganizations = strings.Join(entry.Github.Organizations, ",")
+	args.githubTeams = strings.Join(entry.Github.Teams, ",")
+
+	// Values missing from the manifest are errors, never prompts:
+	args.nonInteractive = true
+}
+
+// createFromManifest creates the identity providers of a manifest that contains more than one, in
+// the order that they are declared, using the mapping method of each of them. It stops on the
+// first failure, reporting the identity providers that were already created.
+func createFromManifest(collection *cmv1.ClustersClient, cluster *cmv1.Cluster, clusterKey string,
+	idps []*cmv1.IdentityProvider, entries []idpManifest) error {
+	// Build all the identity providers before creating any of them, so that invalid ones are
+	// detected before changing the cluster:
+	names := map[string]bool{}
+	for _, idp := range idps {
+		names[idp.Name()] = true
+	}
+	var built []*cmv1.IdentityProvider
+	for i, entry := range entries {
+		name := entry.Name
+		if name == "" {
+			existing := make([]*cmv1.IdentityProvider, 0, len(idps)+len(built))
+			existing = append(existing, idps...)
+			existing = append(existing, built...)
+			name = getNextName(entry.Type, existing)
+		}
+		if names[name] && !args.force {
+			return fmt.Errorf("An identity provider named '%s' already exists on cluster '%s'",
+				name, clusterKey)
+		}
+		names[name] = true
+		err := checkManifestUsers(entry, args.githubAllowAnyUser)
+		if err != nil {
+			return fmt.Errorf("Failed to create IDP %d for cluster '%s': %w", i+1, clusterKey, err)
+		}
+		idpBuilder, err := buildGithubIdpWith(cluster, name,
+			manifestGithubOptions(entry, githubOptionsFromArgs()))
+		if err != nil {
+			return fmt.Errorf("Failed to create IDP %d for cluster '%s': %w", i+1, clusterKey, err)
+		}
+		idp, err := idpBuilder.Build()
+		if err != nil {
+			return fmt.Errorf("Failed to create IDP %d for cluster '%s': %w", i+1, clusterKey, err)
+		}
+		built = append(built, idp)
+	}
+
+	if args.dryRun {
+		buf := new(bytes.Buffer)
+		err := cmv1.MarshalIdentityProviderList(built, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal IDPs for cluster '%s': %v", clusterKey, err)
+		}
+		body, err := utils.RedactSecrets(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("Failed to marshal IDPs for cluster '%s': %v", clusterKey, err)
+		}
+		return dump.Pretty(os.Stdout, body)
+	}
+
+	quiet.Printf("Configuring IDPs for cluster '%s'\n", clusterKey)
+
+	idpsClient := collection.Cluster(cluster.ID()).IdentityProviders()
+	var created []string
+	for _, idp := range built {
+		_, err := addIdentityProvider(idpsClient, idp)
+		if err != nil {
+			if len(created) > 0 {
+				return fmt.Errorf("Failed to add IDP '%s' to cluster '%s', the following IDPs "+
+					"were already created: %s: %w", idp.Name(), clusterKey, strings.Join(created, ", "), err)
+			}
+			return fmt.Errorf("Failed to add IDP '%s' to cluster '%s': %w", idp.Name(), clusterKey, err)
+		}
+		created = append(created, idp.Name())
+		quiet.Printf("Identity Provider '%s' has been created.\n", idp.Name())
+		PrintGithubCallbackURL(cluster, idp)
+	}
+
+	quiet.Printf(
+		"You need to ensure that there is a list of cluster administrators defined.\nSee "+
+			"'ocm create user --help' for more information.\nTo login into the console, open %s.\n",
+		cluster.Console().URL(),
+	)
+	return nil
+}
+
+// expandManifestValue replaces references to environment variables like `$VAR` or `${VAR}` with
+// their values, so that secrets don't need to be stored in the manifest file.
+func expandManifestValue(value string) (string, error) {
+	var missing string
+	result := os.Expand(value, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable '%s' isn't set", missing)
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the validation of the manifest files against the JSON schema. Only the
+// subset of JSON schema used by the manifest schema is supported: 'type', 'properties',
+// 'required', 'additionalProperties', 'items', 'enum', and '$ref' to the 'definitions' of the
+// schema.
+
+package idp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed manifest_schema.json
+var manifestSchemaData []byte
+
+// jsonSchema is the part of a JSON schema that is supported by the validator.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []string               `json:"enum"`
+}
+
+// validateManifest checks the given manifest, parsed into generic maps and slices, against the
+// embedded schema, and returns an error describing the first problem found.
+func validateManifest(content interface{}) error {
+	schema := &jsonSchema{}
+	err := json.Unmarshal(manifestSchemaData, schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse manifest schema: %v", err)
+	}
+	return schema.validate(schema, "", content)
+}
+
+func (s *jsonSchema) validate(root *jsonSchema, path string, value interface{}) error {
+	if s.Ref != "" {
+		definition, ok := root.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if !ok {
+			return fmt.Errorf("manifest schema doesn't contain '%s'", s.Ref)
+		}
+		return definition.validate(root, path, value)
+	}
+	name := path
+	if name == "" {
+		name = "manifest"
+	}
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", name)
+		}
+		for _, required := range s.Required {
+			if _, ok := object[required]; !ok {
+				return fmt.Errorf("%s is required", joinPath(path, required))
+			}
+		}
+		// Check the properties in a predictable order, so that the error is always the same:
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s isn't a supported field", joinPath(path, key))
+				}
+				continue
+			}
+			err := property.validate(root, joinPath(path, key), object[key])
+			if err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", name)
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				err := s.Items.validate(root, fmt.Sprintf("%s[%d]", path, i), item)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", name)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, text) {
+			return fmt.Errorf("%s must be one of %s", name, strings.Join(s.Enum, ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", name)
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
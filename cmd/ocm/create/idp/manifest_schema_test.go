@@ -0,0 +1,143 @@
+package idp
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{
+			name: "Single identity provider",
+			manifest: `
+type: github
+name: github-1
+mapping_method: claim
+github:
+  client_id: abc
+  client_secret: xyz
+  organizations:
+  - myorg
+`,
+			expected: "",
+		},
+		{
+			name: "List of identity providers",
+			manifest: `
+identity_providers:
+- name: github-1
+  github:
+    client_id: abc
+    client_secret: xyz
+- name: github-2
+  github:
+    client_id: def
+    client_secret: uvw
+    teams:
+    - myorg/admins
+`,
+			expected: "",
+		},
+		{
+			name:     "Not an object",
+			manifest: `- github`,
+			expected: "manifest must be an object",
+		},
+		{
+			name: "Unknown field",
+			manifest: `
+name: github-1
+clientid: abc
+`,
+			expected: "clientid isn't a supported field",
+		},
+		{
+			name: "Unknown field of the GitHub section",
+			manifest: `
+github:
+  client_id: abc
+  client_secret: xyz
+  org: myorg
+`,
+			expected: "github.org isn't a supported field",
+		},
+		{
+			name: "Name isn't a string",
+			manifest: `
+name: [github-1]
+`,
+			expected: "name must be a string",
+		},
+		{
+			name: "Organizations aren't a list",
+			manifest: `
+github:
+  client_id: abc
+  client_secret: xyz
+  organizations: myorg
+`,
+			expected: "github.organizations must be an array",
+		},
+		{
+			name: "Invalid mapping method",
+			manifest: `
+mapping_method: wrong
+`,
+			expected: "mapping_method must be one of claim, lookup, generate, add",
+		},
+		{
+			name: "Missing client secret",
+			manifest: `
+github:
+  client_id: abc
+`,
+			expected: "github.client_secret is required",
+		},
+		{
+			name: "Missing GitHub section",
+			manifest: `
+identity_providers:
+- name: github-1
+  github:
+    client_id: abc
+    client_secret: xyz
+- name: github-2
+`,
+			expected: "identity_providers[1].github is required",
+		},
+		{
+			name: "Team of a list entry isn't a string",
+			manifest: `
+identity_providers:
+- github:
+    client_id: abc
+    client_secret: xyz
+    teams:
+    - admins: true
+`,
+			expected: "identity_providers[0].github.teams[0] must be a string",
+		},
+	}
+
+	for _, test := range tests {
+		var raw interface{}
+		err := yaml.Unmarshal([]byte(test.manifest), &raw)
+		if err != nil {
+			t.Fatalf("%s: failed to parse manifest: %v", test.name, err)
+		}
+		err = validateManifest(raw)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%s: expected no error, got '%v'", test.name, err)
+		case test.expected != "" && err == nil:
+			t.Errorf("%s: expected error '%s', got nothing", test.name, test.expected)
+		case test.expected != "" && err.Error() != test.expected:
+			t.Errorf("%s: expected error '%s', got '%v'", test.name, test.expected, err)
+		}
+	}
+}
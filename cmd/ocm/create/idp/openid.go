@@ -17,45 +17,116 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
 
-	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/AlecAivazis/survey/v2"
 )
 
 func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.IdentityProviderBuilder, err error) {
+	mappingMethod, err := getMappingMethod(args.mappingMethod)
+	if err != nil {
+		return idpBuilder, err
+	}
+
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 	issuerURL := args.openidIssuerURL
 	email := args.openidEmail
 	name := args.openidName
 	username := args.openidUsername
+	groups := args.openidGroups
 	extraScopes := args.openidExtraScopes
 
+	// Use the discovery document of the provider to fill the claims and scopes that weren't
+	// given explicitly:
+	discovered := false
+	discover := func() error {
+		if !args.openidDiscover || discovered || issuerURL == "" {
+			return nil
+		}
+		discovered = true
+		// Don't send anything to an issuer that would be rejected anyhow:
+		err := validateOpenidIssuerURL(issuerURL)
+		if err != nil {
+			return err
+		}
+		ca := ""
+		if args.caFile != "" {
+			ca, err = readCAFile(args.caFile)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := apiContext()
+		defer cancel()
+		configuration, err := discoverOpenidConfiguration(ctx, issuerURL, ca)
+		err = checkTimeout(ctx, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to discover the OpenID configuration, the claims and "+
+				"scopes need to be given explicitly: %v\n", err)
+			return nil
+		}
+		if email == "" {
+			email = configuration.supportedClaim("email")
+		}
+		if name == "" {
+			name = configuration.supportedClaim("name")
+		}
+		if username == "" {
+			username = configuration.supportedClaim("preferred_username")
+		}
+		if groups == "" {
+			groups = configuration.supportedClaim("groups")
+		}
+		if extraScopes == "" {
+			extraScopes = configuration.extraScopes()
+		}
+		return nil
+	}
+	err = discover()
+	if err != nil {
+		return idpBuilder, err
+	}
+
+	if args.nonInteractive {
+		switch {
+		case clientID == "":
+			return idpBuilder, nonInteractiveError("client-id")
+		case clientSecret == "":
+			return idpBuilder, nonInteractiveError("client-secret")
+		case issuerURL == "":
+			return idpBuilder, nonInteractiveError("issuer-url")
+		case email == "" && name == "" && username == "":
+			return idpBuilder, errors.New("At least one of --email-claims, --name-claims or " +
+				"--username-claims flags is required in non-interactive mode")
+		}
+	}
+
 	isInteractive := clientID == "" || clientSecret == "" || issuerURL == "" ||
 		(email == "" && name == "" && username == "")
 
 	if isInteractive {
-		fmt.Println("To use OpenID as an identity provider, you must first register the application:")
+		quiet.Println("To use OpenID as an identity provider, you must first register the application:")
 		instructionsURL := "https://docs.openshift.com/dedicated/osd_install_access_delete_cluster/" +
 			"config-identity-providers.html#config-openid-idp_config-identity-providers"
-		fmt.Println("* Open the following URL:", instructionsURL)
-		fmt.Println("* Follow the instructions to register your application")
-
-		oauthURL := c.GetClusterOauthURL(cluster)
+		quiet.Println("* Open the following URL:", instructionsURL)
+		quiet.Println("* Follow the instructions to register your application")
 
-		fmt.Println("* When creating the OpenID, use the following URL for the Authorized redirect URI: ",
-			oauthURL+"/oauth2callback/"+idpName)
+		quiet.Println("* When creating the OpenID, use the following URL for the Authorized redirect URI: ",
+			getCallbackURL(cluster, idpName))
 
 		if clientID == "" {
 			prompt := &survey.Input{
 				Message: "Copy the Client ID provided by the OpenID Provider:",
 			}
-			err = survey.AskOne(prompt, &clientID)
+			err = askOne(prompt, &clientID)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a valid application Client ID")
+				return idpBuilder, promptError(err, "Expected a valid application Client ID")
 			}
 		}
 
@@ -63,9 +134,9 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "Copy the Client Secret provided by the OpenID Provider:",
 			}
-			err = survey.AskOne(prompt, &clientSecret)
+			err = askOne(prompt, &clientSecret)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a valid application Client Secret")
+				return idpBuilder, promptError(err, "Expected a valid application Client Secret")
 			}
 		}
 
@@ -73,9 +144,13 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "URL that the OpenID Provider asserts as the Issuer Identifier:",
 			}
-			err = survey.AskOne(prompt, &issuerURL)
+			err = askOne(prompt, &issuerURL)
+			if err != nil {
+				return idpBuilder, promptError(err, "Expected a valid OpenID Issuer URL")
+			}
+			err = discover()
 			if err != nil {
-				return idpBuilder, errors.New("Expected a valid OpenID Issuer URL")
+				return idpBuilder, err
 			}
 		}
 
@@ -83,9 +158,9 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "Claim mappings to use as the email address:",
 			}
-			err = survey.AskOne(prompt, &email)
+			err = askOne(prompt, &email)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a list of claims to use as the email address")
+				return idpBuilder, promptError(err, "Expected a list of claims to use as the email address")
 			}
 		}
 
@@ -93,9 +168,9 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "Claim mappings to use as the display name:",
 			}
-			err = survey.AskOne(prompt, &name)
+			err = askOne(prompt, &name)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a list of claims to use as the display name")
+				return idpBuilder, promptError(err, "Expected a list of claims to use as the display name")
 			}
 		}
 
@@ -103,9 +178,9 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "Claim mappings to use as the preferred username:",
 			}
-			err = survey.AskOne(prompt, &username)
+			err = askOne(prompt, &username)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a list of claims to use as the preferred username")
+				return idpBuilder, promptError(err, "Expected a list of claims to use as the preferred username")
 			}
 		}
 
@@ -113,9 +188,9 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 			prompt := &survey.Input{
 				Message: "Extra scopes to request:",
 			}
-			err = survey.AskOne(prompt, &extraScopes)
+			err = askOne(prompt, &extraScopes)
 			if err != nil {
-				return idpBuilder, errors.New("Expected a list of extra scopes to request")
+				return idpBuilder, promptError(err, "Expected a list of extra scopes to request")
 			}
 		}
 	}
@@ -124,30 +199,24 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 		return idpBuilder, errors.New("At least one claim is required: [email-claims name-claims username-claims]")
 	}
 
-	parsedIssuerURL, err := url.ParseRequestURI(issuerURL)
+	err = validateOpenidIssuerURL(issuerURL)
 	if err != nil {
-		return idpBuilder, fmt.Errorf("Expected a valid OpenID issuer URL: %v", err)
-	}
-	if parsedIssuerURL.Scheme != "https" {
-		return idpBuilder, errors.New("Expected OpenID issuer URL to use an https:// scheme")
-	}
-	if parsedIssuerURL.RawQuery != "" {
-		return idpBuilder, errors.New("OpenID issuer URL must not have query parameters")
-	}
-	if parsedIssuerURL.Fragment != "" {
-		return idpBuilder, errors.New("OpenID issuer URL must not have a fragment")
+		return idpBuilder, err
 	}
 
 	// Build OpenID Claims
 	openIDClaims := cmv1.NewOpenIDClaims()
 	if email != "" {
-		openIDClaims = openIDClaims.Email(strings.Split(email, ",")...)
+		openIDClaims = openIDClaims.Email(utils.SplitList(email)...)
 	}
 	if name != "" {
-		openIDClaims = openIDClaims.Name(strings.Split(name, ",")...)
+		openIDClaims = openIDClaims.Name(utils.SplitList(name)...)
 	}
 	if username != "" {
-		openIDClaims = openIDClaims.PreferredUsername(strings.Split(username, ",")...)
+		openIDClaims = openIDClaims.PreferredUsername(utils.SplitList(username)...)
+	}
+	if groups != "" {
+		openIDClaims = openIDClaims.Groups(utils.SplitList(groups)...)
 	}
 
 	// Create OpenID IDP
@@ -156,14 +225,42 @@ func buildOpenidIdp(cluster *cmv1.Cluster, idpName string) (idpBuilder cmv1.Iden
 		ClientSecret(clientSecret).
 		Issuer(issuerURL).
 		Claims(openIDClaims).
-		ExtraScopes(extraScopes)
+		ExtraScopes(utils.SplitList(extraScopes)...)
+
+	if args.caFile != "" {
+		ca, err := readCAFile(args.caFile)
+		if err != nil {
+			return idpBuilder, err
+		}
+		openIDIDP = openIDIDP.CA(ca)
+	}
 
 	// Create new IDP with OpenID provider
 	idpBuilder.
 		Type("OpenIDIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
 		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod(args.mappingMethod)).
+		MappingMethod(mappingMethod).
 		OpenID(openIDIDP)
 
 	return
 }
+
+// validateOpenidIssuerURL checks that the issuer URL uses the https scheme and doesn't have query
+// parameters or a fragment, as required by the OpenID specification.
+func validateOpenidIssuerURL(issuerURL string) error {
+	parsedIssuerURL, err := url.ParseRequestURI(issuerURL)
+	if err != nil {
+		return fmt.Errorf("Expected a valid OpenID issuer URL: %v", err)
+	}
+	if parsedIssuerURL.Scheme != "https" {
+		return errors.New("Expected OpenID issuer URL to use an https:// scheme")
+	}
+	if parsedIssuerURL.RawQuery != "" {
+		return errors.New("OpenID issuer URL must not have query parameters")
+	}
+	// ParseRequestURI doesn't split the fragment, so it is left in the path:
+	if strings.Contains(issuerURL, "#") {
+		return errors.New("OpenID issuer URL must not have a fragment")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to get the configuration of an OpenID provider using the
+// discovery document.
+
+package idp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/ocm-cli/pkg/debug"
+	"github.com/openshift-online/ocm-cli/pkg/keepalive"
+	"github.com/openshift-online/ocm-cli/pkg/proxy"
+)
+
+// openidConfiguration contains the fields of the OpenID provider discovery document that are used
+// to calculate the default values of the identity provider.
+type openidConfiguration struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	ScopesSupported       []string `json:"scopes_supported"`
+	ClaimsSupported       []string `json:"claims_supported"`
+}
+
+// discoverOpenidConfiguration fetches the discovery document of the given issuer. If a CA is
+// given it is used to verify the TLS certificate of the issuer.
+func discoverOpenidConfiguration(ctx context.Context, issuerURL string,
+	ca string) (*openidConfiguration, error) {
+	err := proxy.Check()
+	if err != nil {
+		return nil, err
+	}
+	err = keepalive.Check()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if ca != "" {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM([]byte(ca))
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	client := &http.Client{
+		Transport: debug.WrapTransport(proxy.WrapTransport(keepalive.WrapTransport(transport))),
+		Timeout:   30 * time.Second,
+	}
+
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to '%s' failed with status code %d", discoveryURL,
+			response.StatusCode)
+	}
+	configuration := &openidConfiguration{}
+	err = json.NewDecoder(response.Body).Decode(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %v", discoveryURL, err)
+	}
+	if strings.TrimSuffix(configuration.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return nil, fmt.Errorf("'%s' is for issuer '%s' instead of '%s'", discoveryURL,
+			configuration.Issuer, issuerURL)
+	}
+	if configuration.AuthorizationEndpoint == "" || configuration.TokenEndpoint == "" {
+		return nil, fmt.Errorf("'%s' doesn't contain the authorization and token endpoints",
+			discoveryURL)
+	}
+	return configuration, nil
+}
+
+// supportedClaim returns the given claim if it is advertised as supported by the provider, or an
+// empty string otherwise.
+func (c *openidConfiguration) supportedClaim(claim string) string {
+	for _, supported := range c.ClaimsSupported {
+		if supported == claim {
+			return claim
+		}
+	}
+	return ""
+}
+
+// extraScopes returns the scopes, besides the mandatory 'openid', that are needed to get the
+// email and profile claims, if the provider supports them.
+func (c *openidConfiguration) extraScopes() string {
+	var scopes []string
+	for _, supported := range c.ScopesSupported {
+		if supported == "email" || supported == "profile" {
+			scopes = append(scopes, supported)
+		}
+	}
+	return strings.Join(scopes, ",")
+}
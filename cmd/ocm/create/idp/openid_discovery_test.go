@@ -0,0 +1,99 @@
+package idp
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateOpenidIssuerURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "https://example.com",
+			expected: "",
+		},
+		{
+			url:      "http://example.com",
+			expected: "https:// scheme",
+		},
+		{
+			url:      "https://example.com?tenant=mytenant",
+			expected: "must not have query parameters",
+		},
+		{
+			url:      "https://example.com/#fragment",
+			expected: "must not have a fragment",
+		},
+	}
+
+	for _, test := range tests {
+		err := validateOpenidIssuerURL(test.url)
+		if test.expected == "" {
+			if err != nil {
+				t.Errorf("Unexpected error for '%s': %v", test.url, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected error containing '%s' for '%s', got: %v", test.expected, test.url, err)
+		}
+	}
+}
+
+func TestDiscoverOpenidConfiguration(t *testing.T) {
+	tests := []struct {
+		name     string
+		issuer   string
+		expected string
+	}{
+		{
+			name:     "Matching issuer",
+			expected: "",
+		},
+		{
+			name:     "Different issuer",
+			issuer:   "https://other.example.com",
+			expected: "is for issuer 'https://other.example.com'",
+		},
+	}
+
+	for _, test := range tests {
+		var server *httptest.Server
+		server = httptest.NewTLSServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				issuer := test.issuer
+				if issuer == "" {
+					issuer = server.URL
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{
+					"issuer": "%s",
+					"authorization_endpoint": "%s/authorize",
+					"token_endpoint": "%s/token"
+				}`, issuer, issuer, issuer)
+			},
+		))
+		ca := string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		}))
+		_, err := discoverOpenidConfiguration(context.Background(), server.URL, ca)
+		server.Close()
+		if test.expected == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected error containing '%s', got: %v", test.name, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to prompt for the values that aren't given in the command
+// line.
+
+package idp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"golang.org/x/term"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+)
+
+// askOne is like survey.AskOne, but it fails if nothing is answered within the time given with the
+// '--prompt-timeout' flag, so that scripts that don't expect the prompt don't hang forever. The
+// prompt is written to the standard error, like the rest of the messages for the user.
+func askOne(prompt survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	opts = append(opts, arguments.PromptStdio())
+	if args.promptTimeout <= 0 {
+		return survey.AskOne(prompt, response, opts...)
+	}
+
+	// The prompt may have put the terminal in raw mode, so save the state in order to restore it
+	// if the prompt is abandoned:
+	fd := int(os.Stdin.Fd())
+	state, err := term.GetState(fd)
+	if err != nil {
+		state = nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- survey.AskOne(prompt, response, opts...)
+	}()
+	select {
+	case err = <-done:
+		return err
+	case <-time.After(args.promptTimeout):
+		if state != nil {
+			_ = term.Restore(fd, state)
+		}
+		fmt.Fprintln(os.Stderr)
+		return newIDPError(errorCodeTimeout, "no input received within %s", args.promptTimeout)
+	}
+}
+
+// promptError returns the error that should be reported when a prompt fails: the timeout error
+// if nothing was answered in time, or else an error with the given message.
+func promptError(err error, message string) error {
+	if errorCode(err) == errorCodeTimeout {
+		return err
+	}
+	return errors.New(message)
+}
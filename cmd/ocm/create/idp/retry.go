@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to retry the creation of identity providers when the API
+// fails with transient errors.
+
+package idp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// retryInitialDelay is the time to wait before the first retry, it is doubled for each of the
+// following retries.
+const retryInitialDelay = 2 * time.Second
+
+// addIdentityProvider adds the identity provider to the cluster, retrying up to the number of
+// times given with the '--retries' flag when the API fails with a transient error. The time given
+// with the '--timeout' flag applies to each attempt, not to all of them.
+func addIdentityProvider(client *cmv1.IdentityProvidersClient,
+	idp *cmv1.IdentityProvider) (*cmv1.IdentityProvider, error) {
+	return AddIdentityProvider(client, idp, args.retries, args.timeout)
+}
+
+// AddIdentityProvider adds the identity provider to the cluster, retrying up to the given number
+// of times when the API fails with a transient error. Before each retry it checks if the previous
+// attempt created the identity provider anyway, as that would make the retry fail because the
+// name is already in use. The timeout applies to each attempt, not to all of them.
+func AddIdentityProvider(client *cmv1.IdentityProvidersClient, idp *cmv1.IdentityProvider,
+	retries int, timeout time.Duration) (*cmv1.IdentityProvider, error) {
+	delay := retryInitialDelay
+	var listErr error
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := RequestContext(timeout)
+		response, err := client.Add().Body(idp).SendContext(ctx)
+		cancel()
+		if err == nil {
+			return response.Body(), nil
+		}
+		if attempt >= retries || !isTransientError(response, err) {
+			err = CheckTimeout(ctx, err, timeout)
+			if listErr != nil {
+				// The previous attempt may have created the identity provider, so explain
+				// that, as otherwise the error would only say that the name is in use:
+				return nil, fmt.Errorf("%w, and it wasn't possible to check if a previous "+
+					"attempt created the identity provider anyway: %v", err, listErr)
+			}
+			return nil, err
+		}
+		time.Sleep(delay)
+		delay *= 2
+		var existing *cmv1.IdentityProvider
+		existing, listErr = findIdentityProvider(client, idp.Name(), timeout)
+		if listErr == nil && existing != nil {
+			return existing, nil
+		}
+	}
+}
+
+// findIdentityProvider returns the identity provider with the given name, or nil if it doesn't
+// exist.
+func findIdentityProvider(client *cmv1.IdentityProvidersClient, name string,
+	timeout time.Duration) (*cmv1.IdentityProvider, error) {
+	ctx, cancel := RequestContext(timeout)
+	defer cancel()
+	idps, err := c.ListIdentityProviders(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range idps {
+		if item.Name() == name {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// isTransientError checks if the request failed because of a server error or a network problem
+// that may not happen again. Client errors, like 4xx responses, are never transient.
+func isTransientError(response *cmv1.IdentityProvidersAddResponse, err error) bool {
+	if response != nil && response.Status() != 0 {
+		return response.Status() >= 500
+	}
+	// A request that takes longer than the time given with the '--timeout' flag isn't retried,
+	// as the next attempt would most likely take as long:
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
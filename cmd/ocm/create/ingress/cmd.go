@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -52,10 +53,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to add the ingress to (required).",
+		"Name or ID or external_id of the cluster to add the ingress to "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 
 	flags.BoolVar(
 		&args.private,
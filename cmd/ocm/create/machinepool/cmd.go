@@ -59,10 +59,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to add the machine pool to (required).",
+		"Name or ID or external_id of the cluster to add the machine pool to "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 
 	flags.StringVar(
 		&args.instanceType,
@@ -193,6 +194,11 @@ func run(cmd *cobra.Command, argv []string) error {
 		return err
 	}
 
+	err = validateReplicas(cluster.MultiAZ())
+	if err != nil {
+		return err
+	}
+
 	mpBuilder := cmv1.NewMachinePool().
 		ID(machinePoolID).
 		InstanceType(args.instanceType).
@@ -223,3 +229,30 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 	return nil
 }
+
+// validateReplicas checks the number of replicas given in the command line. Machine pools other than
+// the default one can be scaled down to zero nodes in all the providers, but in multi-zone clusters
+// the nodes are distributed evenly across the three zones.
+func validateReplicas(multiAZ bool) error {
+	if args.autoscaling.Enabled {
+		if args.autoscaling.MinReplicas < 0 {
+			return fmt.Errorf("Minimum number of replicas is 0, but --min-replicas is %d",
+				args.autoscaling.MinReplicas)
+		}
+		if args.autoscaling.MaxReplicas < args.autoscaling.MinReplicas {
+			return fmt.Errorf("--max-replicas must be greater than or equal to --min-replicas")
+		}
+		if multiAZ && (args.autoscaling.MinReplicas%3 != 0 || args.autoscaling.MaxReplicas%3 != 0) {
+			return fmt.Errorf("Multi-zone clusters require --min-replicas and --max-replicas " +
+				"to be multiples of 3")
+		}
+		return nil
+	}
+	if args.replicas < 0 {
+		return fmt.Errorf("Minimum number of replicas is 0, but --replicas is %d", args.replicas)
+	}
+	if multiAZ && args.replicas%3 != 0 {
+		return fmt.Errorf("Multi-zone clusters require --replicas to be a multiple of 3")
+	}
+	return nil
+}
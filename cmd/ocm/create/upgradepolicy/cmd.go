@@ -22,8 +22,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -51,10 +53,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID of the cluster to add the machine pool to (required).",
+		"Name or ID of the cluster to add the machine pool to "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -92,7 +95,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		Message: "Select policy type",
 		Options: []string{"manual", "automatic"},
 	}
-	err = survey.AskOne(prompt, &scheduleType)
+	err = survey.AskOne(prompt, &scheduleType, arguments.PromptStdio())
 	if err != nil {
 		return fmt.Errorf("Failed to get a policy type")
 	}
@@ -105,7 +108,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			Options: []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
 		}
 		var day string
-		err = survey.AskOne(prompt, &day)
+		err = survey.AskOne(prompt, &day, arguments.PromptStdio())
 		if err != nil {
 			return fmt.Errorf("Failed to get a valid day")
 		}
@@ -137,7 +140,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			Options: hours,
 		}
 		var hour string
-		err = survey.AskOne(prompt, &hour)
+		err = survey.AskOne(prompt, &hour, arguments.PromptStdio())
 		if err != nil {
 			return fmt.Errorf("Failed to get a valid hour")
 		}
@@ -161,7 +164,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			return fmt.Errorf("Failed to find available upgrades: %v", err)
 		}
 		if len(availableUpgrades) == 0 {
-			fmt.Println("There are no available upgrades")
+			quiet.Println("There are no available upgrades")
 			return nil
 		}
 
@@ -169,7 +172,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			Message: "Select version",
 			Options: availableUpgrades,
 		}
-		err = survey.AskOne(prompt, &version)
+		err = survey.AskOne(prompt, &version, arguments.PromptStdio())
 		if err != nil {
 			return fmt.Errorf("Failed to get a valid version to upgrade to")
 		}
@@ -177,7 +180,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			Message: "Schedule Upgrade",
 			Options: []string{"Upgrade now", "Schedule a different time"},
 		}
-		err = survey.AskOne(prompt, &upgradePreference)
+		err = survey.AskOne(prompt, &upgradePreference, arguments.PromptStdio())
 		if err != nil {
 			return fmt.Errorf("Failed to get an upgrade time preference")
 		}
@@ -216,14 +219,14 @@ func run(cmd *cobra.Command, argv []string) error {
 				Date        string
 				DesiredTime string
 			}{}
-			err = survey.Ask(validationQs, &answers)
+			err = survey.Ask(validationQs, &answers, arguments.PromptStdio())
 			if err != nil {
 				return err
 			}
 
 			desiredTime := fmt.Sprintf("%sT%s:00.000Z", answers.Date, answers.DesiredTime)
 			timestamp, _ = time.Parse(time.RFC3339, desiredTime)
-			fmt.Println(timestamp)
+			quiet.Println(timestamp)
 		}
 
 		upgradeBuilder = cmv1.NewUpgradePolicy().
@@ -245,7 +248,7 @@ func run(cmd *cobra.Command, argv []string) error {
 	if err != nil {
 		return fmt.Errorf("Failed to create upgrade policy for cluster: %v", err)
 	}
-	fmt.Println("upgrade policy successfully created")
+	quiet.Println("upgrade policy successfully created")
 
 	return nil
 }
@@ -20,8 +20,10 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 )
 
 var args struct {
@@ -47,10 +49,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to add the user to (required).",
+		"Name or ID or external_id of the cluster to add the user to "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 
 	flags.StringVar(
 		&args.group,
@@ -127,7 +130,7 @@ func run(cmd *cobra.Command, argv []string) error {
 			usersFailed = append(usersFailed, username)
 			continue
 		}
-		fmt.Printf("Added '%s' user '%s' to cluster '%s'\n", args.group, username, clusterKey)
+		quiet.Printf("Added '%s' user '%s' to cluster '%s'\n", args.group, username, clusterKey)
 	}
 
 	if failedToAddUser {
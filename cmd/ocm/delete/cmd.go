@@ -17,9 +17,14 @@ limitations under the License.
 package delete
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift-online/ocm-cli/cmd/ocm/delete/idp"
@@ -28,29 +33,62 @@ import (
 	"github.com/openshift-online/ocm-cli/cmd/ocm/delete/upgradepolicy"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/delete/user"
 	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/config"
 	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 	"github.com/openshift-online/ocm-cli/pkg/urls"
 )
 
 var args struct {
 	parameter []string
 	header    []string
+	watch     bool
+	timeout   time.Duration
+	interval  time.Duration
+	pretty    bool
+	compact   bool
 }
 
+// clusterPathRE matches the path of a cluster, so that the '--watch' flag knows what cluster to
+// wait for.
+var clusterPathRE = regexp.MustCompile(`^/api/clusters_mgmt/v1/clusters/([^/?]+)/?$`)
+
 var Cmd = &cobra.Command{
-	Use:       "delete [flags] PATH",
-	Short:     "Send a DELETE request",
-	Long:      "Send a DELETE request to the given path.",
-	RunE:      run,
-	ValidArgs: urls.Resources(),
+	Use:   "delete [flags] PATH",
+	Short: "Send a DELETE request",
+	Long:  "Send a DELETE request to the given path.",
+	Example: `  # Delete a cluster and wait till it is deprovisioned
+  ocm delete cluster 1a2b3c4d --watch`,
+	RunE:              run,
+	ValidArgsFunction: arguments.CompleteResource,
 }
 
 func init() {
 	fs := Cmd.Flags()
 	arguments.AddParameterFlag(fs, &args.parameter)
 	arguments.AddHeaderFlag(fs, &args.header)
+	arguments.AddPrettyFlags(fs, &args.pretty, &args.compact)
+	Cmd.MarkFlagsMutuallyExclusive("pretty", "compact")
+	fs.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"When deleting a cluster, wait till it doesn't exist, printing the changes of its state.",
+	)
+	fs.DurationVar(
+		&args.timeout,
+		"timeout",
+		60*time.Minute,
+		"Maximum time to wait for the cluster to be deleted, when using --watch.",
+	)
+	fs.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"Time to wait between checks of the state of the cluster, when using --watch.",
+	)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
@@ -64,6 +102,19 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Could not create URI: %v", err)
 	}
 
+	// Only clusters can be watched:
+	var clusterID string
+	if args.watch {
+		matches := clusterPathRE.FindStringSubmatch(path)
+		if matches == nil {
+			return fmt.Errorf("Option '--watch' can only be used to delete clusters, but the "+
+				"path is '%s'", path)
+		}
+		clusterID = matches[1]
+	} else if cmd.Flags().Changed("timeout") || cmd.Flags().Changed("interval") {
+		return fmt.Errorf("Options '--timeout' and '--interval' can only be used together with '--watch'")
+	}
+
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
 	if err != nil {
@@ -79,7 +130,10 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 	arguments.ApplyParameterFlag(request, args.parameter)
-	arguments.ApplyHeaderFlag(request, args.header)
+	err = arguments.ApplyHeaderFlag(request, args.header)
+	if err != nil {
+		return err
+	}
 
 	// Send the request:
 	response, err := request.Send()
@@ -89,9 +143,9 @@ func run(cmd *cobra.Command, argv []string) error {
 	status := response.Status()
 	body := response.Bytes()
 	if status < 400 {
-		err = dump.Pretty(os.Stdout, body)
+		err = dump.Document(os.Stdout, body, arguments.IsCompact(os.Stdout, args.pretty, args.compact))
 	} else {
-		err = dump.Pretty(os.Stderr, body)
+		err = dump.Document(os.Stderr, body, arguments.IsCompact(os.Stderr, args.pretty, args.compact))
 	}
 	if err != nil {
 		return fmt.Errorf("Can't print body: %v", err)
@@ -121,5 +175,29 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 
+	if args.watch {
+		return watchClusterDeletion(connection.ClustersMgmt().V1().Clusters(), clusterID)
+	}
+
+	return nil
+}
+
+// watchClusterDeletion waits till the given cluster doesn't exist, printing the changes of its
+// state.
+func watchClusterDeletion(client *cmv1.ClustersClient, clusterID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), args.timeout)
+	defer cancel()
+
+	err := c.WaitForClusterDeletion(ctx, client, clusterID, args.interval,
+		func(current *cmv1.Cluster) {
+			quiet.Printf("Cluster '%s' is %s\n", clusterID, current.State())
+		})
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("Cluster '%s' still exists after %s", clusterID, args.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to wait for cluster '%s': %v", clusterID, err)
+	}
+	quiet.Printf("Cluster '%s' has been deleted\n", clusterID)
 	return nil
 }
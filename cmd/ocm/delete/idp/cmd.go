@@ -16,24 +16,30 @@ package idp
 import (
 	"fmt"
 
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 )
 
 var args struct {
 	clusterKey string
+	yes        bool
 }
 
 var Cmd = &cobra.Command{
-	Use:     "idp --cluster={NAME|ID|EXTERNAL_ID} [flags] IDP_NAME",
+	Use:     "idp --cluster={NAME|ID|EXTERNAL_ID} [flags] {IDP_NAME|IDP_ID}",
 	Aliases: []string{"idps"},
 	Short:   "Delete cluster IDPs",
-	Long:    "Delete a specific identity provider for a cluster.",
+	Long: "Delete a specific identity provider for a cluster. The identity provider can be " +
+		"given by name or by identifier.",
 	Example: `  # Delete an identity provider named github-1
-  ocm delete idp github-1 --cluster=mycluster`,
+  ocm delete idp github-1 --cluster=mycluster
+  # Delete an identity provider named github-1 without asking for confirmation
+  ocm delete idp github-1 --cluster=mycluster --yes`,
 	RunE: run,
 }
 
@@ -45,10 +51,19 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to delete the IdP from (required).",
+		"Name or ID or external_id of the cluster to delete the IdP from "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.BoolVarP(
+		&args.yes,
+		"yes",
+		"y",
+		false,
+		"Delete the identity provider without asking for confirmation.",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -96,14 +111,25 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
 	}
 
-	var idp *cmv1.IdentityProvider
-	for _, item := range idps {
-		if item.Name() == idpName {
-			idp = item
-		}
+	idp, err := c.FindIdentityProvider(idps, idpName)
+	if err != nil {
+		return fmt.Errorf("Failed to get identity provider '%s' for cluster '%s': %v",
+			idpName, clusterKey, err)
 	}
-	if idp == nil {
-		return fmt.Errorf("Failed to get identity provider '%s' for cluster '%s'", idpName, clusterKey)
+
+	if !args.yes {
+		confirmed := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Delete identity provider '%s' (%s) on cluster '%s'?",
+				idp.Name(), idp.ID(), clusterKey),
+		}
+		err = survey.AskOne(prompt, &confirmed, arguments.PromptStdio())
+		if err != nil {
+			return fmt.Errorf("Failed to get confirmation: %v", err)
+		}
+		if !confirmed {
+			return nil
+		}
 	}
 
 	_, err = clusterCollection.
@@ -113,8 +139,9 @@ func run(cmd *cobra.Command, argv []string) error {
 		Delete().
 		Send()
 	if err != nil {
-		return fmt.Errorf("Failed to delete identity provider '%s' on cluster '%s'", idpName, clusterKey)
+		return fmt.Errorf("Failed to delete identity provider '%s' on cluster '%s': %v",
+			idpName, clusterKey, err)
 	}
-	fmt.Printf("Deleted identity provider '%s' on cluster '%s'\n", idpName, clusterKey)
+	quiet.Printf("Deleted identity provider '%s' on cluster '%s'\n", idp.Name(), clusterKey)
 	return nil
 }
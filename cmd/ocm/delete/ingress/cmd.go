@@ -20,8 +20,10 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 )
 
 var ingressKeyRE = regexp.MustCompile(`^[a-z0-9]{4,5}$`)
@@ -49,10 +51,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to delete the ingress from (required).",
+		"Name or ID or external_id of the cluster to delete the ingress from "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -129,6 +132,6 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to delete ingress '%s' on cluster '%s'", ingress.ID(), clusterKey)
 	}
 
-	fmt.Printf("Deleted ingress '%s' on cluster '%s'\n", ingressID, clusterKey)
+	quiet.Printf("Deleted ingress '%s' on cluster '%s'\n", ingressID, clusterKey)
 	return nil
 }
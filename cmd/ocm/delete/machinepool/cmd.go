@@ -18,8 +18,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 )
 
 var args struct {
@@ -44,10 +46,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to delete the machine pool from (required).",
+		"Name or ID or external_id of the cluster to delete the machine pool from "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -97,6 +100,6 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to delete machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
 	}
 
-	fmt.Printf("Deleted machine pool '%s' on cluster '%s'\n", machinePoolID, clusterKey)
+	quiet.Printf("Deleted machine pool '%s' on cluster '%s'\n", machinePoolID, clusterKey)
 	return nil
 }
@@ -15,22 +15,28 @@ package upgradepolicy
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
 var args struct {
 	clusterKey string
+	yes        bool
 }
 
 var Cmd = &cobra.Command{
 	Use:     "upgradepolicy --cluster={NAME|ID|EXTERNAL_ID} [flags] UPGRADE_POLICY_ID",
 	Aliases: []string{"upgrade-policy", "upgradepolicies", "upgrade-policies"},
 	Short:   "Delete cluster upgrade policy",
-	Long:    "Delete the upgrade policy of a cluster.",
+	Long: "Delete the upgrade policy of a cluster, cancelling the upgrade if it hasn't started " +
+		"yet. Deleting a policy whose upgrade is already in progress requires --yes.",
 	Example: `  # Delete upgrade policy from a cluster named 'mycluster'
   ocm delete upgradepolicy --cluster=mycluster <id>`,
 	RunE: run,
@@ -44,10 +50,19 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to delete the upgrade policy from (required).",
+		"Name or ID or external_id of the cluster to delete the upgrade policy from "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.BoolVarP(
+		&args.yes,
+		"yes",
+		"y",
+		false,
+		"Delete the upgrade policy even if the upgrade is already in progress.",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -87,6 +102,21 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
 	}
 
+	// The upgrade can't be stopped once it has started, so make sure that the user knows
+	// before deleting the policy:
+	state, err := c.GetUpgradePolicyState(clusterCollection, cluster.ID(), upgradePolicyID)
+	if err != nil {
+		return err
+	}
+	if state.Value() == cmv1.UpgradePolicyStateValueStarted {
+		fmt.Fprintf(os.Stderr, "Warning: the upgrade of upgrade policy '%s' on cluster '%s' is "+
+			"already in progress, deleting the policy will not stop it\n", upgradePolicyID, clusterKey)
+		if !args.yes {
+			return fmt.Errorf("Upgrade policy '%s' on cluster '%s' is in progress, use --yes to "+
+				"delete it anyway", upgradePolicyID, clusterKey)
+		}
+	}
+
 	_, err = clusterCollection.
 		Cluster(cluster.ID()).
 		UpgradePolicies().
@@ -94,9 +124,10 @@ func run(cmd *cobra.Command, argv []string) error {
 		Delete().
 		Send()
 	if err != nil {
-		return fmt.Errorf("Failed to delete upgrade policy '%s' on cluster '%s'", upgradePolicyID, clusterKey)
+		return fmt.Errorf("Failed to delete upgrade policy '%s' on cluster '%s': %v",
+			upgradePolicyID, clusterKey, err)
 	}
 
-	fmt.Printf("Deleted upgrade policy '%s' on cluster '%s'\n", upgradePolicyID, clusterKey)
+	quiet.Printf("Deleted upgrade policy '%s' on cluster '%s'\n", upgradePolicyID, clusterKey)
 	return nil
 }
@@ -18,8 +18,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 )
 
 var args struct {
@@ -45,10 +47,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to delete the user from (required).",
+		"Name or ID or external_id of the cluster to delete the user from "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 
 	flags.StringVar(
 		&args.group,
@@ -115,6 +118,6 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to delete '%s' user '%s' on cluster '%s'", args.group, username, clusterKey)
 	}
 
-	fmt.Printf("Deleted '%s' user '%s' on cluster '%s'\n", args.group, username, clusterKey)
+	quiet.Printf("Deleted '%s' user '%s' on cluster '%s'\n", args.group, username, clusterKey)
 	return nil
 }
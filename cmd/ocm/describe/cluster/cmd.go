@@ -34,6 +34,7 @@ import (
 var args struct {
 	json   bool
 	output bool
+	wide   bool
 }
 
 var Cmd = &cobra.Command{
@@ -58,6 +59,13 @@ func init() {
 		false,
 		"Output the entire JSON structure",
 	)
+	flags.BoolVar(
+		&args.wide,
+		"wide",
+		false,
+		"Add to the description the details of the nodes and the network, like the machine "+
+			"types, availability zones and CIDRs.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -118,7 +126,6 @@ func run(cmd *cobra.Command, argv []string) error {
 	if args.json {
 		// Buffer for pretty output:
 		buf := new(bytes.Buffer)
-		fmt.Println()
 
 		// Convert cluster to JSON and dump to encoder:
 		err = cmv1.MarshalCluster(cluster, buf)
@@ -131,6 +138,11 @@ func run(cmd *cobra.Command, argv []string) error {
 			return fmt.Errorf("Can't print body: %v", err)
 		}
 
+	} else if args.wide {
+		err = clusterpkg.PrintClusterWideDescription(connection, cluster)
+		if err != nil {
+			return err
+		}
 	} else {
 		err = clusterpkg.PrintClusterDescription(connection, cluster)
 		if err != nil {
@@ -15,6 +15,7 @@ package describe
 
 import (
 	"github.com/openshift-online/ocm-cli/cmd/ocm/describe/cluster"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/describe/idp"
 	"github.com/spf13/cobra"
 )
 
@@ -26,4 +27,5 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(idp.Cmd)
 }
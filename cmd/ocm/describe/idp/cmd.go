@@ -0,0 +1,299 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/output"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var args struct {
+	clusterKey string
+	output     string
+}
+
+var validOutputs = append([]string{"json", "yaml"}, output.TemplateFormats...)
+
+var Cmd = &cobra.Command{
+	Use:     "idp --cluster={NAME|ID|EXTERNAL_ID} [flags] {IDP_NAME|IDP_ID}",
+	Aliases: []string{"idps"},
+	Short:   "Show details of a cluster IDP",
+	Long: "Show the complete configuration of an identity provider of a cluster. Secrets, like " +
+		"client secrets and passwords, are never displayed.",
+	Example: `  # Describe the identity provider named github-1
+  ocm describe idp github-1 --cluster=mycluster
+  # Describe the identity provider named github-1 in YAML format
+  ocm describe idp github-1 --cluster=mycluster --output=yaml`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID or external_id of the cluster the IdP belongs to "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, instead of the text description. Options are %s.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	// Check command line arguments:
+	if len(argv) != 1 || argv[0] == "" {
+		return fmt.Errorf(
+			"Expected exactly one command line parameters containing the name " +
+				"of the Identity provider.",
+		)
+	}
+	idpName := argv[0]
+
+	var tmpl *output.Template
+	if output.IsTemplate(args.output) {
+		var err error
+		tmpl, err = output.NewTemplate(args.output)
+		if err != nil {
+			return err
+		}
+	} else if args.output != "" && args.output != "json" && args.output != "yaml" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Get the client for the cluster management api
+	clusterCollection := connection.ClustersMgmt().V1().Clusters()
+
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	idps, err := c.GetIdentityProviders(clusterCollection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+	}
+
+	idp, err := c.FindIdentityProvider(idps, idpName)
+	if err != nil {
+		return fmt.Errorf("Failed to get identity provider '%s' for cluster '%s': %v",
+			idpName, clusterKey, err)
+	}
+
+	if args.output != "" {
+		return printStructured(idp, args.output, tmpl)
+	}
+	return printDescription(cluster, idp)
+}
+
+// printStructured writes the identity provider in JSON or YAML format, or using the given template,
+// with the secrets redacted.
+func printStructured(idp *cmv1.IdentityProvider, format string, tmpl *output.Template) error {
+	idp, err := redactClientIDs(idp)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity provider: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	err = cmv1.MarshalIdentityProvider(idp, buf)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity provider: %v", err)
+	}
+	body, err := utils.RedactSecrets(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity provider: %v", err)
+	}
+	if tmpl != nil {
+		return tmpl.Execute(os.Stdout, body)
+	}
+	if format == "json" {
+		return dump.Pretty(os.Stdout, body)
+	}
+	var data interface{}
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity provider: %v", err)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	err = encoder.Encode(data)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity provider: %v", err)
+	}
+	return encoder.Close()
+}
+
+// printDescription writes the human readable description of the identity provider.
+func printDescription(cluster *cmv1.Cluster, idp *cmv1.IdentityProvider) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	field := func(name string, value interface{}) {
+		fmt.Fprintf(writer, "%s:\t%v\n", name, value)
+	}
+	list := func(name string, values []string) {
+		if len(values) > 0 {
+			field(name, strings.Join(values, ", "))
+		}
+	}
+	secret := func(name string, value string) {
+		if value != "" {
+			field(name, utils.RedactedValue)
+		}
+	}
+
+	field("ID", idp.ID())
+	field("Name", idp.Name())
+	field("Type", idp.Type())
+	field("Mapping method", idp.MappingMethod())
+
+	switch idp.Type() {
+	case "GithubIdentityProvider":
+		github := idp.Github()
+		field("Client ID", redactClientID(github.ClientID()))
+		secret("Client secret", github.ClientSecret())
+		if github.Hostname() != "" {
+			field("Hostname", github.Hostname())
+		}
+		list("Organizations", github.Organizations())
+		list("Teams", github.Teams())
+	case "GitlabIdentityProvider":
+		gitlab := idp.Gitlab()
+		field("URL", gitlab.URL())
+		field("Client ID", redactClientID(gitlab.ClientID()))
+		secret("Client secret", gitlab.ClientSecret())
+	case "GoogleIdentityProvider":
+		google := idp.Google()
+		field("Client ID", redactClientID(google.ClientID()))
+		secret("Client secret", google.ClientSecret())
+		if google.HostedDomain() != "" {
+			field("Hosted domain", google.HostedDomain())
+		}
+	case "LDAPIdentityProvider":
+		ldap := idp.LDAP()
+		field("URL", ldap.URL())
+		field("Insecure", ldap.Insecure())
+		if ldap.BindDN() != "" {
+			field("Bind DN", ldap.BindDN())
+		}
+		secret("Bind password", ldap.BindPassword())
+		list("ID attributes", ldap.Attributes().ID())
+		list("Username attributes", ldap.Attributes().PreferredUsername())
+		list("Name attributes", ldap.Attributes().Name())
+		list("Email attributes", ldap.Attributes().Email())
+	case "OpenIDIdentityProvider":
+		openID := idp.OpenID()
+		field("Issuer URL", openID.Issuer())
+		field("Client ID", redactClientID(openID.ClientID()))
+		secret("Client secret", openID.ClientSecret())
+		list("Email claims", openID.Claims().Email())
+		list("Name claims", openID.Claims().Name())
+		list("Username claims", openID.Claims().PreferredUsername())
+		list("Groups claims", openID.Claims().Groups())
+		list("Extra scopes", openID.ExtraScopes())
+	case "HTPasswdIdentityProvider":
+		var usernames []string
+		for _, user := range idp.Htpasswd().Users().Slice() {
+			usernames = append(usernames, user.Username())
+		}
+		if idp.Htpasswd().Username() != "" {
+			usernames = append(usernames, idp.Htpasswd().Username())
+		}
+		list("Users", usernames)
+	}
+	field("Callback URL", c.GetClusterOauthURL(cluster)+"/oauth2callback/"+idp.Name())
+
+	return writer.Flush()
+}
+
+// redactClientID hides most of the client identifier, keeping only the last characters so that
+// it can be compared to the one of the registered application.
+func redactClientID(clientID string) string {
+	const visible = 4
+	if len(clientID) <= visible {
+		return strings.Repeat("*", len(clientID))
+	}
+	return strings.Repeat("*", len(clientID)-visible) + clientID[len(clientID)-visible:]
+}
+
+// redactClientIDs returns a copy of the identity provider where the client identifier is hidden
+// in the same way as in the human readable description.
+func redactClientIDs(idp *cmv1.IdentityProvider) (*cmv1.IdentityProvider, error) {
+	builder := cmv1.NewIdentityProvider().Copy(idp)
+	if github, ok := idp.GetGithub(); ok {
+		if clientID, ok := github.GetClientID(); ok {
+			builder.Github(cmv1.NewGithubIdentityProvider().Copy(github).
+				ClientID(redactClientID(clientID)))
+		}
+	}
+	if gitlab, ok := idp.GetGitlab(); ok {
+		if clientID, ok := gitlab.GetClientID(); ok {
+			builder.Gitlab(cmv1.NewGitlabIdentityProvider().Copy(gitlab).
+				ClientID(redactClientID(clientID)))
+		}
+	}
+	if google, ok := idp.GetGoogle(); ok {
+		if clientID, ok := google.GetClientID(); ok {
+			builder.Google(cmv1.NewGoogleIdentityProvider().Copy(google).
+				ClientID(redactClientID(clientID)))
+		}
+	}
+	if openID, ok := idp.GetOpenID(); ok {
+		if clientID, ok := openID.GetClientID(); ok {
+			builder.OpenID(cmv1.NewOpenIDIdentityProvider().Copy(openID).
+				ClientID(redactClientID(clientID)))
+		}
+	}
+	return builder.Build()
+}
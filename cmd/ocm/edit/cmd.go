@@ -15,6 +15,7 @@ package edit
 
 import (
 	"github.com/openshift-online/ocm-cli/cmd/ocm/edit/cluster"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/edit/idp"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/edit/ingress"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/edit/machinepool"
 	"github.com/spf13/cobra"
@@ -30,5 +31,6 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(ingress.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
 }
@@ -0,0 +1,354 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var args struct {
+	clusterKey string
+
+	clientID      string
+	clientSecret  string
+	mappingMethod string
+
+	// GitHub
+	githubHostname      string
+	githubOrganizations string
+	githubTeams         string
+}
+
+const (
+	clientIDFlag      = "client-id"
+	clientSecretFlag  = "client-secret"
+	mappingMethodFlag = "mapping-method"
+	hostnameFlag      = "hostname"
+	organizationsFlag = "organizations"
+	teamsFlag         = "teams"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "idp --cluster={NAME|ID|EXTERNAL_ID} [flags] {IDP_NAME|IDP_ID}",
+	Aliases: []string{"idps"},
+	Short:   "Edit a cluster IDP",
+	Long: "Edit an identity provider of a cluster. Only the values given in the command line " +
+		"are changed, the rest of the configuration is preserved. When no value is given and " +
+		"the command runs in a terminal, a menu is displayed to select the values to change.",
+	Example: `  # Rotate the client secret of the GitHub identity provider named github-1
+  ocm edit idp github-1 --cluster=mycluster --client-secret=xyz
+  # Replace the organizations allowed to log in with the GitHub identity provider
+  ocm edit idp github-1 --cluster=mycluster --organizations=myorg,otherorg
+  # Select the values to change from a menu
+  ocm edit idp github-1 --cluster=mycluster`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID or external_id of the cluster the IdP belongs to "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.StringVar(
+		&args.mappingMethod,
+		mappingMethodFlag,
+		"",
+		fmt.Sprintf("Specifies how new identities are mapped to users when they log in. "+
+			"Options are %s", c.MappingMethods),
+	)
+	Cmd.RegisterFlagCompletionFunc(mappingMethodFlag, arguments.CompleteMappingMethod)
+	flags.StringVar(
+		&args.clientID,
+		clientIDFlag,
+		"",
+		"Client ID from the registered application.",
+	)
+	flags.StringVar(
+		&args.clientSecret,
+		clientSecretFlag,
+		"",
+		"Client Secret from the registered application.\n",
+	)
+
+	// GitHub
+	flags.StringVar(
+		&args.githubHostname,
+		hostnameFlag,
+		"",
+		"GitHub: Optional domain to use with a hosted instance of GitHub Enterprise.",
+	)
+	flags.StringVar(
+		&args.githubOrganizations,
+		organizationsFlag,
+		"",
+		"GitHub: Only users that are members of at least one of the listed organizations will be allowed to log in.",
+	)
+	flags.StringVar(
+		&args.githubTeams,
+		teamsFlag,
+		"",
+		"GitHub: Only users that are members of at least one of the listed teams will be allowed to log in. "+
+			"The format is <org>/<team>.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+
+	// Check command line arguments:
+	if len(argv) != 1 || argv[0] == "" {
+		return fmt.Errorf(
+			"Expected exactly one command line parameters containing the name " +
+				"of the Identity provider.",
+		)
+	}
+	idpName := argv[0]
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Get the client for the cluster management api
+	clusterCollection := connection.ClustersMgmt().V1().Clusters()
+
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return fmt.Errorf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	idps, err := c.GetIdentityProviders(clusterCollection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+	}
+
+	idp, err := c.FindIdentityProvider(idps, idpName)
+	if err != nil {
+		return fmt.Errorf("Failed to get identity provider '%s' for cluster '%s': %v",
+			idpName, clusterKey, err)
+	}
+
+	// Ask for the values to change if none was given in the command line:
+	if !anyChanged(cmd.Flags()) && isInteractive() {
+		err = askFields(cmd.Flags(), idp)
+		if err != nil {
+			return fmt.Errorf("Failed to edit identity provider '%s' for cluster '%s': %v",
+				idpName, clusterKey, err)
+		}
+	}
+
+	idpBuilder, err := buildPatch(cmd.Flags(), idp)
+	if err != nil {
+		return fmt.Errorf("Failed to edit identity provider '%s' for cluster '%s': %v",
+			idpName, clusterKey, err)
+	}
+
+	patch, err := idpBuilder.Build()
+	if err != nil {
+		return fmt.Errorf("Failed to edit identity provider '%s' for cluster '%s': %v",
+			idpName, clusterKey, err)
+	}
+
+	_, err = clusterCollection.
+		Cluster(cluster.ID()).
+		IdentityProviders().
+		IdentityProvider(idp.ID()).
+		Update().
+		Body(patch).
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to edit identity provider '%s' for cluster '%s': %v",
+			idpName, clusterKey, err)
+	}
+	quiet.Printf("Updated identity provider '%s' on cluster '%s'\n", idp.Name(), clusterKey)
+	return nil
+}
+
+// editFlags are the flags that change values of the identity provider.
+var editFlags = []string{
+	clientIDFlag, clientSecretFlag, mappingMethodFlag, hostnameFlag, organizationsFlag, teamsFlag,
+}
+
+// anyChanged checks if any of the values of the identity provider was given in the command line.
+func anyChanged(flags *pflag.FlagSet) bool {
+	for _, name := range editFlags {
+		if flags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPatch creates the body of the request that updates the given identity provider, containing
+// only the values that were explicitly given in the command line.
+func buildPatch(flags *pflag.FlagSet, idp *cmv1.IdentityProvider) (*cmv1.IdentityProviderBuilder, error) {
+	if !anyChanged(flags) {
+		return nil, errors.New("Nothing to edit, at least one value must be changed")
+	}
+
+	idpBuilder := cmv1.NewIdentityProvider().
+		Type(idp.Type())
+
+	if flags.Changed(mappingMethodFlag) {
+		mappingMethod, err := c.ParseMappingMethod(args.mappingMethod)
+		if err != nil {
+			return nil, err
+		}
+		idpBuilder = idpBuilder.MappingMethod(mappingMethod)
+	}
+
+	githubChanged := flags.Changed(hostnameFlag) || flags.Changed(organizationsFlag) ||
+		flags.Changed(teamsFlag)
+	if idp.Type() != "GithubIdentityProvider" && githubChanged {
+		return nil, errors.New("The --hostname, --organizations and --teams flags can only be " +
+			"used with GitHub identity providers")
+	}
+
+	switch idp.Type() {
+	case "GithubIdentityProvider":
+		githubIDP, err := buildGithubPatch(flags, idp.Github())
+		if err != nil {
+			return nil, err
+		}
+		idpBuilder = idpBuilder.Github(githubIDP)
+	case "GoogleIdentityProvider":
+		googleIDP := cmv1.NewGoogleIdentityProvider()
+		if flags.Changed(clientIDFlag) {
+			googleIDP = googleIDP.ClientID(args.clientID)
+		}
+		if flags.Changed(clientSecretFlag) {
+			googleIDP = googleIDP.ClientSecret(args.clientSecret)
+		}
+		idpBuilder = idpBuilder.Google(googleIDP)
+	case "OpenIDIdentityProvider":
+		openIDIDP := cmv1.NewOpenIDIdentityProvider()
+		if flags.Changed(clientIDFlag) {
+			openIDIDP = openIDIDP.ClientID(args.clientID)
+		}
+		if flags.Changed(clientSecretFlag) {
+			openIDIDP = openIDIDP.ClientSecret(args.clientSecret)
+		}
+		idpBuilder = idpBuilder.OpenID(openIDIDP)
+	default:
+		if flags.Changed(clientIDFlag) || flags.Changed(clientSecretFlag) {
+			return nil, fmt.Errorf("Identity providers of type '%s' don't have client credentials",
+				idp.Type())
+		}
+	}
+
+	return idpBuilder, nil
+}
+
+// buildGithubPatch creates the GitHub part of the update request. Organizations and teams are
+// preserved unless they are explicitly replaced.
+func buildGithubPatch(flags *pflag.FlagSet,
+	current *cmv1.GithubIdentityProvider) (*cmv1.GithubIdentityProviderBuilder, error) {
+	githubIDP := cmv1.NewGithubIdentityProvider()
+
+	if flags.Changed(clientIDFlag) {
+		githubIDP = githubIDP.ClientID(args.clientID)
+	}
+	if flags.Changed(clientSecretFlag) {
+		githubIDP = githubIDP.ClientSecret(args.clientSecret)
+	}
+	if flags.Changed(hostnameFlag) {
+		if args.githubHostname != "" {
+			err := utils.ValidateHostname(args.githubHostname)
+			if err != nil {
+				return nil, fmt.Errorf("Expected a valid GitHub Enterprise hostname: %v", err)
+			}
+		}
+		githubIDP = githubIDP.Hostname(args.githubHostname)
+	}
+
+	// Organizations and teams can't be used together, and switching from one to the other
+	// isn't allowed either:
+	organizationsChanged := flags.Changed(organizationsFlag)
+	teamsChanged := flags.Changed(teamsFlag)
+	hasOrganizations := len(current.Organizations()) > 0
+	hasTeams := len(current.Teams()) > 0
+	if (organizationsChanged && teamsChanged) ||
+		(organizationsChanged && hasTeams) ||
+		(teamsChanged && hasOrganizations) {
+		return nil, errors.New("GitHub IDP only allows either organizations or teams, but not both")
+	}
+	if organizationsChanged {
+		organizations := utils.SplitList(args.githubOrganizations)
+		if len(organizations) == 0 {
+			return nil, errors.New("Expected at least one GitHub organization")
+		}
+		for _, organization := range organizations {
+			err := utils.ValidateGithubLogin(organization)
+			if err != nil {
+				return nil, fmt.Errorf("Expected a valid GitHub organization: %v", err)
+			}
+		}
+		githubIDP = githubIDP.Organizations(organizations...)
+	} else if hasOrganizations {
+		githubIDP = githubIDP.Organizations(current.Organizations()...)
+	}
+	if teamsChanged {
+		teams := utils.SplitList(args.githubTeams)
+		if len(teams) == 0 {
+			return nil, errors.New("Expected at least one GitHub team")
+		}
+		for _, team := range teams {
+			err := utils.ValidateGithubTeam(team)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid GitHub team '%s': %v", team, err)
+			}
+		}
+		githubIDP = githubIDP.Teams(teams...)
+	} else if hasTeams {
+		githubIDP = githubIDP.Teams(current.Teams()...)
+	}
+
+	return githubIDP, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+  http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to select interactively the values of the identity
+// provider that are changed, when none is given in the command line.
+
+package idp
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/output"
+)
+
+// fieldLabels contains the text displayed in the menu for each of the flags that can be changed.
+var fieldLabels = map[string]string{
+	clientIDFlag:      "Client ID",
+	clientSecretFlag:  "Client secret",
+	mappingMethodFlag: "Mapping method",
+	hostnameFlag:      "GitHub Enterprise hostname",
+	organizationsFlag: "GitHub organizations",
+	teamsFlag:         "GitHub teams",
+}
+
+// isInteractive checks if the command can ask the user for the values to change.
+func isInteractive() bool {
+	return output.IsTerminal(os.Stdin) && output.IsTerminal(os.Stdout)
+}
+
+// editableFields returns the flags that can be changed for the given identity provider, in the
+// order that they are displayed in the menu. Organizations and teams can't be switched, so only
+// the one that is in use is offered.
+func editableFields(idp *cmv1.IdentityProvider) []string {
+	switch idp.Type() {
+	case "GithubIdentityProvider":
+		fields := []string{clientIDFlag, clientSecretFlag, mappingMethodFlag, hostnameFlag}
+		github := idp.Github()
+		if len(github.Teams()) == 0 {
+			fields = append(fields, organizationsFlag)
+		}
+		if len(github.Organizations()) == 0 {
+			fields = append(fields, teamsFlag)
+		}
+		return fields
+	case "GoogleIdentityProvider", "OpenIDIdentityProvider":
+		return []string{clientIDFlag, clientSecretFlag, mappingMethodFlag}
+	default:
+		return []string{mappingMethodFlag}
+	}
+}
+
+// askFields displays a menu with the fields of the identity provider that can be changed, and then
+// asks only for the new values of the selected ones. The answers are stored in the flags, so that
+// the patch is built as if they had been given in the command line.
+func askFields(flags *pflag.FlagSet, idp *cmv1.IdentityProvider) error {
+	fields := editableFields(idp)
+	options := make([]string, len(fields))
+	for i, field := range fields {
+		options[i] = fieldLabels[field]
+	}
+	var selected []string
+	err := survey.AskOne(
+		&survey.MultiSelect{
+			Message: "Values to change:",
+			Options: options,
+		},
+		&selected,
+		arguments.PromptStdio(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return errors.New("Nothing to edit, at least one value must be changed")
+	}
+
+	current := currentValues(idp)
+	for _, field := range fields {
+		if !contains(selected, fieldLabels[field]) {
+			continue
+		}
+		var prompt survey.Prompt
+		switch field {
+		case clientSecretFlag:
+			prompt = &survey.Password{
+				Message: fieldLabels[field] + ":",
+			}
+		case mappingMethodFlag:
+			method := current[field]
+			if method == "" {
+				method = string(cmv1.IdentityProviderMappingMethodClaim)
+			}
+			prompt = &survey.Select{
+				Message: fieldLabels[field] + ":",
+				Options: c.MappingMethods,
+				Default: method,
+			}
+		default:
+			prompt = &survey.Input{
+				Message: fieldLabels[field] + ":",
+				Default: current[field],
+			}
+		}
+		var value string
+		err = survey.AskOne(prompt, &value, arguments.PromptStdio())
+		if err != nil {
+			return err
+		}
+		err = flags.Set(field, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentValues returns the values of the identity provider that are used as the defaults of the
+// prompts. The client secret isn't returned by the API, so it has no default.
+func currentValues(idp *cmv1.IdentityProvider) map[string]string {
+	values := map[string]string{
+		mappingMethodFlag: string(idp.MappingMethod()),
+	}
+	switch idp.Type() {
+	case "GithubIdentityProvider":
+		github := idp.Github()
+		values[clientIDFlag] = github.ClientID()
+		values[hostnameFlag] = github.Hostname()
+		values[organizationsFlag] = strings.Join(github.Organizations(), ",")
+		values[teamsFlag] = strings.Join(github.Teams(), ",")
+	case "GoogleIdentityProvider":
+		values[clientIDFlag] = idp.Google().ClientID()
+	case "OpenIDIdentityProvider":
+		values[clientIDFlag] = idp.OpenID().ClientID()
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
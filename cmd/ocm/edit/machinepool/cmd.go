@@ -36,11 +36,13 @@ var Cmd = &cobra.Command{
 	Use:     "machinepool --cluster={NAME|ID|EXTERNAL_ID} [flags] MACHINE_POOL_ID",
 	Aliases: []string{"machine-pool"},
 	Short:   "Edit a cluster machine pool",
-	Long:    "Edit a machine pool size.",
+	Long: "Edit a machine pool size. The size is either a fixed number of replicas, given with " +
+		"--replicas, or the bounds used by the autoscaler, given with --min-replicas and " +
+		"--max-replicas, but not both.",
 	Example: `  #  Update the number of replicas for machine pool with ID 'a1b2'
   ocm edit machinepool --replicas=3 --cluster=mycluster a1b2
   # Enable autoscaling and Set 3-5 replicas on machine pool 'mp1' on cluster 'mycluster'
-  ocm edit machinepool --enable-autoscaling --min-replicas=3 max-replicas=5 --cluster=mycluster mp1`,
+  ocm edit machinepool --enable-autoscaling --min-replicas=3 --max-replicas=5 --cluster=mycluster mp1`,
 	RunE: run,
 }
 
@@ -52,10 +54,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to edit the machine pool (required).",
+		"Name or ID or external_id of the cluster to edit the machine pool "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 
 	flags.IntVar(
 		&args.replicas,
@@ -267,12 +270,25 @@ func validateAutoscalingReplicasFlags(cmd *cobra.Command) error {
 
 	if args.autoscaling.Enabled {
 		if isReplicasSet {
-			return fmt.Errorf("--replicas can't be set with autoscaling paramteres")
+			return fmt.Errorf("--replicas can't be used together with autoscaling, use either " +
+				"--replicas or --min-replicas and --max-replicas")
 		}
 		if !isMaxReplicasSet && !isMinReplicasSet {
 			return fmt.Errorf(
 				"at least one of '--min-replicas' and '--max-replicas' is required when enabling autoscaling")
 		}
+		if isMinReplicasSet && args.autoscaling.MinReplicas < 0 {
+			return fmt.Errorf("Minimum number of replicas is 0, but --min-replicas is %d",
+				args.autoscaling.MinReplicas)
+		}
+		// When only one of the bounds is given the other one is the current value of the
+		// machine pool, and the check is left to OCM:
+		if isMinReplicasSet && isMaxReplicasSet &&
+			args.autoscaling.MaxReplicas < args.autoscaling.MinReplicas {
+			return fmt.Errorf("--max-replicas must be greater than or equal to --min-replicas")
+		}
+	} else if isReplicasSet && args.replicas < 0 {
+		return fmt.Errorf("Minimum number of replicas is 0, but --replicas is %d", args.replicas)
 	}
 
 	if isAutoscalingSet && !args.autoscaling.Enabled {
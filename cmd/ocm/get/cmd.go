@@ -32,26 +32,33 @@ var args struct {
 	parameter []string
 	header    []string
 	single    bool
+	pretty    bool
+	compact   bool
+	filter    string
 }
 
 var Cmd = &cobra.Command{
-	Use:       "get RESOURCE [ID]",
-	Short:     "Send a GET request",
-	Long:      "Send a GET request to the given path.",
-	RunE:      run,
-	ValidArgs: urls.Resources(),
+	Use:               "get RESOURCE [ID]",
+	Short:             "Send a GET request",
+	Long:              "Send a GET request to the given path.",
+	RunE:              run,
+	ValidArgsFunction: arguments.CompleteResource,
 }
 
 func init() {
 	fs := Cmd.Flags()
 	arguments.AddParameterFlag(fs, &args.parameter)
 	arguments.AddHeaderFlag(fs, &args.header)
+	arguments.AddFilterFlag(fs, &args.filter)
 	fs.BoolVar(
 		&args.single,
 		"single",
 		false,
-		"Return the output as a single line.",
+		"Return the output as a single line. Same as --compact.",
 	)
+	arguments.AddPrettyFlags(fs, &args.pretty, &args.compact)
+	Cmd.MarkFlagsMutuallyExclusive("pretty", "compact")
+	Cmd.MarkFlagsMutuallyExclusive("pretty", "single")
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -60,6 +67,12 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Could not create URI: %v", err)
 	}
 
+	// Parse the filter before sending the request, so that mistakes are detected early:
+	filter, err := arguments.ParseFilterFlag(args.filter)
+	if err != nil {
+		return fmt.Errorf("Can't parse filter: %v", err)
+	}
+
 	// Load the configuration file:
 	cfg, err := config.Load()
 	if err != nil {
@@ -92,7 +105,10 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 	arguments.ApplyParameterFlag(request, args.parameter)
-	arguments.ApplyHeaderFlag(request, args.header)
+	err = arguments.ApplyHeaderFlag(request, args.header)
+	if err != nil {
+		return err
+	}
 
 	// Send the request:
 	response, err := request.Send()
@@ -101,18 +117,18 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 	status := response.Status()
 	body := response.Bytes()
-	if status < 400 {
-		if args.single {
-			err = dump.Single(os.Stdout, body)
-		} else {
-			err = dump.Pretty(os.Stdout, body)
+	compact := args.compact || args.single
+	if status < 400 && filter != nil {
+		var values []interface{}
+		values, err = filter.Apply(body)
+		if err != nil {
+			return fmt.Errorf("Can't apply filter to body: %v", err)
 		}
+		err = dump.Values(os.Stdout, values, arguments.IsCompact(os.Stdout, args.pretty, compact))
+	} else if status < 400 {
+		err = dump.Document(os.Stdout, body, arguments.IsCompact(os.Stdout, args.pretty, compact))
 	} else {
-		if args.single {
-			err = dump.Single(os.Stderr, body)
-		} else {
-			err = dump.Pretty(os.Stderr, body)
-		}
+		err = dump.Document(os.Stderr, body, arguments.IsCompact(os.Stderr, args.pretty, compact))
 	}
 	if err != nil {
 		return fmt.Errorf("Can't print body: %v", err)
@@ -6,6 +6,8 @@ import (
 
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/spf13/cobra"
 )
@@ -13,8 +15,9 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "cluster {NAME|ID|EXTERNAL_ID}",
 	Short: "Initiate cluster hibernation",
-	Long: "Initiates cluster hibernation. While hibernating a cluster will not consume any cloud provider infrastructure" +
-		"but will be counted for quota.",
+	Long: "Initiates cluster hibernation. While hibernating a cluster will not consume any cloud provider infrastructure " +
+		"but will be counted for quota. Only clusters that are ready can be hibernated, and hibernation is only " +
+		"supported for OpenShift Dedicated clusters.",
 	RunE: run,
 }
 
@@ -57,9 +60,19 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
 	}
 
+	err = c.CheckHibernationSupported(cluster)
+	if err != nil {
+		return fmt.Errorf("Can't hibernate cluster '%s': %v", clusterKey, err)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return fmt.Errorf("Can't hibernate cluster '%s' because it is %s, only clusters that "+
+			"are ready can be hibernated", clusterKey, cluster.State())
+	}
+
 	_, err = clusterCollection.Cluster(cluster.ID()).Hibernate().Send()
 	if err != nil {
 		return err
 	}
+	quiet.Printf("Hibernating cluster '%s'\n", clusterKey)
 	return nil
 }
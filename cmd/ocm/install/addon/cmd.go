@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	clusterKey string
+	parameters []string
+	wait       bool
+	timeout    time.Duration
+	interval   time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:     "addon --cluster={NAME|ID|EXTERNAL_ID} [flags] ADDON_ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Install an add-on on a cluster",
+	Long: "Install an add-on on a cluster. The values of the parameters of the add-on are given " +
+		"with the --parameters flag, and are checked against the parameters declared by the " +
+		"add-on before installing it.",
+	Example: `  # Install the add-on 'my-addon' on the cluster 'mycluster'
+  ocm install addon --cluster=mycluster my-addon
+  # Install the add-on 'my-addon' with two parameters
+  ocm install addon --cluster=mycluster --parameters size=3 --parameters tier=large my-addon
+  # Install the add-on 'my-addon' and wait up to ten minutes till it is ready
+  ocm install addon --cluster=mycluster --wait --timeout=10m my-addon`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID or external_id of the cluster to install the add-on on "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.StringArrayVar(
+		&args.parameters,
+		"parameters",
+		nil,
+		"Value of a parameter of the add-on, in the format 'key=value'. "+
+			"Can be used multiple times to give multiple parameters.",
+	)
+
+	flags.BoolVar(
+		&args.wait,
+		"wait",
+		false,
+		"Wait till the add-on is ready, and fail if the installation fails.",
+	)
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		30*time.Minute,
+		"Maximum time to wait for the add-on to be ready, when using --wait.",
+	)
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"Time to wait between checks of the state of the add-on, when using --wait.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+
+	// Check command line arguments:
+	if len(argv) != 1 || argv[0] == "" {
+		return fmt.Errorf(
+			"Expected exactly one command line parameter containing the identifier of the add-on",
+		)
+	}
+	addOnID := argv[0]
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	if !args.wait && (cmd.Flags().Changed("timeout") || cmd.Flags().Changed("interval")) {
+		return fmt.Errorf("Options '--timeout' and '--interval' can only be used together with '--wait'")
+	}
+
+	values, err := parseParameters(args.parameters)
+	if err != nil {
+		return err
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Get the client for the cluster management api
+	client := connection.ClustersMgmt().V1()
+
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return fmt.Errorf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	addOn, err := c.GetAddOn(client, addOnID)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateAddOnParameters(addOn, values)
+	if err != nil {
+		return err
+	}
+
+	// Send the parameters in the order that they are declared by the add-on:
+	var parameters []*cmv1.AddOnInstallationParameterBuilder
+	for _, parameter := range addOn.Parameters().Slice() {
+		value, ok := values[parameter.ID()]
+		if ok {
+			parameters = append(parameters,
+				cmv1.NewAddOnInstallationParameter().ID(parameter.ID()).Value(value))
+		}
+	}
+	installationBuilder := cmv1.NewAddOnInstallation().
+		ID(addOn.ID()).
+		Addon(cmv1.NewAddOn().ID(addOn.ID()))
+	if len(parameters) > 0 {
+		installationBuilder = installationBuilder.
+			Parameters(cmv1.NewAddOnInstallationParameterList().Items(parameters...))
+	}
+	installation, err := installationBuilder.Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create add-on installation body for cluster '%s': %v",
+			clusterKey, err)
+	}
+
+	_, err = client.Clusters().
+		Cluster(cluster.ID()).
+		Addons().
+		Add().
+		Body(installation).
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to install add-on '%s' on cluster '%s': %v", addOnID, clusterKey, err)
+	}
+	quiet.Printf("Installing add-on '%s' on cluster '%s'\n", addOnID, clusterKey)
+
+	if args.wait {
+		return waitForAddOn(client.Clusters(), cluster.ID(), clusterKey, addOnID)
+	}
+	return nil
+}
+
+// waitForAddOn waits till the installation of the add-on finishes, reporting the changes of its
+// state, and returns an error containing the reason if it fails.
+func waitForAddOn(client *cmv1.ClustersClient, clusterID, clusterKey, addOnID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), args.timeout)
+	defer cancel()
+
+	installation, err := c.WaitForAddOnInstallation(ctx, client, clusterID, addOnID, args.interval,
+		func(current *cmv1.AddOnInstallation) {
+			quiet.Printf("Add-on '%s' is %s\n", addOnID, current.State())
+		})
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("Add-on '%s' isn't ready on cluster '%s' after %s", addOnID, clusterKey,
+			args.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to wait for add-on '%s' on cluster '%s': %v", addOnID, clusterKey,
+			err)
+	}
+
+	switch installation.State() {
+	case cmv1.AddOnInstallationStateReady:
+		return nil
+	case cmv1.AddOnInstallationStateFailed:
+		if installation.StateDescription() != "" {
+			return fmt.Errorf("Add-on '%s' failed to install on cluster '%s': %s", addOnID,
+				clusterKey, installation.StateDescription())
+		}
+		return fmt.Errorf("Add-on '%s' failed to install on cluster '%s'", addOnID, clusterKey)
+	default:
+		return fmt.Errorf("Add-on '%s' will not be ready on cluster '%s', its state is '%s'",
+			addOnID, clusterKey, installation.State())
+	}
+}
+
+// parseParameters converts the values of the '--parameters' flag into a map.
+func parseParameters(parameters []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, parameter := range parameters {
+		key, value, ok := strings.Cut(parameter, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("Expected key=value format for parameter '%s'", parameter)
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("Parameter '%s' is given more than once", key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
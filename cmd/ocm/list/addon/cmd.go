@@ -18,10 +18,12 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/config"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	"github.com/openshift-online/ocm-cli/pkg/output"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/spf13/cobra"
@@ -30,6 +32,8 @@ import (
 var args struct {
 	clusterKey string
 	columns    string
+	output     string
+	noHeaders  bool
 }
 
 var Cmd = &cobra.Command{
@@ -50,7 +54,8 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to list the add-ons of (required).",
+		"Name or ID or external_id of the cluster to list the add-ons of "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
 	fs.StringVar(
 		&args.columns,
@@ -58,15 +63,33 @@ func init() {
 		"id, name, state",
 		"Comma separated list of columns to display.",
 	)
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		"Output format, instead of the table. Options are [csv].",
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput([]string{"csv"}))
+	fs.BoolVar(
+		&args.noHeaders,
+		"no-headers",
+		false,
+		"Don't print header row",
+	)
 
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
 	// Create a context:
 	ctx := context.Background()
 
+	if args.output != "" && args.output != "csv" {
+		return fmt.Errorf("Invalid output format '%s', options are [csv]", args.output)
+	}
+
 	// Load the configuration:
 	cfg, err := config.Load()
 	if err != nil {
@@ -94,6 +117,7 @@ func run(cmd *cobra.Command, argv []string) error {
 	table, err := printer.NewTable().
 		Name("addons").
 		Columns(args.columns).
+		CSV(args.output == "csv").
 		Build(ctx)
 	if err != nil {
 		return err
@@ -125,15 +149,17 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to get add-ons for cluster '%s': %v", clusterKey, err)
 	}
 
-	if len(clusterAddOns) == 0 {
-		fmt.Printf("There are no add-ons installed on cluster '%s'", clusterKey)
+	if len(clusterAddOns) == 0 && args.output == "" {
+		quiet.Printf("There are no add-ons installed on cluster '%s'", clusterKey)
 		return nil
 	}
 
 	// Write the column headers:
-	err = table.WriteHeaders()
-	if err != nil {
-		return err
+	if !args.noHeaders {
+		err = table.WriteHeaders()
+		if err != nil {
+			return err
+		}
 	}
 
 	// Write the rows:
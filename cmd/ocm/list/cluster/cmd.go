@@ -17,10 +17,14 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
@@ -29,15 +33,23 @@ import (
 	"github.com/openshift-online/ocm-cli/pkg/config"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	"github.com/openshift-online/ocm-cli/pkg/output"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 )
 
 var args struct {
 	parameter []string
 	header    []string
 	managed   bool
+	search    string
 	noHeaders bool
 	columns   string
+	fields    string
 	padding   int
+	output    string
+	summary   bool
+	groupBy   string
+	sortBy    string
+	idOnly    bool
 }
 
 // Cmd Constant:
@@ -46,8 +58,18 @@ var Cmd = &cobra.Command{
 	Aliases: []string{"cluster"},
 	Short:   "List clusters",
 	Long:    "List clusters, optionally filtering by substring of ID or Name",
-	Args:    cobra.RangeArgs(0, 1),
-	RunE:    run,
+	Example: `  # List all the clusters
+  ocm list clusters
+  # Count the clusters in each state
+  ocm list clusters --summary
+  # Count the ready clusters of each version
+  ocm list clusters --group-by version --search "state = 'ready'"
+  # Print the logs of each of the ready clusters
+  for id in $(ocm list clusters --id-only --search "state = 'ready'"); do
+    ocm cluster logs "$id"
+  done`,
+	Args: cobra.RangeArgs(0, 1),
+	RunE: run,
 }
 
 func init() {
@@ -60,6 +82,14 @@ func init() {
 		false,
 		"Filter managed/unmanaged clusters",
 	)
+	fs.StringVar(
+		&args.search,
+		"search",
+		"",
+		"Search expression that the server uses to select the clusters, for example "+
+			"\"name like 'prod%' and state = 'ready'\". It is combined with the rest of the "+
+			"filters using the 'and' connective.",
+	)
 	_ = fs.Bool(
 		"step",
 		true,
@@ -79,18 +109,182 @@ func init() {
 		"id, name, api.url, openshift_version, product.id, hypershift.enabled, cloud_provider.id, region.id, state",
 		"Specify which columns to display separated by commas, path is based on Cluster struct",
 	)
+	fs.StringVar(
+		&args.fields,
+		"fields",
+		"",
+		fmt.Sprintf("Comma separated list of the fields to display, in the given order, "+
+			"for example 'name,id,state,region'. This is a shorter alternative to '--columns'. "+
+			"Valid fields are %s.", strings.Join(fieldNames(), ", ")),
+	)
 	fs.IntVar(
 		&args.padding,
 		"padding",
 		-1,
 		"Change all column sizes.",
 	)
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, instead of the table. Options are %s. The template is "+
+			"executed once for each cluster.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+	fs.BoolVar(
+		&args.summary,
+		"summary",
+		false,
+		"Instead of the clusters, write the number of clusters in each state, or in each of "+
+			"the groups selected with '--group-by'.",
+	)
+	fs.StringVar(
+		&args.groupBy,
+		"group-by",
+		"state",
+		fmt.Sprintf("Field used to group the clusters in the summary. Options are %s. "+
+			"Implies '--summary'.", strings.Join(groupNames(), ", ")),
+	)
+	Cmd.RegisterFlagCompletionFunc("group-by", completeGroupBy)
+	fs.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		"Field used to sort the clusters, instead of the order returned by the server. It can "+
+			"be one of the displayed columns or of the names accepted by '--fields'. Prefix it "+
+			"with '-' to sort in descending order, for example '-version'.",
+	)
+	arguments.AddIDOnlyFlag(fs, &args.idOnly)
+}
+
+func completeGroupBy(cmd *cobra.Command, args []string, toComplete string) ([]string,
+	cobra.ShellCompDirective) {
+	return groupNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+var validOutputs = append([]string{"csv"}, output.TemplateFormats...)
+
+// fields contains the names that can be used with the '--fields' flag, in the order that they are
+// suggested to the user, and the columns of the table that they correspond to.
+var fields = []struct {
+	name   string
+	column string
+}{
+	{name: "id", column: "id"},
+	{name: "external_id", column: "external_id"},
+	{name: "name", column: "name"},
+	{name: "api_url", column: "api.url"},
+	{name: "version", column: "openshift_version"},
+	{name: "product", column: "product.id"},
+	{name: "hcp", column: "hypershift.enabled"},
+	{name: "provider", column: "cloud_provider.id"},
+	{name: "region", column: "region.id"},
+	{name: "state", column: "state"},
+}
+
+// fieldNames returns the names of the fields that can be used with the '--fields' flag.
+func fieldNames() []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// fieldColumns translates the value of the '--fields' flag into the corresponding columns of the
+// table, preserving the order.
+func fieldColumns(value string) (string, error) {
+	names := utils.SplitList(value)
+	if len(names) == 0 {
+		return "", fmt.Errorf("Expected at least one field, valid fields are %s",
+			strings.Join(fieldNames(), ", "))
+	}
+	columns := make([]string, len(names))
+	for i, name := range names {
+		for _, field := range fields {
+			if field.name == name {
+				columns[i] = field.column
+				break
+			}
+		}
+		if columns[i] == "" {
+			return "", fmt.Errorf("Unknown field '%s', valid fields are %s",
+				name, strings.Join(fieldNames(), ", "))
+		}
+	}
+	return strings.Join(columns, ","), nil
 }
 
 func run(cmd *cobra.Command, argv []string) error {
 	// Create a context:
 	ctx := context.Background()
 
+	var tmpl *output.Template
+	if output.IsTemplate(args.output) {
+		var err error
+		tmpl, err = output.NewTemplate(args.output)
+		if err != nil {
+			return err
+		}
+	} else if args.output != "" && args.output != "csv" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
+	if args.idOnly {
+		if args.output != "" {
+			return fmt.Errorf("Option '--id-only' can't be used together with '--output'")
+		}
+		if cmd.Flags().Changed("fields") || cmd.Flags().Changed("columns") {
+			return fmt.Errorf("Option '--id-only' can't be used together with '--fields' or " +
+				"'--columns'")
+		}
+		if args.summary {
+			return fmt.Errorf("Option '--id-only' can't be used together with '--summary'")
+		}
+		if cmd.Flags().Changed("group-by") {
+			return fmt.Errorf("Option '--id-only' can't be used together with '--group-by'")
+		}
+	}
+
+	columns := args.columns
+	if cmd.Flags().Changed("fields") {
+		if cmd.Flags().Changed("columns") {
+			return fmt.Errorf("Options '--fields' and '--columns' can't be used together")
+		}
+		var err error
+		columns, err = fieldColumns(args.fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	var counts *summary
+	if args.summary || cmd.Flags().Changed("group-by") {
+		if tmpl != nil {
+			return fmt.Errorf("Option '--summary' can only be used with the 'csv' output format")
+		}
+		if args.sortBy != "" {
+			return fmt.Errorf("Option '--summary' can't be used together with '--sort-by'")
+		}
+		if cmd.Flags().Changed("fields") || cmd.Flags().Changed("columns") {
+			return fmt.Errorf("Option '--summary' can't be used together with '--fields' or " +
+				"'--columns', use '--group-by' to select how the clusters are grouped")
+		}
+		var err error
+		counts, err = newSummary(args.groupBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	if args.search != "" {
+		err := arguments.CheckSearch(args.search)
+		if err != nil {
+			return fmt.Errorf("Invalid search expression '%s': %v", args.search, err)
+		}
+	}
+
 	// Load the configuration:
 	cfg, err := config.Load()
 	if err != nil {
@@ -117,13 +311,23 @@ func run(cmd *cobra.Command, argv []string) error {
 	// Create the output table:
 	table, err := printer.NewTable().
 		Name("clusters").
-		Columns(args.columns).
+		Columns(columns).
+		CSV(args.output == "csv").
 		Build(ctx)
 	if err != nil {
 		return err
 	}
 	defer table.Close()
 
+	// Check the sort field before retrieving the clusters:
+	var sorter *output.Sorter
+	if args.sortBy != "" {
+		sorter, err = table.Sorter(sortColumn(args.sortBy))
+		if err != nil {
+			return err
+		}
+	}
+
 	// This will contain the terms used to construct the search query:
 	var searchTerms []string
 
@@ -133,6 +337,11 @@ func run(cmd *cobra.Command, argv []string) error {
 		searchTerms = append(searchTerms, term)
 	}
 
+	// Add the search expression given with the `--search` flag:
+	if args.search != "" {
+		searchTerms = append(searchTerms, args.search)
+	}
+
 	// Add the search term for the `--managed` flag:
 	if cmd.Flags().Changed("managed") {
 		var value string
@@ -174,7 +383,7 @@ func run(cmd *cobra.Command, argv []string) error {
 	searchQuery := strings.Join(searchTerms, " and ")
 
 	// Unless noHeaders set, print header row:
-	if !args.noHeaders {
+	if !args.noHeaders && !args.idOnly && tmpl == nil && counts == nil {
 		table.WriteHeaders()
 	}
 
@@ -182,27 +391,44 @@ func run(cmd *cobra.Command, argv []string) error {
 	// for all the iterations just changing the values of the `size` and `page` parameters.
 	request := connection.ClustersMgmt().V1().Clusters().List().Search(searchQuery)
 	arguments.ApplyParameterFlag(request, args.parameter)
-	arguments.ApplyHeaderFlag(request, args.header)
+	err = arguments.ApplyHeaderFlag(request, args.header)
+	if err != nil {
+		return err
+	}
+
+	// When sorting the clusters can only be written once all the pages have been retrieved:
+	var sorted []*v1.Cluster
 
 	// Send the request till we receive a page with less items than requested:
 	size := 100
 	index := 1
 	for {
 		// Fetch the next page:
-		request.Size(size)
-		request.Page(index)
-		response, err := request.Send()
+		response, err := fetchPage(request, index, size)
 		if err != nil {
+			// Bad requests are usually caused by a search expression that the server doesn't
+			// accept, so report that explicitly:
+			if response != nil && response.Status() == http.StatusBadRequest && searchQuery != "" {
+				return fmt.Errorf("The server rejected the search expression \"%s\": %s",
+					searchQuery, response.Error().Reason())
+			}
 			return fmt.Errorf("Can't retrieve clusters: %v", err)
 		}
 
 		// Display the items of the fetched page:
 		response.Items().Each(func(cluster *v1.Cluster) bool {
-			err = table.WriteObject(cluster)
+			switch {
+			case counts != nil:
+				counts.add(cluster)
+			case sorter != nil:
+				sorted = append(sorted, cluster)
+			default:
+				err = writeCluster(printer, table, tmpl, cluster)
+			}
 			return err == nil
 		})
 		if err != nil {
-			break
+			return err
 		}
 
 		// If the number of fetched items is less than requested, then this was the last
@@ -213,5 +439,93 @@ func run(cmd *cobra.Command, argv []string) error {
 		index++
 	}
 
+	// The summary can only be written once all the clusters have been counted:
+	if counts != nil {
+		return counts.write(printer, args.output == "csv", !args.noHeaders)
+	}
+
+	if sorter != nil {
+		sorter.Sort(sorted)
+		for _, cluster := range sorted {
+			err = writeCluster(printer, table, tmpl, cluster)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
+
+// pageRetryLimit is the number of times that the request for a page is retried, and pageRetryDelay
+// the delay before the first retry, which is doubled after each failure. Note that the SDK already
+// retries each request a couple of times with short delays, these retries are intended for
+// problems that last longer than that.
+const (
+	pageRetryLimit = 3
+	pageRetryDelay = 2 * time.Second
+)
+
+// fetchPage sends the request for the given page, retrying it when it fails because of a transient
+// problem, so that a failure in the middle of a long list doesn't discard the pages that have
+// already been written.
+func fetchPage(request *v1.ClustersListRequest, page, size int) (response *v1.ClustersListResponse,
+	err error) {
+	delay := pageRetryDelay
+	for attempt := 1; ; attempt++ {
+		response, err = request.Page(page).Size(size).Send()
+		if err == nil || attempt > pageRetryLimit || !isTransient(response) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Failed to retrieve page %d of clusters, will retry in %s: %v\n",
+			page, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isTransient checks if the failed request that produced the given response can be retried. That
+// is the case when there is no response at all, for example because the connection was closed, or
+// when the server reported an internal error. The rest of the errors, like a rejected search
+// expression, won't go away retrying.
+func isTransient(response *v1.ClustersListResponse) bool {
+	return response == nil || response.Status() >= http.StatusInternalServerError
+}
+
+// sortColumn translates the value of the '--sort-by' flag into the corresponding column of the
+// table when it is one of the names accepted by the '--fields' flag, preserving the prefix that
+// selects the descending order.
+func sortColumn(value string) string {
+	value = strings.TrimSpace(value)
+	name := strings.TrimPrefix(value, "-")
+	for _, field := range fields {
+		if field.name == name {
+			return strings.TrimSuffix(value, name) + field.column
+		}
+	}
+	return value
+}
+
+// writeCluster writes the given cluster as a row of the table, or using the template given with
+// the '--output' flag if there is one, or just its identifier if the '--id-only' flag was given.
+func writeCluster(printer *output.Printer, table *output.Table, tmpl *output.Template,
+	cluster *v1.Cluster) error {
+	if args.idOnly {
+		_, err := fmt.Fprintln(printer, cluster.ID())
+		return err
+	}
+	if tmpl != nil {
+		return writeTemplate(printer, tmpl, cluster)
+	}
+	return table.WriteObject(cluster)
+}
+
+// writeTemplate writes the given cluster using the template given with the '--output' flag.
+func writeTemplate(writer io.Writer, tmpl *output.Template, cluster *v1.Cluster) error {
+	buf := new(bytes.Buffer)
+	err := v1.MarshalCluster(cluster, buf)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal cluster: %v", err)
+	}
+	return tmpl.Execute(writer, buf.Bytes())
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to write the summary of the clusters requested with the
+// '--summary' flag.
+
+package cluster
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// groups contains the names that can be used with the '--group-by' flag and the functions that
+// extract the corresponding value from a cluster.
+var groups = []struct {
+	name string
+	key  func(cluster *v1.Cluster) string
+}{
+	{
+		name: "state",
+		key: func(cluster *v1.Cluster) string {
+			return string(cluster.State())
+		},
+	},
+	{
+		name: "region",
+		key: func(cluster *v1.Cluster) string {
+			return cluster.Region().ID()
+		},
+	},
+	{
+		name: "provider",
+		key: func(cluster *v1.Cluster) string {
+			return cluster.CloudProvider().ID()
+		},
+	},
+	{
+		name: "version",
+		key: func(cluster *v1.Cluster) string {
+			version := cluster.OpenshiftVersion()
+			if version == "" {
+				version = cluster.Version().RawID()
+			}
+			return version
+		},
+	},
+}
+
+// unknownGroup is the name of the group of the clusters that don't have a value for the field
+// used to group them, for example clusters that don't have a version yet.
+const unknownGroup = "unknown"
+
+// groupNames returns the names that can be used with the '--group-by' flag.
+func groupNames() []string {
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		names[i] = group.name
+	}
+	return names
+}
+
+// summary counts the clusters that share the same value of the field given with the '--group-by'
+// flag.
+type summary struct {
+	name   string
+	key    func(cluster *v1.Cluster) string
+	counts map[string]int
+	total  int
+}
+
+// newSummary creates a summary that groups the clusters by the given field.
+func newSummary(name string) (*summary, error) {
+	for _, group := range groups {
+		if group.name == name {
+			return &summary{
+				name:   group.name,
+				key:    group.key,
+				counts: map[string]int{},
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("Invalid group '%s', options are %s",
+		name, strings.Join(groupNames(), ", "))
+}
+
+// add counts the given cluster in the group that it belongs to.
+func (s *summary) add(cluster *v1.Cluster) {
+	key := s.key(cluster)
+	if key == "" {
+		key = unknownGroup
+	}
+	s.counts[key]++
+	s.total++
+}
+
+// keys returns the names of the groups, the largest first. Groups with the same number of
+// clusters are sorted by name so that the output is stable.
+func (s *summary) keys() []string {
+	keys := make([]string, 0, len(s.counts))
+	for key := range s.counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if s.counts[keys[i]] != s.counts[keys[j]] {
+			return s.counts[keys[i]] > s.counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// write writes the number of clusters of each group, followed by the total. In CSV format the
+// total isn't written, as it can be calculated from the rest of the rows.
+func (s *summary) write(writer io.Writer, csvFormat, headers bool) error {
+	if csvFormat {
+		csvWriter := csv.NewWriter(writer)
+		if headers {
+			err := csvWriter.Write([]string{s.name, "count"})
+			if err != nil {
+				return err
+			}
+		}
+		for _, key := range s.keys() {
+			err := csvWriter.Write([]string{key, strconv.Itoa(s.counts[key])})
+			if err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if headers {
+		fmt.Fprintf(tabWriter, "%s\tCOUNT\n", strings.ToUpper(s.name))
+	}
+	for _, key := range s.keys() {
+		fmt.Fprintf(tabWriter, "%s\t%d\n", key, s.counts[key])
+	}
+	fmt.Fprintf(tabWriter, "TOTAL\t%d\n", s.total)
+	return tabWriter.Flush()
+}
@@ -14,22 +14,32 @@ limitations under the License.
 package idp
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	"github.com/openshift-online/ocm-cli/pkg/output"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var args struct {
 	clusterKey string
 	columns    string
+	output     string
+	noHeaders  bool
+	sortBy     string
+	idOnly     bool
 }
 
 var Cmd = &cobra.Command{
@@ -38,7 +48,11 @@ var Cmd = &cobra.Command{
 	Short:   "List cluster IDPs",
 	Long:    "List identity providers for a cluster.",
 	Example: `  # List all identity providers on a cluster named "mycluster"
-  ocm list idps --cluster=mycluster`,
+  ocm list idps --cluster=mycluster
+  # List all identity providers on a cluster named "mycluster" in JSON format
+  ocm list idps --cluster=mycluster --output=json
+  # Export the identity providers of a cluster named "mycluster" to a spreadsheet
+  ocm list idps --cluster=mycluster --output=csv > idps.csv`,
 	Args: cobra.NoArgs,
 	RunE: run,
 }
@@ -50,19 +64,44 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to list the IdP of (required).",
+		"Name or ID or external_id of the cluster to list the IdP of "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
 	fs.StringVar(
 		&args.columns,
 		"columns",
-		"name, type, auth_url",
+		"name, type, mapping_method, members, auth_url",
 		"Comma separated list of columns to display.",
 	)
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, instead of the table. Options are %s.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+	fs.BoolVar(
+		&args.noHeaders,
+		"no-headers",
+		false,
+		"Don't print header row",
+	)
+	fs.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		"Column used to sort the identity providers, for example 'type'. Prefix it with '-' "+
+			"to sort in descending order.",
+	)
+	arguments.AddIDOnlyFlag(fs, &args.idOnly)
 
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
+var validOutputs = append([]string{"json", "yaml", "csv"}, output.TemplateFormats...)
+
 func run(cmd *cobra.Command, argv []string) error {
 	// Create a context:
 	ctx := context.Background()
@@ -73,6 +112,25 @@ func run(cmd *cobra.Command, argv []string) error {
 		return err
 	}
 
+	var tmpl *output.Template
+	if output.IsTemplate(args.output) {
+		tmpl, err = output.NewTemplate(args.output)
+		if err != nil {
+			return err
+		}
+	} else if args.output != "" && args.output != "json" && args.output != "yaml" &&
+		args.output != "csv" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+	if args.idOnly {
+		if args.output != "" {
+			return fmt.Errorf("Option '--id-only' can't be used together with '--output'")
+		}
+		if cmd.Flags().Changed("columns") {
+			return fmt.Errorf("Option '--id-only' can't be used together with '--columns'")
+		}
+	}
+
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
 	if err != nil {
@@ -123,19 +181,45 @@ func run(cmd *cobra.Command, argv []string) error {
 		Name("idps").
 		Columns(args.columns).
 		Value("type", getType).
+		Value("members", getMembers).
 		Value("auth_url", func(idp *cmv1.IdentityProvider) string {
 			return getAuthURL(cluster, idp.Name())
 		}).
+		CSV(args.output == "csv").
 		Build(ctx)
 	if err != nil {
 		return err
 	}
 	defer table.Close()
 
+	// Sort the identity providers, for all the output formats:
+	if args.sortBy != "" {
+		sorter, err := table.Sorter(args.sortBy)
+		if err != nil {
+			return err
+		}
+		sorter.Sort(idps)
+	}
+
+	if args.idOnly {
+		for _, idp := range idps {
+			fmt.Fprintln(printer, idp.ID())
+		}
+		return nil
+	}
+	if args.output == "json" || args.output == "yaml" {
+		return printList(printer, idps, args.output)
+	}
+	if tmpl != nil {
+		return printTemplate(printer, idps, tmpl)
+	}
+
 	// Write the column headers:
-	err = table.WriteHeaders()
-	if err != nil {
-		return err
+	if !args.noHeaders {
+		err = table.WriteHeaders()
+		if err != nil {
+			return err
+		}
 	}
 
 	// Write the rows:
@@ -156,8 +240,12 @@ func getType(idp *cmv1.IdentityProvider) string {
 	switch idp.Type() {
 	case "GithubIdentityProvider":
 		return "GitHub"
+	case "GitlabIdentityProvider":
+		return "GitLab"
 	case "GoogleIdentityProvider":
 		return "Google"
+	case "HTPasswdIdentityProvider":
+		return "HTPasswd"
 	case "LDAPIdentityProvider":
 		return "LDAP"
 	case "OpenIDIdentityProvider":
@@ -167,6 +255,60 @@ func getType(idp *cmv1.IdentityProvider) string {
 	return ""
 }
 
+// getMembers returns the number of organizations or teams that are allowed to log in with a
+// GitHub identity provider, and an empty string for other types.
+func getMembers(idp *cmv1.IdentityProvider) string {
+	if idp.Type() != "GithubIdentityProvider" {
+		return ""
+	}
+	github := idp.Github()
+	if teams := len(github.Teams()); teams > 0 {
+		return fmt.Sprintf("%d teams", teams)
+	}
+	return fmt.Sprintf("%d organizations", len(github.Organizations()))
+}
+
+// printList writes the complete description of the identity providers in JSON or YAML format.
+func printList(writer io.Writer, idps []*cmv1.IdentityProvider, format string) error {
+	buf := new(bytes.Buffer)
+	err := cmv1.MarshalIdentityProviderList(idps, buf)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity providers: %v", err)
+	}
+	if format == "json" {
+		return dump.Pretty(writer, buf.Bytes())
+	}
+	var data interface{}
+	err = json.Unmarshal(buf.Bytes(), &data)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity providers: %v", err)
+	}
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	err = encoder.Encode(data)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal identity providers: %v", err)
+	}
+	return encoder.Close()
+}
+
+// printTemplate writes each of the identity providers using the template given with the
+// '--output' flag.
+func printTemplate(writer io.Writer, idps []*cmv1.IdentityProvider, tmpl *output.Template) error {
+	for _, idp := range idps {
+		buf := new(bytes.Buffer)
+		err := cmv1.MarshalIdentityProvider(idp, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal identity provider: %v", err)
+		}
+		err = tmpl.Execute(writer, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getAuthURL(cluster *cmv1.Cluster, idpName string) string {
 	oauthURL := c.GetClusterOauthURL(cluster)
 	return fmt.Sprintf("%s/oauth2callback/%s", oauthURL, idpName)
@@ -22,6 +22,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/config"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
@@ -54,11 +55,12 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to list the routes of (required).",
+		"Name or ID or external_id of the cluster to list the routes of "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
 
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -31,6 +32,7 @@ import (
 
 var args struct {
 	clusterKey string
+	idOnly     bool
 }
 
 var Cmd = &cobra.Command{
@@ -52,10 +54,12 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to list the machine pools of (required).",
+		"Name or ID or external_id of the cluster to list the machine pools of "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.AddIDOnlyFlag(flags, &args.idOnly)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -95,6 +99,16 @@ func run(cmd *cobra.Command, argv []string) error {
 		return err
 	}
 
+	// The default machine pool is described by the nodes of the cluster, but it is listed with
+	// the rest as it can be used with the commands that accept the identifier of a machine pool:
+	if args.idOnly {
+		fmt.Fprintln(os.Stdout, "default")
+		for _, machinePool := range machinePools {
+			fmt.Fprintln(os.Stdout, machinePool.ID())
+		}
+		return nil
+	}
+
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
@@ -17,8 +17,10 @@ limitations under the License.
 package org
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
@@ -34,6 +36,8 @@ var args struct {
 	parameter []string
 	header    []string
 	columns   string
+	output    string
+	noHeaders bool
 }
 
 var Cmd = &cobra.Command{
@@ -55,12 +59,40 @@ func init() {
 		"id, name",
 		"Comma separated list of columns to display.",
 	)
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, instead of the table. Options are %s. The template is "+
+			"executed once for each organization.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+	fs.BoolVar(
+		&args.noHeaders,
+		"no-headers",
+		false,
+		"Don't print header row",
+	)
 }
 
+var validOutputs = append([]string{"csv"}, output.TemplateFormats...)
+
 func run(cmd *cobra.Command, argv []string) error {
 	// Create a context:
 	ctx := context.Background()
 
+	var tmpl *output.Template
+	if output.IsTemplate(args.output) {
+		var err error
+		tmpl, err = output.NewTemplate(args.output)
+		if err != nil {
+			return err
+		}
+	} else if args.output != "" && args.output != "csv" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
 	// Load the configuration:
 	cfg, err := config.Load()
 	if err != nil {
@@ -88,6 +120,7 @@ func run(cmd *cobra.Command, argv []string) error {
 	table, err := printer.NewTable().
 		Name("orgs").
 		Columns(args.columns).
+		CSV(args.output == "csv").
 		Build(ctx)
 	if err != nil {
 		return err
@@ -95,16 +128,21 @@ func run(cmd *cobra.Command, argv []string) error {
 	defer table.Close()
 
 	// Write the header row:
-	err = table.WriteHeaders()
-	if err != nil {
-		return err
+	if !args.noHeaders && tmpl == nil {
+		err = table.WriteHeaders()
+		if err != nil {
+			return err
+		}
 	}
 
 	// Create the request. Note that this request can be created outside of the loop and used
 	// for all iterations just changing the values of the `size` and `page` parameters.
 	request := connection.AccountsMgmt().V1().Organizations().List()
 	arguments.ApplyParameterFlag(request, args.parameter)
-	arguments.ApplyHeaderFlag(request, args.header)
+	err = arguments.ApplyHeaderFlag(request, args.header)
+	if err != nil {
+		return err
+	}
 
 	// Send the request till we receive a page with less items than requested:
 	size := 100
@@ -120,11 +158,15 @@ func run(cmd *cobra.Command, argv []string) error {
 
 		// Display the items of the fetched page:
 		response.Items().Each(func(org *amv1.Organization) bool {
-			err = table.WriteObject(org)
+			if tmpl != nil {
+				err = writeTemplate(printer, tmpl, org)
+			} else {
+				err = table.WriteObject(org)
+			}
 			return err == nil
 		})
 		if err != nil {
-			break
+			return err
 		}
 
 		// If the number of fetched items is less than requested, then this was the last
@@ -137,3 +179,13 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	return nil
 }
+
+// writeTemplate writes the given organization using the template given with the '--output' flag.
+func writeTemplate(writer io.Writer, tmpl *output.Template, org *amv1.Organization) error {
+	buf := new(bytes.Buffer)
+	err := amv1.MarshalOrganization(org, buf)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal organization: %v", err)
+	}
+	return tmpl.Execute(writer, buf.Bytes())
+}
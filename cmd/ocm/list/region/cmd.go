@@ -14,13 +14,18 @@ limitations under the License.
 package region
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"text/tabwriter"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	"github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/output"
 	"github.com/openshift-online/ocm-cli/pkg/provider"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +34,7 @@ var args struct {
 	ccs                bool
 	awsAccessKeyID     string
 	awsSecretAccessKey string
+	output             string
 }
 
 var Cmd = &cobra.Command{
@@ -38,6 +44,10 @@ var Cmd = &cobra.Command{
 	Long: "List regions of a cloud provider.\n\n" +
 		"In --ccs mode, fetch regions that would be available to *your* cloud account\n" +
 		"(currently only supported with --provider=aws).",
+	Example: `  # List the regions of AWS and whether they support multiple availability zones
+  ocm list regions --provider=aws
+  # List the identifiers of the GCP regions
+  ocm list regions --provider=gcp --output='go-template={{.id}}{{"\n"}}'`,
 	RunE: run,
 }
 
@@ -52,6 +62,7 @@ func init() {
 
 	//nolint:gosec
 	Cmd.MarkFlagRequired("provider")
+	Cmd.RegisterFlagCompletionFunc("provider", providerCompletion)
 	fs.BoolVar(
 		&args.ccs,
 		"ccs",
@@ -72,9 +83,43 @@ func init() {
 		"AWS Secret Access",
 	)
 
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, instead of the table. Options are %s. The template is "+
+			"executed once for each region.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+}
+
+var validOutputs = append([]string{"json"}, output.TemplateFormats...)
+
+// validProviders are the cloud providers that have regions.
+var validProviders = []string{cluster.ProviderAWS, cluster.ProviderGCP}
+
+func providerCompletion(cmd *cobra.Command, args []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validProviders, cobra.ShellCompDirectiveNoFileComp
 }
 
 func run(cmd *cobra.Command, argv []string) error {
+	if !isValidProvider(args.provider) {
+		return fmt.Errorf("Invalid provider '%s', options are %s", args.provider, validProviders)
+	}
+
+	var tmpl *output.Template
+	if output.IsTemplate(args.output) {
+		var err error
+		tmpl, err = output.NewTemplate(args.output)
+		if err != nil {
+			return err
+		}
+	} else if args.output != "" && args.output != "json" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
 	ccs := cluster.CCS{}
 	if args.provider == "aws" && args.ccs {
 		if args.awsAccessKeyID == "" {
@@ -102,25 +147,49 @@ func run(cmd *cobra.Command, argv []string) error {
 		return err
 	}
 
+	// Only the enabled regions are displayed, both for CCS and non CCS regions:
+	enabled := make([]*cmv1.CloudRegion, 0, len(regions))
+	for _, region := range regions {
+		if region.Enabled() {
+			enabled = append(enabled, region)
+		}
+	}
+
+	if args.output == "json" {
+		buf := new(bytes.Buffer)
+		err = cmv1.MarshalCloudRegionList(enabled, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal regions: %v", err)
+		}
+		return dump.Pretty(os.Stdout, buf.Bytes())
+	}
+	if tmpl != nil {
+		for _, region := range enabled {
+			buf := new(bytes.Buffer)
+			err = cmv1.MarshalCloudRegion(region, buf)
+			if err != nil {
+				return fmt.Errorf("Failed to marshal region: %v", err)
+			}
+			err = tmpl.Execute(os.Stdout, buf.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.TabIndent)
 
-	//We display only the enabled region for both ccs and non ccs regions
 	if args.provider == "aws" && args.ccs {
 		fmt.Fprintf(writer, "ID\t\tSUPPORTS MULTI-AZ\n")
-		for _, region := range regions {
-			if !region.Enabled() {
-				continue
-			}
+		for _, region := range enabled {
 			fmt.Fprintf(writer, "%s\t\t%v\n",
 				region.ID(), region.SupportsMultiAZ())
 		}
 	} else {
 		fmt.Fprintf(writer, "ID\t\tON RED HAT INFRA\t\tCCS ONLY\t\tSUPPORTS MULTI-AZ\n")
-		for _, region := range regions {
-			if !region.Enabled() {
-				continue
-			}
+		for _, region := range enabled {
 			fmt.Fprintf(writer, "%s\t\t%v\t\t%v\t\t%v\n",
 				region.ID(), !region.CCSOnly(), region.CCSOnly(), region.SupportsMultiAZ())
 		}
@@ -130,3 +199,12 @@ func run(cmd *cobra.Command, argv []string) error {
 	err = writer.Flush()
 	return err
 }
+
+func isValidProvider(value string) bool {
+	for _, provider := range validProviders {
+		if value == provider {
+			return true
+		}
+	}
+	return false
+}
@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"os"
 	"text/tabwriter"
+	"time"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -36,7 +38,8 @@ var Cmd = &cobra.Command{
 	Use:     "upgradepolicies --cluster={NAME|ID|EXTERNAL_ID}",
 	Aliases: []string{"upgrade-policy", "upgrade-policies", "upgradepolicy"},
 	Short:   "List cluster upgrade policies",
-	Long:    "List upgrade policies for a cluster.",
+	Long: "List upgrade policies for a cluster, with their schedule, the version that they " +
+		"upgrade to and their current state.",
 	Example: `  # List all upgrade policies on a cluster named "mycluster"
   ocm list upgradepolicies --cluster=mycluster`,
 	Args: cobra.NoArgs,
@@ -51,10 +54,11 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID or external_id of the cluster to list the upgrade policies of (required).",
+		"Name or ID or external_id of the cluster to list the upgrade policies of "+
+			"(required, unless selected with 'ocm cluster use').",
 	)
-	//nolint:gosec
-	Cmd.MarkFlagRequired("cluster")
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -97,13 +101,23 @@ func run(cmd *cobra.Command, argv []string) error {
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-	fmt.Fprintf(writer, "ID\tSCHEDULE TYPE\t\t\tUPGRADE VERSION\t\tNEXT RUN\n")
+	fmt.Fprintf(writer, "ID\tSCHEDULE TYPE\tSCHEDULE\tUPGRADE VERSION\tSTATE\tNEXT RUN\n")
 	for _, upgradePolicy := range upgradePolicies {
-		fmt.Fprintf(writer, "%s\t%s\t\t\t%s\t\t%v\n",
+		state, err := c.GetUpgradePolicyState(clusterCollection, cluster.ID(), upgradePolicy.ID())
+		if err != nil {
+			return err
+		}
+		schedule := upgradePolicy.Schedule()
+		if schedule == "" {
+			schedule = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%v\n",
 			upgradePolicy.ID(),
 			upgradePolicy.ScheduleType(),
+			schedule,
 			upgradePolicy.Version(),
-			upgradePolicy.NextRun())
+			state.Value(),
+			upgradePolicy.NextRun().UTC().Format(time.RFC3339))
 	}
 
 	//nolint:gosec
@@ -17,25 +17,40 @@ limitations under the License.
 package version
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	"github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/output"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 )
 
 var args struct {
 	defaultVersion bool
 	channelGroup   string
+	all            bool
+	columns        string
+	output         string
+	noHeaders      bool
 }
 
 var Cmd = &cobra.Command{
 	Use:     "versions",
 	Aliases: []string{"version"},
 	Short:   "List available versions",
-	Long:    "List the versions available for provisioning a cluster",
+	Long: "List the versions available for provisioning a cluster, indicating which one is the " +
+		"default and which ones are enabled.",
 	Example: `  # List all supported cluster versions
-  ocm list versions`,
+  ocm list versions
+  # List the versions of the 'stable' channel group in JSON format
+  ocm list versions --channel-group=stable --output=json`,
 	Args: cobra.NoArgs,
 	RunE: run,
 }
@@ -55,9 +70,58 @@ func init() {
 		"stable",
 		"List only versions from the specified channel group",
 	)
+	fs.BoolVar(
+		&args.all,
+		"all",
+		false,
+		"Include the versions that aren't enabled.",
+	)
+	fs.StringVar(
+		&args.columns,
+		"columns",
+		"id, default, enabled",
+		"Comma separated list of columns to display.",
+	)
+	fs.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, instead of the table. Options are %s. The template is "+
+			"executed once for each version.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+	fs.BoolVar(
+		&args.noHeaders,
+		"no-headers",
+		false,
+		"Don't print header row",
+	)
 }
 
+var validOutputs = append([]string{"json", "csv"}, output.TemplateFormats...)
+
 func run(cmd *cobra.Command, argv []string) error {
+	// Create a context:
+	ctx := context.Background()
+
+	var tmpl *output.Template
+	if output.IsTemplate(args.output) {
+		var err error
+		tmpl, err = output.NewTemplate(args.output)
+		if err != nil {
+			return err
+		}
+	} else if args.output != "" && args.output != "json" && args.output != "csv" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
+	// Load the configuration:
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
 	if err != nil {
@@ -66,16 +130,80 @@ func run(cmd *cobra.Command, argv []string) error {
 	defer connection.Close()
 
 	client := connection.ClustersMgmt().V1()
-	versions, defaultVersion, err := cluster.GetEnabledVersions(client, args.channelGroup)
+	versions, err := cluster.GetVersions(client, args.channelGroup, !args.all)
 	if err != nil {
 		return fmt.Errorf("Can't retrieve versions: %v", err)
 	}
 
 	if args.defaultVersion {
-		fmt.Println(defaultVersion)
-	} else {
-		for _, v := range versions {
-			fmt.Println(v)
+		for _, version := range versions {
+			if version.Default() {
+				fmt.Println(cluster.DropOpenshiftVPrefix(version.ID()))
+			}
+		}
+		return nil
+	}
+
+	if args.output == "json" {
+		buf := new(bytes.Buffer)
+		err = cmv1.MarshalVersionList(versions, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal versions: %v", err)
+		}
+		return dump.Pretty(os.Stdout, buf.Bytes())
+	}
+	if tmpl != nil {
+		for _, version := range versions {
+			buf := new(bytes.Buffer)
+			err = cmv1.MarshalVersion(version, buf)
+			if err != nil {
+				return fmt.Errorf("Failed to marshal version: %v", err)
+			}
+			err = tmpl.Execute(os.Stdout, buf.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Create the output printer:
+	printer, err := output.NewPrinter().
+		Writer(os.Stdout).
+		Pager(cfg.Pager).
+		Build(ctx)
+	if err != nil {
+		return err
+	}
+	defer printer.Close()
+
+	// Create the output table:
+	table, err := printer.NewTable().
+		Name("versions").
+		Columns(args.columns).
+		Value("id", func(version *cmv1.Version) string {
+			return cluster.DropOpenshiftVPrefix(version.ID())
+		}).
+		CSV(args.output == "csv").
+		Build(ctx)
+	if err != nil {
+		return err
+	}
+	defer table.Close()
+
+	// Write the column headers:
+	if !args.noHeaders {
+		err = table.WriteHeaders()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write the rows:
+	for _, version := range versions {
+		err = table.WriteObject(version)
+		if err != nil {
+			return err
 		}
 	}
 
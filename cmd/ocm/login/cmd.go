@@ -17,8 +17,11 @@ limitations under the License.
 package login
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
@@ -47,16 +50,18 @@ var urlAliases = map[string]string{
 }
 
 var args struct {
-	tokenURL     string
-	clientID     string
-	clientSecret string
-	scopes       []string
-	url          string
-	token        string
-	user         string
-	password     string
-	insecure     bool
-	persistent   bool
+	tokenURL      string
+	clientID      string
+	clientSecret  string
+	scopes        []string
+	url           string
+	token         string
+	tokenFile     string
+	user          string
+	password      string
+	insecure      bool
+	persistent    bool
+	useDeviceCode bool
 }
 
 var Cmd = &cobra.Command{
@@ -64,7 +69,11 @@ var Cmd = &cobra.Command{
 	Short: "Log in",
 	Long: "Log in, saving the credentials to the configuration file.\n" +
 		"The recommend way is using '--token', which you can obtain at: " +
-		urls.OfflineTokenPage,
+		urls.OfflineTokenPage + "\n" +
+		"Use '--token-file' instead to read the token from a file or from the standard input, " +
+		"so that it isn't visible in the list of processes.\n" +
+		"On machines without a browser use '--use-device-code', which prints a code to enter " +
+		"in a browser of any other device.",
 	Args: cobra.NoArgs,
 	RunE: run,
 }
@@ -115,6 +124,13 @@ func init() {
 		"",
 		"Access or refresh token.",
 	)
+	flags.StringVar(
+		&args.tokenFile,
+		"token-file",
+		"",
+		"File containing the access or refresh token, so that it isn't visible in the list of "+
+			"processes. Use '-' to read it from the standard input.",
+	)
 	flags.StringVar(
 		&args.user,
 		"user",
@@ -143,6 +159,13 @@ func init() {
 			"this option is provided then the user name and password will be stored "+
 			"persistently, in clear text, which is potentially unsafe.",
 	)
+	flags.BoolVar(
+		&args.useDeviceCode,
+		"use-device-code",
+		false,
+		"Log in with the OAuth device code flow: the command prints a code and an URL where it "+
+			"has to be entered, using a browser of any device, and waits till that is done.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -153,11 +176,28 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Option '--url' is mandatory")
 	}
 
+	// Read the token from the file, if given:
+	if args.tokenFile != "" {
+		if args.token != "" {
+			return fmt.Errorf("Options '--token' and '--token-file' can't be used together")
+		}
+		args.token, err = readTokenFile(args.tokenFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check that we have some kind of credentials:
 	havePassword := args.user != "" && args.password != ""
 	haveSecret := args.clientID != "" && args.clientSecret != ""
 	haveToken := args.token != ""
-	if !havePassword && !haveSecret && !haveToken {
+	if args.useDeviceCode && (havePassword || haveSecret || haveToken) {
+		return fmt.Errorf(
+			"Option '--use-device-code' can't be used together with '--token', '--user' " +
+				"and '--password', or '--client-secret'",
+		)
+	}
+	if !havePassword && !haveSecret && !haveToken && !args.useDeviceCode {
 		// Allow bare `ocm login` to suggest the token page without noise of full help.
 		fmt.Fprintf(
 			os.Stderr,
@@ -226,6 +266,9 @@ func run(cmd *cobra.Command, argv []string) error {
 		tokenURL = args.tokenURL
 	}
 	clientID := sdk.DefaultClientID
+	if args.useDeviceCode {
+		clientID = deviceCodeClientID
+	}
 	if args.clientID != "" {
 		clientID = args.clientID
 	}
@@ -247,6 +290,14 @@ func run(cmd *cobra.Command, argv []string) error {
 	cfg.Password = args.password
 	cfg.Insecure = args.insecure
 
+	// Get the tokens with the device code flow, the connection below will then only verify them:
+	if args.useDeviceCode {
+		cfg.AccessToken, cfg.RefreshToken, err = loginWithDeviceCode(context.Background(), cfg)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create a connection and get the token to verify that the crendentials are correct:
 	connection, err := cfg.Connection()
 	if err != nil {
@@ -272,3 +323,53 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	return nil
 }
+
+// loginWithDeviceCode asks the user to enter a code in the verification page of the OpenID server
+// and waits till that is done, returning the resulting tokens.
+func loginWithDeviceCode(ctx context.Context, cfg *config.Config) (accessToken, refreshToken string,
+	err error) {
+	client := newDeviceClient(cfg.TokenURL, cfg.ClientID, cfg.Scopes, cfg.Insecure)
+	authorization, err := client.authorize(ctx)
+	if err != nil {
+		err = fmt.Errorf("Can't start device code login: %v", err)
+		return
+	}
+	fmt.Fprintf(
+		os.Stderr,
+		"To log in open '%s' in a browser and enter the code '%s'\n",
+		authorization.VerificationURI, authorization.UserCode,
+	)
+	if authorization.VerificationURIComplete != "" {
+		fmt.Fprintf(
+			os.Stderr,
+			"Alternatively open '%s', which already contains the code\n",
+			authorization.VerificationURIComplete,
+		)
+	}
+	accessToken, refreshToken, err = client.wait(ctx, authorization)
+	if err != nil {
+		err = fmt.Errorf("Can't complete device code login: %v", err)
+	}
+	return
+}
+
+// readTokenFile reads the token from the given file, or from the standard input if the name of
+// the file is '-', removing the surrounding white space.
+func readTokenFile(file string) (token string, err error) {
+	var data []byte
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		// #nosec G304
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		err = fmt.Errorf("Can't read token file '%s': %v", file, err)
+		return
+	}
+	token = strings.TrimSpace(string(data))
+	if token == "" {
+		err = fmt.Errorf("Token file '%s' is empty", file)
+	}
+	return
+}
@@ -0,0 +1,207 @@
+/*
+Copyright (c) 2018 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to log in with the OAuth device authorization grant, as
+// described in RFC 8628.
+
+package login
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/ocm-cli/pkg/debug"
+)
+
+// deviceCodeClientID is the OpenID client used for the device code flow when no client identifier
+// is explicitly given, as the default client doesn't support that flow.
+const deviceCodeClientID = "ocm-cli"
+
+// deviceCodeGrantType is the grant type used to exchange the device code for tokens.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// deviceAuthorization is the response of the device authorization endpoint.
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceToken is the response of the token endpoint while the device code is exchanged, either
+// containing the tokens or the reason why they aren't available yet.
+type deviceToken struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// deviceClient knows how to send the requests of the device code flow to the OpenID server.
+type deviceClient struct {
+	tokenURL   string
+	clientID   string
+	scopes     []string
+	httpClient *http.Client
+}
+
+// newDeviceClient creates a client for the device code flow of the OpenID server that has the
+// given token URL.
+func newDeviceClient(tokenURL, clientID string, scopes []string, insecure bool) *deviceClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecure {
+		// #nosec G402
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &deviceClient{
+		tokenURL: tokenURL,
+		clientID: clientID,
+		scopes:   scopes,
+		httpClient: &http.Client{
+			Transport: debug.WrapTransport(transport),
+			Timeout:   30 * time.Second,
+		},
+	}
+}
+
+// deviceAuthorizationURL returns the URL of the device authorization endpoint. The servers used
+// by OCM are Keycloak servers, where that endpoint is next to the token endpoint.
+func deviceAuthorizationURL(tokenURL string) (string, error) {
+	parsed, err := url.Parse(tokenURL)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(parsed.Path, "/token") {
+		return "", fmt.Errorf("expected a token URL ending with '/token', but got '%s'", tokenURL)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/token") + "/auth/device"
+	return parsed.String(), nil
+}
+
+// post sends a form to the given URL and decodes the JSON response. Error responses are also
+// decoded, as the token endpoint uses them to report that the authorization is still pending.
+func (c *deviceClient) post(ctx context.Context, address string, form url.Values,
+	result interface{}) (status int, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, address,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	status = response.StatusCode
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		err = fmt.Errorf("unexpected response with status code %d from %s", status, address)
+	}
+	return
+}
+
+// authorize starts the flow requesting a device code and the code that the user has to enter in
+// the verification page.
+func (c *deviceClient) authorize(ctx context.Context) (*deviceAuthorization, error) {
+	address, err := deviceAuthorizationURL(c.tokenURL)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"client_id": {c.clientID},
+		"scope":     {strings.Join(c.scopes, " ")},
+	}
+	result := &deviceAuthorization{}
+	status, err := c.post(ctx, address, form, result)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || result.DeviceCode == "" {
+		return nil, fmt.Errorf("unexpected status code %d from %s", status, address)
+	}
+	return result, nil
+}
+
+// wait polls the token endpoint till the user completes the authorization in the verification
+// page, and then returns the tokens.
+func (c *deviceClient) wait(ctx context.Context,
+	authorization *deviceAuthorization) (accessToken, refreshToken string, err error) {
+	// The interval is five seconds unless the server says otherwise, and the server may ask to
+	// increase it with the 'slow_down' error:
+	interval := 5 * time.Second
+	if authorization.Interval > 0 {
+		interval = time.Duration(authorization.Interval) * time.Second
+	}
+	if authorization.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(authorization.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {authorization.DeviceCode},
+		"client_id":   {c.clientID},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("the device code expired before the login was completed")
+			return
+		case <-time.After(interval):
+		}
+		result := &deviceToken{}
+		_, err = c.post(ctx, c.tokenURL, form, result)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("the device code expired before the login was completed")
+			}
+			return
+		}
+		switch result.Error {
+		case "":
+			accessToken = result.AccessToken
+			refreshToken = result.RefreshToken
+			return
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "expired_token":
+			err = fmt.Errorf("the device code expired before the login was completed")
+			return
+		case "access_denied":
+			err = fmt.Errorf("the login was denied")
+			return
+		default:
+			err = fmt.Errorf("%s: %s", result.Error, result.ErrorDescription)
+			return
+		}
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package logout
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -27,9 +29,10 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Log out",
-	Long:  "Log out, removing connection related variables from the config file.",
-	Args:  cobra.NoArgs,
-	RunE:  run,
+	Long: "Log out, revoking the refresh token at the authentication server and removing " +
+		"connection related variables from the config file.",
+	Args: cobra.NoArgs,
+	RunE: run,
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -39,6 +42,19 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("can't load configuration file: %w", err)
 	}
 
+	// Revoke the refresh token, so that it can't be used even if there are copies of the
+	// configuration file. The local settings are removed even if this fails, for example
+	// because there is no network connection. Without the token URL it isn't possible to know
+	// what server issued the token, so nothing is revoked:
+	if cfg.RefreshToken != "" && cfg.TokenURL != "" {
+		err = revokeToken(context.Background(), cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to revoke the refresh token at the "+
+				"authentication server, it was removed from the configuration file but it is "+
+				"still valid till it expires: %v\n", err)
+		}
+	}
+
 	// Remove all the login related settings from the configuration file:
 	cfg.Disarm()
 
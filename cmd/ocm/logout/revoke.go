@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to revoke the refresh token, as described in RFC 7009.
+
+package logout
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/debug"
+	"github.com/openshift-online/ocm-cli/pkg/insecure"
+	"github.com/openshift-online/ocm-cli/pkg/keepalive"
+	"github.com/openshift-online/ocm-cli/pkg/proxy"
+)
+
+// revocationURL returns the URL of the token revocation endpoint. The servers used by OCM are
+// Keycloak servers, where that endpoint is next to the token endpoint.
+func revocationURL(tokenURL string) (string, error) {
+	parsed, err := url.Parse(tokenURL)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(parsed.Path, "/token") {
+		return "", fmt.Errorf("expected a token URL ending with '/token', but got '%s'", tokenURL)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/token") + "/revoke"
+	return parsed.String(), nil
+}
+
+// revokeToken asks the OpenID server to revoke the refresh token of the given configuration, so
+// that it can't be used any more, even by someone that has a copy of the configuration file.
+func revokeToken(ctx context.Context, cfg *config.Config) error {
+	address, err := revocationURL(cfg.TokenURL)
+	if err != nil {
+		return err
+	}
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = sdk.DefaultClientID
+	}
+	form := url.Values{
+		"token":           {cfg.RefreshToken},
+		"token_type_hint": {"refresh_token"},
+		"client_id":       {clientID},
+	}
+	if cfg.ClientSecret != "" {
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	err = proxy.Check()
+	if err != nil {
+		return err
+	}
+	err = keepalive.Check()
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Insecure || insecure.Enabled() {
+		// #nosec G402
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	client := &http.Client{
+		Transport: debug.WrapTransport(proxy.WrapTransport(keepalive.WrapTransport(transport))),
+		Timeout:   10 * time.Second,
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, address,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, err = io.Copy(io.Discard, response.Body)
+	if err != nil {
+		return err
+	}
+
+	// The server responds with 200 also when the token was already invalid, as described in
+	// section 2.2 of RFC 7009:
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from '%s'", response.StatusCode, address)
+	}
+	return nil
+}
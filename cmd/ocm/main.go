@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/openshift-online/ocm-cli/cmd/ocm/account"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/apply"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/cluster"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/completion"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/config"
@@ -38,9 +39,11 @@ import (
 	"github.com/openshift-online/ocm-cli/cmd/ocm/fail"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/get"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/hibernate"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/install"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/list"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/login"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/logout"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/org"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/patch"
 	plugincmd "github.com/openshift-online/ocm-cli/cmd/ocm/plugin"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/pop"
@@ -50,6 +53,8 @@ import (
 	"github.com/openshift-online/ocm-cli/cmd/ocm/success"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/token"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/tunnel"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/uninstall"
+	"github.com/openshift-online/ocm-cli/cmd/ocm/upgrade"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/version"
 	"github.com/openshift-online/ocm-cli/cmd/ocm/whoami"
 	"github.com/openshift-online/ocm-cli/pkg/arguments"
@@ -79,9 +84,17 @@ func init() {
 	// Add the command line flags:
 	fs := root.PersistentFlags()
 	arguments.AddDebugFlag(fs)
+	arguments.AddDebugHTTPFlag(fs)
+	arguments.AddInsecureSkipTLSVerifyFlag(fs)
+	arguments.AddKeepAliveFlags(fs)
+	arguments.AddProxyFlags(fs)
+	arguments.AddQuietFlag(fs)
+	arguments.AddProfileFlag(fs)
+	arguments.AddNoColorFlag(fs)
 
 	// Register the subcommands:
 	root.AddCommand(account.Cmd)
+	root.AddCommand(apply.Cmd)
 	root.AddCommand(cluster.Cmd)
 	root.AddCommand(completion.Cmd)
 	root.AddCommand(config.Cmd)
@@ -92,9 +105,11 @@ func init() {
 	root.AddCommand(fail.Cmd)
 	root.AddCommand(get.Cmd)
 	root.AddCommand(hibernate.Cmd)
+	root.AddCommand(install.Cmd)
 	root.AddCommand(list.Cmd)
 	root.AddCommand(login.Cmd)
 	root.AddCommand(logout.Cmd)
+	root.AddCommand(org.Cmd)
 	root.AddCommand(patch.Cmd)
 	root.AddCommand(plugincmd.Cmd)
 	root.AddCommand(post.Cmd)
@@ -104,6 +119,8 @@ func init() {
 	root.AddCommand(success.Cmd)
 	root.AddCommand(token.Cmd)
 	root.AddCommand(tunnel.Cmd)
+	root.AddCommand(uninstall.Cmd)
+	root.AddCommand(upgrade.Cmd)
 	root.AddCommand(version.Cmd)
 	root.AddCommand(whoami.Cmd)
 }
@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+)
+
+var args struct {
+	org    string
+	output string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "quota",
+	Short: "List the quota of the organization",
+	Long: "List the quota of the organization of the current account: the resource types that " +
+		"each quota applies to, how much of it is already consumed and how much is still " +
+		"available. Check it when the creation of a cluster fails because the quota was exceeded.",
+	Example: `  # List the quota of the current organization
+  ocm org quota
+  # Write the quota of the current organization in JSON
+  ocm org quota --output json`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVar(
+		&args.org,
+		"org",
+		"",
+		"Identifier of the organization. Defaults to the organization of the current account.",
+	)
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		"Output format, instead of the table. The only option is 'json'.",
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput([]string{"json"}))
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	if args.output != "" && args.output != "json" {
+		return fmt.Errorf("Invalid output format '%s', the only option is 'json'", args.output)
+	}
+
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	orgID := args.org
+	if orgID == "" {
+		// Get organization of current user:
+		response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+		if err != nil {
+			return fmt.Errorf("Can't retrieve current user information: %v", err)
+		}
+		orgID = response.Body().Organization().ID()
+		if orgID == "" {
+			return fmt.Errorf("The current account doesn't belong to an organization")
+		}
+	}
+
+	// Retrieve all the pages of quota cost, including the resources that each quota applies to:
+	request := connection.AccountsMgmt().V1().Organizations().Organization(orgID).QuotaCost().List().
+		Parameter("fetchRelatedResources", true)
+	var quotas []*amv1.QuotaCost
+	size := 100
+	for page := 1; ; page++ {
+		response, err := request.Page(page).Size(size).Send()
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve quota of organization '%s': %v", orgID, err)
+		}
+		quotas = append(quotas, response.Items().Slice()...)
+		if response.Size() < size {
+			break
+		}
+	}
+
+	if args.output == "json" {
+		buf := new(bytes.Buffer)
+		err = amv1.MarshalQuotaCostList(quotas, buf)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal quota: %v", err)
+		}
+		return dump.Pretty(os.Stdout, buf.Bytes())
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "QUOTA ID\tRESOURCE TYPES\tCONSUMED\tALLOWED\tAVAILABLE\n")
+	for _, quota := range quotas {
+		available := quota.Allowed() - quota.Consumed()
+		if available < 0 {
+			available = 0
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\n",
+			quota.QuotaID(), resourceTypes(quota), quota.Consumed(), quota.Allowed(), available)
+	}
+	return writer.Flush()
+}
+
+// resourceTypes returns the distinct types of the resources that the given quota applies to,
+// separated by commas, in the order that they are returned by the server.
+func resourceTypes(quota *amv1.QuotaCost) string {
+	var types []string
+	seen := map[string]bool{}
+	for _, resource := range quota.RelatedResources() {
+		resourceType := resource.ResourceType()
+		if resourceType == "" || seen[resourceType] {
+			continue
+		}
+		seen[resourceType] = true
+		types = append(types, resourceType)
+	}
+	if len(types) == 0 {
+		return "-"
+	}
+	return strings.Join(types, ",")
+}
@@ -33,14 +33,17 @@ var args struct {
 	parameter []string
 	header    []string
 	body      string
+	filter    string
+	pretty    bool
+	compact   bool
 }
 
 var Cmd = &cobra.Command{
-	Use:       "patch PATH",
-	Short:     "Send a PATCH request",
-	Long:      "Send a PATCH request to the given path.",
-	RunE:      run,
-	ValidArgs: urls.Resources(),
+	Use:               "patch PATH",
+	Short:             "Send a PATCH request",
+	Long:              "Send a PATCH request to the given path.",
+	RunE:              run,
+	ValidArgsFunction: arguments.CompleteResource,
 }
 
 func init() {
@@ -48,6 +51,9 @@ func init() {
 	arguments.AddParameterFlag(fs, &args.parameter)
 	arguments.AddHeaderFlag(fs, &args.header)
 	arguments.AddBodyFlag(fs, &args.body)
+	arguments.AddFilterFlag(fs, &args.filter)
+	arguments.AddPrettyFlags(fs, &args.pretty, &args.compact)
+	Cmd.MarkFlagsMutuallyExclusive("pretty", "compact")
 }
 
 func run(cmd *cobra.Command, argv []string) error {
@@ -56,6 +62,12 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Could not create URI: %v", err)
 	}
 
+	// Parse the filter before sending the request, so that mistakes are detected early:
+	filter, err := arguments.ParseFilterFlag(args.filter)
+	if err != nil {
+		return fmt.Errorf("Can't parse filter: %v", err)
+	}
+
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
 	if err != nil {
@@ -71,7 +83,10 @@ func run(cmd *cobra.Command, argv []string) error {
 		os.Exit(1)
 	}
 	arguments.ApplyParameterFlag(request, args.parameter)
-	arguments.ApplyHeaderFlag(request, args.header)
+	err = arguments.ApplyHeaderFlag(request, args.header)
+	if err != nil {
+		return err
+	}
 	err = arguments.ApplyBodyFlag(request, args.body)
 	if err != nil {
 		return fmt.Errorf("Can't read body: %v", err)
@@ -84,10 +99,17 @@ func run(cmd *cobra.Command, argv []string) error {
 	}
 	status := response.Status()
 	body := response.Bytes()
-	if status < 400 {
-		err = dump.Pretty(os.Stdout, body)
+	if status < 400 && filter != nil {
+		var values []interface{}
+		values, err = filter.Apply(body)
+		if err != nil {
+			return fmt.Errorf("Can't apply filter to body: %v", err)
+		}
+		err = dump.Values(os.Stdout, values, arguments.IsCompact(os.Stdout, args.pretty, args.compact))
+	} else if status < 400 {
+		err = dump.Document(os.Stdout, body, arguments.IsCompact(os.Stdout, args.pretty, args.compact))
 	} else {
-		err = dump.Pretty(os.Stderr, body)
+		err = dump.Document(os.Stderr, body, arguments.IsCompact(os.Stderr, args.pretty, args.compact))
 	}
 	if err != nil {
 		return fmt.Errorf("Can't print body: %v", err)
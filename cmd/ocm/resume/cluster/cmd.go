@@ -6,6 +6,8 @@ import (
 
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/spf13/cobra"
 )
@@ -55,9 +57,15 @@ func run(cmd *cobra.Command, argv []string) error {
 	if err != nil {
 		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
 	}
+	if cluster.State() != cmv1.ClusterStateHibernating {
+		return fmt.Errorf("Can't resume cluster '%s' because it is %s, only clusters that are "+
+			"hibernating can be resumed", clusterKey, cluster.State())
+	}
+
 	_, err = clusterCollection.Cluster(cluster.ID()).Resume().Send()
 	if err != nil {
 		return err
 	}
+	quiet.Printf("Resuming cluster '%s'\n", clusterKey)
 	return nil
 }
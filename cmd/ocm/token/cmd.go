@@ -18,11 +18,13 @@ package token
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v4"
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift-online/ocm-cli/pkg/config"
@@ -76,7 +78,8 @@ func init() {
 		&args.generate,
 		"generate",
 		false,
-		"Generate a new token.",
+		"Generate a new access token using the refresh token, even if the current one hasn't "+
+			"expired yet.",
 	)
 }
 
@@ -112,9 +115,9 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	if args.generate {
 		// Get new tokens:
-		accessToken, refreshToken, err = connection.Tokens(15 * time.Minute)
+		accessToken, refreshToken, err = generateTokens(connection)
 		if err != nil {
-			return fmt.Errorf("Can't get new tokens: %v", err)
+			return err
 		}
 	} else {
 		// Get the tokens:
@@ -187,3 +190,45 @@ func run(cmd *cobra.Command, argv []string) error {
 	// Bye:
 	return nil
 }
+
+// generateTokens requests new tokens, even if the current access token is still valid. It returns
+// an error explaining how to log in again if the refresh token can't be used.
+func generateTokens(connection *sdk.Connection) (accessToken, refreshToken string, err error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", "", fmt.Errorf("Can't load config file: %v", err)
+	}
+	haveCredentials := (cfg.User != "" && cfg.Password != "") ||
+		(cfg.ClientID != "" && cfg.ClientSecret != "")
+	if !haveCredentials {
+		if cfg.RefreshToken == "" {
+			return "", "", errors.New("There is no refresh token to generate a new access token, " +
+				"run 'ocm login' to log in again")
+		}
+		if !config.IsEncryptedToken(cfg.RefreshToken) {
+			expires, left, err := config.TokenExpiration(cfg.RefreshToken)
+			if err != nil {
+				return "", "", fmt.Errorf("Can't check refresh token: %v", err)
+			}
+			if expires && left <= 0 {
+				return "", "", errors.New("The refresh token has expired, run 'ocm login' to " +
+					"log in again")
+			}
+		}
+	}
+
+	// The SDK only requests new tokens when the current access token expires before the given
+	// time, so ask for a bit more than the time that it has left:
+	minRemaining := 15 * time.Minute
+	if cfg.AccessToken != "" && !config.IsEncryptedToken(cfg.AccessToken) {
+		expires, left, err := config.TokenExpiration(cfg.AccessToken)
+		if err == nil && expires && left+time.Minute > minRemaining {
+			minRemaining = left + time.Minute
+		}
+	}
+	accessToken, refreshToken, err = connection.Tokens(minRemaining)
+	if err != nil {
+		return "", "", fmt.Errorf("Can't get new tokens, run 'ocm login' to log in again: %v", err)
+	}
+	return accessToken, refreshToken, nil
+}
@@ -27,6 +27,7 @@ import (
 
 	c "github.com/openshift-online/ocm-cli/pkg/cluster"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
 	clustersmgmtv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 )
@@ -98,7 +99,7 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("failed to get cluster '%s': %v", clusterKey, err)
 	}
 
-	fmt.Printf("Will create tunnel to cluster:\n Name: %s\n ID: %s\n", cluster.Name(), cluster.ID())
+	quiet.Printf("Will create tunnel to cluster:\n Name: %s\n ID: %s\n", cluster.Name(), cluster.ID())
 
 	sshURL, err := generateSSHURI(cluster)
 	if err != nil {
@@ -130,7 +131,7 @@ func run(cmd *cobra.Command, argv []string) error {
 	sshuttleArgs = append(sshuttleArgs, argv[1:]...)
 
 	// Output sshuttle command execution string for review
-	fmt.Printf("\n# %s %s\n\n", path, strings.Join(sshuttleArgs, " "))
+	quiet.Printf("\n# %s %s\n\n", path, strings.Join(sshuttleArgs, " "))
 
 	// #nosec G204
 	sshuttleCmd := exec.Command(path, sshuttleArgs...)
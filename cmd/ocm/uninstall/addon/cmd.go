@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+)
+
+var args struct {
+	clusterKey string
+	yes        bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "addon --cluster={NAME|ID|EXTERNAL_ID} [flags] ADDON_ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Uninstall an add-on from a cluster",
+	Long:    "Uninstall an add-on from a cluster, removing the resources that it created.",
+	Example: `  # Uninstall the add-on 'my-addon' from the cluster 'mycluster'
+  ocm uninstall addon --cluster=mycluster my-addon
+  # Uninstall the add-on 'my-addon' without asking for confirmation
+  ocm uninstall addon --cluster=mycluster --yes my-addon`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID or external_id of the cluster to uninstall the add-on from "+
+			"(required, unless selected with 'ocm cluster use').",
+	)
+	arguments.UseDefaultCluster(Cmd, &args.clusterKey)
+	Cmd.RegisterFlagCompletionFunc("cluster", arguments.CompleteCluster)
+
+	flags.BoolVarP(
+		&args.yes,
+		"yes",
+		"y",
+		false,
+		"Uninstall the add-on without asking for confirmation.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+
+	// Check command line arguments:
+	if len(argv) != 1 || argv[0] == "" {
+		return fmt.Errorf(
+			"Expected exactly one command line parameter containing the identifier of the add-on",
+		)
+	}
+	addOnID := argv[0]
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Get the client for the cluster management api
+	clusterCollection := connection.ClustersMgmt().V1().Clusters()
+
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	if !args.yes {
+		confirmed := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Uninstall add-on '%s' from cluster '%s'?", addOnID, clusterKey),
+		}
+		err = survey.AskOne(prompt, &confirmed, arguments.PromptStdio())
+		if err != nil {
+			return fmt.Errorf("Failed to get confirmation: %v", err)
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+
+	_, err = clusterCollection.
+		Cluster(cluster.ID()).
+		Addons().
+		Addoninstallation(addOnID).
+		Delete().
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to uninstall add-on '%s' from cluster '%s': %v",
+			addOnID, clusterKey, err)
+	}
+	quiet.Printf("Uninstalling add-on '%s' from cluster '%s'\n", addOnID, clusterKey)
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	c "github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+)
+
+var args struct {
+	version  string
+	schedule string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster {NAME|ID|EXTERNAL_ID} --version=VERSION [--schedule=TIME]",
+	Short: "Schedule a cluster upgrade",
+	Long: "Schedules the upgrade of a cluster to the given version. The version must be one of the " +
+		"available upgrades of the current version of the cluster. The time of the upgrade is given " +
+		"in RFC3339 format, and when it isn't given the upgrade starts in ten minutes.",
+	Example: `  # Upgrade the cluster 'mycluster' to version 4.10.1 on the 1st of June at 10:00 UTC
+  ocm upgrade cluster mycluster --version=4.10.1 --schedule=2030-06-01T10:00:00Z`,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version to upgrade the cluster to (required).",
+	)
+	//nolint:gosec
+	Cmd.MarkFlagRequired("version")
+	flags.StringVar(
+		&args.schedule,
+		"schedule",
+		"",
+		"Time of the upgrade in RFC3339 format, for example '2030-06-01T10:00:00Z'. "+
+			"The default is ten minutes from now.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) error {
+	// Check that there is exactly one cluster name, identifier or external identifier in the
+	// command line arguments:
+	if len(argv) != 1 {
+		return fmt.Errorf(
+			"Expected exactly one cluster name, identifier or external identifier",
+		)
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := argv[0]
+	if !c.IsValidClusterKey(clusterKey) {
+		return fmt.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+
+	// Check the time of the upgrade before talking to the server:
+	nextRun := time.Now().UTC().Add(10 * time.Minute)
+	if args.schedule != "" {
+		var err error
+		nextRun, err = time.Parse(time.RFC3339, args.schedule)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for --schedule, expected a time in RFC3339 "+
+				"format like '2030-06-01T10:00:00Z'", args.schedule)
+		}
+		if !nextRun.After(time.Now()) {
+			return fmt.Errorf("The time of the upgrade '%s' isn't in the future", args.schedule)
+		}
+	}
+	version := c.DropOpenshiftVPrefix(args.version)
+
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	// Get the client for the cluster management api
+	clusterCollection := connection.ClustersMgmt().V1().Clusters()
+
+	// Verify the cluster exists in OCM.
+	cluster, err := c.GetCluster(connection, clusterKey)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+	}
+
+	// Check that the version is a valid upgrade of the current version:
+	availableUpgrades, err := c.GetAvailableUpgrades(
+		connection.ClustersMgmt().V1(), c.GetVersionID(cluster), cluster.Product().ID())
+	if err != nil {
+		return fmt.Errorf("Failed to find available upgrades: %v", err)
+	}
+	if len(availableUpgrades) == 0 {
+		return fmt.Errorf("There are no available upgrades for cluster '%s'", clusterKey)
+	}
+	found := false
+	for _, availableUpgrade := range availableUpgrades {
+		if availableUpgrade == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("Version '%s' isn't a valid upgrade for cluster '%s' from version "+
+			"'%s', available upgrades are: %s", version, clusterKey, cluster.OpenshiftVersion(),
+			strings.Join(availableUpgrades, ", "))
+	}
+
+	upgradePolicy, err := cmv1.NewUpgradePolicy().
+		ScheduleType("manual").
+		NextRun(nextRun).
+		Version(version).
+		Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create upgrade policy for cluster '%s': %v", clusterKey, err)
+	}
+
+	response, err := clusterCollection.Cluster(cluster.ID()).
+		UpgradePolicies().
+		Add().
+		Body(upgradePolicy).
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to create upgrade policy for cluster '%s': %v", clusterKey, err)
+	}
+	quiet.Printf("Created upgrade policy '%s' to upgrade cluster '%s' to version %s at %s\n",
+		response.Body().ID(), clusterKey, version, nextRun.UTC().Format(time.RFC3339))
+
+	return nil
+}
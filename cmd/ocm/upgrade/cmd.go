@@ -0,0 +1,32 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"github.com/openshift-online/ocm-cli/cmd/ocm/upgrade/cluster"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "upgrade [flags] RESOURCE",
+	Short: "Upgrade a specific resource (currently only supported for clusters)",
+	Long:  "Upgrade a specific resource (currently only supported for clusters)",
+}
+
+func init() {
+	Cmd.AddCommand(cluster.Cmd)
+}
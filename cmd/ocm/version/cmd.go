@@ -17,25 +17,145 @@ limitations under the License.
 package version
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"runtime"
+	"runtime/debug"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/info"
 )
 
+var args struct {
+	output string
+}
+
+var validOutputs = []string{"json", "yaml"}
+
 var Cmd = &cobra.Command{
 	Use:   "version",
 	Short: "Prints the version",
-	Long:  "Prints the version number of the client.",
-	Args:  cobra.NoArgs,
-	RunE:  run,
+	Long: "Prints the version number of the client. With the '--output' flag it also prints " +
+		"the build details and, when logged in, the version of the server.",
+	Example: `  # Print the version of the SDK used to build the client
+  ocm version --output=json | jq -r .sdk_version`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, containing the versions of the client, the SDK, the Go "+
+			"compiler and the server. Options are %s.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+}
+
+// sdkModule is the path of the module of the SDK, used to find its version in the build
+// information.
+const sdkModule = "github.com/openshift-online/ocm-sdk-go"
+
+// versions contains the fields that are written with the '--output' flag. The names of the fields
+// are part of the interface used by scripts, so they must never change.
+type versions struct {
+	Version       string `json:"version" yaml:"version"`
+	Commit        string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	GoVersion     string `json:"go_version" yaml:"go_version"`
+	SDKVersion    string `json:"sdk_version,omitempty" yaml:"sdk_version,omitempty"`
+	ServerVersion string `json:"server_version,omitempty" yaml:"server_version,omitempty"`
 }
 
 func run(cmd *cobra.Command, argv []string) error {
-	// Print the version:
-	fmt.Fprintf(os.Stdout, "%s\n", info.Version)
+	if args.output == "" {
+		// Print the version:
+		fmt.Fprintf(os.Stdout, "%s\n", info.Version)
+		return nil
+	}
+	if args.output != "json" && args.output != "yaml" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
+
+	data := versions{
+		Version:   info.Version,
+		GoVersion: runtime.Version(),
+	}
+
+	// The commit and the version of the SDK are only available if the binary was built with
+	// module support:
+	buildInfo, ok := debug.ReadBuildInfo()
+	if ok {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				data.Commit = setting.Value
+			}
+		}
+		for _, dep := range buildInfo.Deps {
+			if dep.Path == sdkModule {
+				data.SDKVersion = dep.Version
+				if dep.Replace != nil {
+					data.SDKVersion = dep.Replace.Version
+				}
+			}
+		}
+	}
+
+	// The version of the server is optional, as this command should work also when not logged
+	// in or when the server isn't reachable:
+	serverVersion, err := getServerVersion()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't get the version of the server: %v\n", err)
+	}
+	data.ServerVersion = serverVersion
+
+	if args.output == "json" {
+		body, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal versions: %v", err)
+		}
+		return dump.Pretty(os.Stdout, body)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	err = encoder.Encode(data)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal versions: %v", err)
+	}
+	return encoder.Close()
+}
 
-	return nil
+// getServerVersion returns the version of the clusters management service, or an empty string if
+// the user isn't logged in.
+func getServerVersion() (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("Can't load config file: %v", err)
+	}
+	if cfg == nil {
+		return "", nil
+	}
+	armed, _, err := cfg.Armed()
+	if err != nil || !armed {
+		return "", err
+	}
+	connection, err := cfg.Connection()
+	if err != nil {
+		return "", fmt.Errorf("Can't create connection: %v", err)
+	}
+	defer connection.Close()
+	response, err := connection.ClustersMgmt().V1().Get().Send()
+	if err != nil {
+		return "", err
+	}
+	return response.Body().ServerVersion(), nil
 }
@@ -18,25 +18,62 @@ package whoami
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
 	"github.com/openshift-online/ocm-cli/pkg/dump"
 	"github.com/openshift-online/ocm-cli/pkg/ocm"
 )
 
+var args struct {
+	output string
+}
+
+var validOutputs = []string{"json", "yaml"}
+
 var Cmd = &cobra.Command{
 	Use:   "whoami",
 	Short: "Prints user information",
 	Long:  "Prints user information.",
-	Args:  cobra.NoArgs,
-	RunE:  run,
+	Example: `  # Print the identifier of the current account
+  ocm whoami --output=json | jq -r .account_id`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		fmt.Sprintf("Output format, containing only the account and organization identifiers "+
+			"and names, instead of the complete account. Options are %s.", validOutputs),
+	)
+	Cmd.RegisterFlagCompletionFunc("output", arguments.CompleteOutput(validOutputs))
+}
+
+// identity contains the fields of the current account that are written with the '--output' flag.
+// The names of the fields are part of the interface used by scripts, so they must never change.
+type identity struct {
+	AccountID        string `json:"account_id" yaml:"account_id"`
+	Username         string `json:"username" yaml:"username"`
+	Email            string `json:"email" yaml:"email"`
+	OrganizationID   string `json:"organization_id" yaml:"organization_id"`
+	OrganizationName string `json:"organization_name" yaml:"organization_name"`
 }
 
 func run(cmd *cobra.Command, argv []string) error {
+	if args.output != "" && args.output != "json" && args.output != "yaml" {
+		return fmt.Errorf("Invalid output format '%s', options are %s", args.output, validOutputs)
+	}
 
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
@@ -52,6 +89,10 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Can't send request: %v", err)
 	}
 
+	if args.output != "" && response.Status() < 400 {
+		return printIdentity(response.Body(), args.output)
+	}
+
 	// Buffer for pretty output:
 	buf := new(bytes.Buffer)
 
@@ -72,3 +113,29 @@ func run(cmd *cobra.Command, argv []string) error {
 
 	return nil
 }
+
+// printIdentity writes the identifiers and names of the given account and its organization in
+// JSON or YAML format.
+func printIdentity(account *amsv1.Account, format string) error {
+	data := identity{
+		AccountID:        account.ID(),
+		Username:         account.Username(),
+		Email:            account.Email(),
+		OrganizationID:   account.Organization().ID(),
+		OrganizationName: account.Organization().Name(),
+	}
+	if format == "json" {
+		body, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal account: %v", err)
+		}
+		return dump.Pretty(os.Stdout, body)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	err := encoder.Encode(data)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal account: %v", err)
+	}
+	return encoder.Close()
+}
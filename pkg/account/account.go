@@ -89,6 +89,33 @@ func GetRolesFromUsers(accounts []*amv1.Account,
 	return
 }
 
+// GetRoleBindings gets all the role bindings of the account with the given identifier.
+func GetRoleBindings(conn *sdk.Connection, accountID string) ([]*amv1.RoleBinding, error) {
+	var results []*amv1.RoleBinding
+	query := fmt.Sprintf("account_id = '%s'", accountID)
+	index := 1
+	size := 100
+	for {
+		response, err := conn.AccountsMgmt().V1().RoleBindings().List().
+			Size(size).
+			Page(index).
+			Parameter("search", query).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("Can't retrieve role bindings: %v", err)
+		}
+		results = append(results, response.Items().Slice()...)
+
+		// Break the loop if the page size is smaller than requested, as that indicates
+		// that this is the last page:
+		if response.Size() < size {
+			break
+		}
+		index++
+	}
+	return results, nil
+}
+
 // stringInList returns a bool signifying whether
 // a string is in a string array.
 func stringInList(strArr []string, key string) bool {
@@ -19,6 +19,8 @@ limitations under the License.
 package arguments
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
@@ -30,8 +32,16 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/color"
 	"github.com/openshift-online/ocm-cli/pkg/debug"
+	"github.com/openshift-online/ocm-cli/pkg/insecure"
+	"github.com/openshift-online/ocm-cli/pkg/jsonpath"
+	"github.com/openshift-online/ocm-cli/pkg/keepalive"
 	"github.com/openshift-online/ocm-cli/pkg/output"
+	"github.com/openshift-online/ocm-cli/pkg/profile"
+	"github.com/openshift-online/ocm-cli/pkg/proxy"
+	"github.com/openshift-online/ocm-cli/pkg/quiet"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 )
 
 type FilePath string
@@ -58,6 +68,43 @@ func AddDebugFlag(fs *pflag.FlagSet) {
 	debug.AddFlag(fs)
 }
 
+// AddDebugHTTPFlag adds the '--debug-http' flag to the given set of command line flags.
+func AddDebugHTTPFlag(fs *pflag.FlagSet) {
+	debug.AddHTTPFlag(fs)
+}
+
+// AddInsecureSkipTLSVerifyFlag adds the '--insecure-skip-tls-verify' flag to the given set of
+// command line flags.
+func AddInsecureSkipTLSVerifyFlag(fs *pflag.FlagSet) {
+	insecure.AddFlag(fs)
+}
+
+// AddKeepAliveFlags adds the '--disable-keep-alives', '--max-idle-conns-per-host' and
+// '--idle-conn-timeout' flags to the given set of command line flags.
+func AddKeepAliveFlags(fs *pflag.FlagSet) {
+	keepalive.AddFlags(fs)
+}
+
+// AddProxyFlags adds the '--proxy' and '--no-proxy' flags to the given set of command line flags.
+func AddProxyFlags(fs *pflag.FlagSet) {
+	proxy.AddFlags(fs)
+}
+
+// AddQuietFlag adds the '--quiet' flag to the given set of command line flags.
+func AddQuietFlag(fs *pflag.FlagSet) {
+	quiet.AddFlag(fs)
+}
+
+// AddProfileFlag adds the '--profile' flag to the given set of command line flags.
+func AddProfileFlag(fs *pflag.FlagSet) {
+	profile.AddFlag(fs)
+}
+
+// AddNoColorFlag adds the '--no-color' flag to the given set of command line flags.
+func AddNoColorFlag(fs *pflag.FlagSet) {
+	color.AddFlag(fs)
+}
+
 // AddParameterFlag adds the '--parameter' flag to the given set of command line flags.
 func AddParameterFlag(fs *pflag.FlagSet, values *[]string) {
 	fs.StringArrayVarP(
@@ -80,23 +127,119 @@ func AddHeaderFlag(fs *pflag.FlagSet, values *[]string) {
 		"header",
 		nil,
 		"Headers to add to the request. The value must be the name of the header "+
-			"followed by an optional equals sign and then the value of the "+
-			"header. Can be used multiple times to specify multiple headers "+
-			"or multiple values for the same header.",
+			"followed by an optional equals sign or colon and then the value of the "+
+			"header, for example 'Prefer: return=minimal'. Can be used multiple times to "+
+			"specify multiple headers or multiple values for the same header. The "+
+			"'Authorization' header can't be changed.",
+	)
+}
+
+// AddIDOnlyFlag adds the '--id-only' flag, used by the commands that list objects, to the given
+// set of command line flags.
+func AddIDOnlyFlag(fs *pflag.FlagSet, value *bool) {
+	fs.BoolVar(
+		value,
+		"id-only",
+		false,
+		"Print only the identifiers of the objects, one per line and without headers, for use "+
+			"in shell loops.",
+	)
+}
+
+// AddPrettyFlags adds the '--pretty' and '--compact' flags to the given set of command line
+// flags. Use IsCompact to decide how to write the JSON documents.
+func AddPrettyFlags(fs *pflag.FlagSet, pretty, compact *bool) {
+	fs.BoolVar(
+		pretty,
+		"pretty",
+		false,
+		"Indent the JSON documents. This is the default when the output is a terminal.",
+	)
+	fs.BoolVar(
+		compact,
+		"compact",
+		false,
+		"Write the JSON documents in a single line, without indentation. This is the default "+
+			"when the output isn't a terminal, for example when it is piped to another command.",
 	)
 }
 
+// IsCompact checks if the JSON documents written to the given stream should be compact, according
+// to the values of the '--pretty' and '--compact' flags. When none of them is given the documents
+// are compact unless the stream is a terminal.
+func IsCompact(stream io.Writer, pretty, compact bool) bool {
+	if pretty || compact {
+		return compact
+	}
+	return !output.IsTerminal(stream)
+}
+
 // AddBodyFlag adds the '--body' flag to the given set of command line flags.
 func AddBodyFlag(fs *pflag.FlagSet, value *string) {
 	fs.StringVar(
 		value,
 		"body",
 		"",
-		"Name of the file containing the request body. If this isn't given then "+
-			"the body will be taken from the standard input.",
+		"Name of the file containing the request body. If this isn't given, or if it is '-', "+
+			"then the body will be taken from the standard input. The body must be valid JSON.",
 	)
 }
 
+// AddFilterFlag adds the '--filter' flag to the given set of command line flags.
+func AddFilterFlag(fs *pflag.FlagSet, value *string) {
+	fs.StringVar(
+		value,
+		"filter",
+		"",
+		"JSONPath expression used to select the parts of the response body that will be "+
+			"printed, for example '$.items[*].id'. Strings are printed without quotes.",
+	)
+}
+
+// ParseFilterFlag parses the value of the '--filter' command line flag. It returns nil if the flag
+// wasn't used.
+func ParseFilterFlag(value string) (*jsonpath.Path, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return jsonpath.Parse(value)
+}
+
+// CheckSearch checks the basic syntax of the given search expression, so that simple mistakes like
+// unbalanced quotes or parenthesis are reported before sending it to the server. The complete
+// syntax is checked by the server.
+func CheckSearch(search string) error {
+	if strings.TrimSpace(search) == "" {
+		return fmt.Errorf("it is empty")
+	}
+	depth := 0
+	quoted := false
+	for i := 0; i < len(search); i++ {
+		c := search[i]
+		switch {
+		case c == '\'':
+			// Quotes inside strings are escaped doubling them, which is equivalent to closing
+			// the string and opening it again, so it doesn't need special treatment:
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unexpected closing parenthesis at position %d", i+1)
+			}
+		}
+	}
+	if quoted {
+		return fmt.Errorf("missing closing quote")
+	}
+	if depth > 0 {
+		return fmt.Errorf("missing closing parenthesis")
+	}
+	return nil
+}
+
 // AddCCSFlagsWithoutAccountID is sufficient for list regions command.
 func AddCCSFlagsWithoutAccountID(fs *pflag.FlagSet, value *cluster.CCS) {
 	fs.BoolVar(
@@ -317,9 +460,70 @@ func ApplyParameterFlag(request interface{}, values []string) {
 	applyNVFlag(request, "Parameter", values)
 }
 
-// ApplyHeaderFlag applies the value of the '--header' command line flag to the given request.
-func ApplyHeaderFlag(request interface{}, values []string) {
-	applyNVFlag(request, "Header", values)
+// ApplyHeaderFlag applies the value of the '--header' command line flag to the given request. It
+// fails without changing the request if any of the values isn't a valid header, or if it tries to
+// replace the authorization header, as that always contains the token of the current session.
+func ApplyHeaderFlag(request interface{}, values []string) error {
+	pairs := make([]string, len(values))
+	for i, value := range values {
+		name, text, err := ParseHeader(value)
+		if err != nil {
+			return fmt.Errorf("Invalid header '%s': %v", value, err)
+		}
+		pairs[i] = name + "=" + text
+	}
+	applyNVFlag(request, "Header", pairs)
+	return nil
+}
+
+// ParseHeader parses a header given as the name followed by an equals sign or a colon and the value,
+// like 'Accept=application/json' or 'Prefer: return=minimal'. The separator is the first equals
+// sign or colon. White space around the name and, when the separator is a colon, before the value
+// is removed. A value without separator is a header with an empty value.
+func ParseHeader(text string) (name, value string, err error) {
+	position := strings.IndexAny(text, "=:")
+	if position != -1 {
+		name = strings.TrimSpace(text[:position])
+		value = text[position+1:]
+		if text[position] == ':' {
+			value = strings.TrimLeft(value, " \t")
+		}
+	} else {
+		name = strings.TrimSpace(text)
+	}
+	if name == "" {
+		err = fmt.Errorf("the name of the header is empty")
+		return
+	}
+	for _, char := range name {
+		if !isTokenChar(char) {
+			err = fmt.Errorf("the name of the header contains the invalid character %q", char)
+			return
+		}
+	}
+	for _, char := range value {
+		if char != '\t' && (char < ' ' || char == 0x7f) {
+			err = fmt.Errorf("the value of the header contains the control character %q", char)
+			return
+		}
+	}
+	if strings.EqualFold(name, "Authorization") {
+		err = fmt.Errorf("the 'Authorization' header can't be changed, it always contains " +
+			"the token of the current session")
+		return
+	}
+	return
+}
+
+// isTokenChar checks if the given character can be used in the name of an HTTP header, as
+// specified in RFC 7230.
+func isTokenChar(char rune) bool {
+	switch {
+	case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		return true
+	default:
+		return strings.ContainsRune("!#$%&'*+-.^_`|~", char)
+	}
 }
 
 // applyNVFlag finds the method with the given name in a request and calls it to set a collection of
@@ -343,11 +547,12 @@ func applyNVFlag(request interface{}, method string, values []string) {
 	}
 }
 
-// ApplyBodyFlag applies the value of the '--body' command line flag to the given request.
+// ApplyBodyFlag applies the value of the '--body' command line flag to the given request. The body
+// is read from the standard input if the value is empty or '-', and it must be valid JSON.
 func ApplyBodyFlag(request *sdk.Request, value string) error {
 	var body []byte
 	var err error
-	if value != "" {
+	if value != "" && value != "-" {
 		// #nosec G304
 		body, err = os.ReadFile(value)
 	} else {
@@ -359,6 +564,17 @@ func ApplyBodyFlag(request *sdk.Request, value string) error {
 	if err != nil {
 		return err
 	}
+
+	// Check the body before sending it, so that the position of the error can be reported. An
+	// empty body is allowed, as some requests don't need one.
+	if len(bytes.TrimSpace(body)) > 0 {
+		var parsed interface{}
+		err = json.Unmarshal(body, &parsed)
+		if err != nil {
+			return utils.DescribeJSONError(body, err)
+		}
+	}
+
 	request.Bytes(body)
 	return nil
 }
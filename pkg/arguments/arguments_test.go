@@ -0,0 +1,43 @@
+package arguments
+
+import (
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		text  string
+		name  string
+		value string
+		fails bool
+	}{
+		{text: "Accept=application/json", name: "Accept", value: "application/json"},
+		{text: "Prefer: return=minimal", name: "Prefer", value: "return=minimal"},
+		{text: "X-Time=10:30", name: "X-Time", value: "10:30"},
+		{text: " X-Empty ", name: "X-Empty", value: ""},
+		{text: "X-Spaces= a b", name: "X-Spaces", value: " a b"},
+		{text: "", fails: true},
+		{text: ": value", fails: true},
+		{text: "My Header: value", fails: true},
+		{text: "X-Bad: a\r\nb", fails: true},
+		{text: "authorization: Bearer abc", fails: true},
+		{text: "Authorization=Bearer abc", fails: true},
+	}
+	for _, test := range tests {
+		name, value, err := ParseHeader(test.text)
+		if test.fails {
+			if err == nil {
+				t.Errorf("expected header %q to fail, got name %q and value %q", test.text, name, value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expected header %q to succeed, got: %v", test.text, err)
+			continue
+		}
+		if name != test.name || value != test.value {
+			t.Errorf("expected header %q to be %q and %q, got %q and %q", test.text, test.name,
+				test.value, name, value)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions that handle the default cluster selected with the
+// 'ocm cluster use' command.
+
+package arguments
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift-online/ocm-cli/pkg/config"
+)
+
+// UseDefaultCluster makes the '--cluster' flag of the given command mandatory unless a default
+// cluster has been selected with the 'ocm cluster use' command. In that case the value is set to
+// the default cluster before the command runs. A value given explicitly in the command line always
+// takes precedence.
+func UseDefaultCluster(cmd *cobra.Command, value *string) {
+	preRunE := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, argv []string) error {
+		if !cmd.Flags().Changed("cluster") {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("Can't load config file: %v", err)
+			}
+			if cfg.Cluster == "" {
+				return fmt.Errorf(
+					"Option '--cluster' is mandatory unless a default cluster has been " +
+						"selected with 'ocm cluster use'",
+				)
+			}
+			*value = cfg.Cluster
+		}
+		if preRunE != nil {
+			return preRunE(cmd, argv)
+		}
+		return nil
+	}
+}
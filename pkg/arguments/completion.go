@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the shell completion functions that are shared by multiple commands.
+
+package arguments
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift-online/ocm-cli/pkg/cluster"
+	"github.com/openshift-online/ocm-cli/pkg/ocm"
+	"github.com/openshift-online/ocm-cli/pkg/output"
+	"github.com/openshift-online/ocm-cli/pkg/urls"
+)
+
+// maxClusterCompletions is the maximum number of clusters offered as completion candidates.
+const maxClusterCompletions = 50
+
+// CompleteCluster completes the values of the '--cluster' flag with the names and identifiers of
+// the most recently created clusters. Unlike the functions created by MakeCompleteFunc it doesn't
+// report errors, for example when the user isn't logged in, because they would be mixed with the
+// completion candidates, it just returns no candidates.
+func CompleteCluster(cmd *cobra.Command, args []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if toComplete != "" && !cluster.IsValidClusterKey(toComplete) {
+		return nil, directive
+	}
+
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return nil, directive
+	}
+	defer connection.Close()
+
+	request := connection.ClustersMgmt().V1().Clusters().List().
+		Order("creation_timestamp desc").
+		Size(maxClusterCompletions)
+	if toComplete != "" {
+		// The key has been checked above, so it is safe to use it in the search:
+		request = request.Search(fmt.Sprintf(
+			"name like '%s%%' or id like '%s%%'", toComplete, toComplete,
+		))
+	}
+	response, err := request.Send()
+	if err != nil {
+		return nil, directive
+	}
+
+	completions := []string{}
+	for _, item := range response.Items().Slice() {
+		// Cobra uses \t char to separate values from optional descriptions.
+		if strings.HasPrefix(item.Name(), toComplete) {
+			completions = append(completions, item.Name()+"\t"+item.ID())
+		}
+		if toComplete != "" && strings.HasPrefix(item.ID(), toComplete) {
+			completions = append(completions, item.ID()+"\t"+item.Name())
+		}
+	}
+	return completions, directive
+}
+
+// CompleteMappingMethod completes the values of the '--mapping-method' flag of the commands that
+// create and edit identity providers.
+func CompleteMappingMethod(cmd *cobra.Command, args []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	return cluster.MappingMethods, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteOutput returns a function that completes the values of the '--output' flag with the
+// given formats, the same list that commands use in the help and error messages of the flag. The
+// descriptions of the template formats, like 'go-template=...', are completed to the prefix, and
+// no space is added after it so that the user can type the template.
+func CompleteOutput(formats []string) CobraCompletionFunc {
+	return func(cmd *cobra.Command, args []string,
+		toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions := []string{}
+		templates := 0
+		for _, format := range formats {
+			switch format {
+			case output.TemplatePrefix + "...":
+				format = output.TemplatePrefix
+			case output.TemplateFilePrefix + "...":
+				format = output.TemplateFilePrefix
+			}
+			if !strings.HasPrefix(format, toComplete) {
+				continue
+			}
+			if output.IsTemplate(format) {
+				templates++
+			}
+			completions = append(completions, format)
+		}
+		directive := cobra.ShellCompDirectiveNoFileComp
+		if templates > 0 && templates == len(completions) {
+			directive |= cobra.ShellCompDirectiveNoSpace
+		}
+		return completions, directive
+	}
+}
+
+// CompleteResource completes the first argument of the commands that send raw requests, like
+// 'ocm get', with the resource aliases and the well known API paths. It doesn't need a connection
+// to the API. Paths are completed without adding a space, so that an identifier can be added.
+func CompleteResource(cmd *cobra.Command, args []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if len(args) > 0 {
+		return nil, directive
+	}
+	completions := []string{}
+	if strings.HasPrefix(toComplete, "/") {
+		for _, path := range urls.Paths() {
+			if strings.HasPrefix(path, toComplete) {
+				completions = append(completions, path)
+			}
+		}
+		return completions, directive | cobra.ShellCompDirectiveNoSpace
+	}
+	resources := urls.Resources()
+	sort.Strings(resources)
+	for _, resource := range resources {
+		if strings.HasPrefix(resource, toComplete) {
+			completions = append(completions, resource)
+		}
+	}
+	if toComplete == "" {
+		completions = append(completions, urls.Paths()...)
+	}
+	return completions, directive
+}
@@ -20,6 +20,7 @@ package arguments
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -47,6 +48,12 @@ func AddInteractiveFlag(flags *pflag.FlagSet, value *bool) {
 	)
 }
 
+// PromptStdio returns the option that makes the prompts write to the standard error, so that the
+// standard output contains only the results of the command and can be safely piped.
+func PromptStdio() survey.AskOpt {
+	return survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)
+}
+
 // SetQuestion sets a friendlier text to use when prompting instead of flag name.
 func SetQuestion(fs *pflag.FlagSet, flagName, question string) {
 	fs.SetAnnotation(flagName, questionAnnotationKey, []string{question})
@@ -151,7 +158,7 @@ func PromptBool(fs *pflag.FlagSet, flagName string) error {
 				Default: value,
 			}
 			var response bool
-			err = survey.AskOne(prompt, &response)
+			err = survey.AskOne(prompt, &response, PromptStdio())
 			if err != nil {
 				return err
 			}
@@ -191,7 +198,7 @@ func PromptInt(fs *pflag.FlagSet, flagName string, validate func() error) error
 				}
 				return nil
 			}
-			return survey.AskOne(prompt, &response, survey.WithValidator(validator))
+			return survey.AskOne(prompt, &response, survey.WithValidator(validator), PromptStdio())
 		}
 		return nil
 	})
@@ -214,7 +221,7 @@ func PromptString(fs *pflag.FlagSet, flagName string) error {
 				Help:    flag.Usage,
 				Default: value,
 			}
-			err = survey.AskOne(prompt, &response)
+			err = survey.AskOne(prompt, &response, PromptStdio())
 			if err != nil {
 				return err
 			}
@@ -241,7 +248,7 @@ func PromptPassword(fs *pflag.FlagSet, flagName string) error {
 				Message: getQuestion(flag),
 				Help:    flag.Usage,
 			}
-			err = survey.AskOne(prompt, &response)
+			err = survey.AskOne(prompt, &response, PromptStdio())
 			if err != nil {
 				return err
 			}
@@ -272,7 +279,7 @@ func PromptFilePath(fs *pflag.FlagSet, flagName string) error {
 				},
 			}
 			var response string
-			err := survey.AskOne(prompt, &response)
+			err := survey.AskOne(prompt, &response, PromptStdio())
 			if err != nil {
 				return err
 			}
@@ -312,7 +319,7 @@ func PromptIPNet(fs *pflag.FlagSet, flagName string) error {
 			}
 			return fs.Set(flagName, str)
 		}
-		return survey.AskOne(prompt, &response, survey.WithValidator(validator))
+		return survey.AskOne(prompt, &response, survey.WithValidator(validator), PromptStdio())
 	})
 }
 
@@ -364,7 +371,7 @@ func doPromptOneOf(fs *pflag.FlagSet, flagName string, options []Option) error {
 			Default: defaultValue,
 		}
 		var response string
-		err = survey.AskOne(prompt, &response)
+		err = survey.AskOne(prompt, &response, PromptStdio())
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,187 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to install add-ons on clusters.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// GetAddOn returns the add-on with the given identifier, including the parameters that it
+// accepts.
+func GetAddOn(client *cmv1.Client, addOnID string) (*cmv1.AddOn, error) {
+	response, err := client.Addons().Addon(addOnID).Get().Send()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get add-on '%s': %v", addOnID, err)
+	}
+	return response.Body(), nil
+}
+
+// IsFinalAddOnState checks if the given state of an add-on installation will not change without
+// the intervention of the user.
+func IsFinalAddOnState(state cmv1.AddOnInstallationState) bool {
+	switch state {
+	case cmv1.AddOnInstallationStateReady,
+		cmv1.AddOnInstallationStateFailed,
+		cmv1.AddOnInstallationStateDeleting:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForAddOnInstallation polls the installation of the given add-on till it is in a final
+// state, as defined by IsFinalAddOnState, and returns its latest version. If the changed function
+// isn't nil it is called with the first version of the installation and then each time that its
+// state changes. If the context expires before the installation is in a final state it returns
+// context.DeadlineExceeded.
+func WaitForAddOnInstallation(ctx context.Context, client *cmv1.ClustersClient, clusterID string,
+	addOnID string, interval time.Duration,
+	changed func(*cmv1.AddOnInstallation)) (installation *cmv1.AddOnInstallation, err error) {
+	var last cmv1.AddOnInstallationState
+	response, err := client.Cluster(clusterID).Addons().Addoninstallation(addOnID).Poll().
+		Interval(interval).
+		Predicate(func(response *cmv1.AddOnInstallationGetResponse) bool {
+			current := response.Body()
+			if changed != nil && (last == "" || current.State() != last) {
+				changed(current)
+			}
+			last = current.State()
+			return IsFinalAddOnState(current.State())
+		}).
+		StartContext(ctx)
+	if err != nil {
+		return
+	}
+	installation = response.Body()
+
+	// The poller stops without an error when there isn't time for another check before the
+	// deadline, so the state needs to be checked again:
+	if !IsFinalAddOnState(installation.State()) {
+		err = context.DeadlineExceeded
+	}
+	return
+}
+
+// ValidateAddOnParameters checks the given parameter values against the parameters declared by
+// the add-on. It rejects unknown parameters, values that don't match the type, the options or the
+// validation expression of the parameter, and required parameters that have no value and no
+// default.
+func ValidateAddOnParameters(addOn *cmv1.AddOn, values map[string]string) error {
+	declared := map[string]*cmv1.AddOnParameter{}
+	for _, parameter := range addOn.Parameters().Slice() {
+		if parameter.Enabled() {
+			declared[parameter.ID()] = parameter
+		}
+	}
+
+	// Check the parameters in a predictable order, so that errors are always the same:
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parameter, ok := declared[key]
+		if !ok {
+			names := make([]string, 0, len(declared))
+			for name := range declared {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if len(names) == 0 {
+				return fmt.Errorf("Add-on '%s' doesn't accept parameters, but '%s' was given",
+					addOn.ID(), key)
+			}
+			return fmt.Errorf("Unknown parameter '%s' for add-on '%s', valid parameters are: %s",
+				key, addOn.ID(), strings.Join(names, ", "))
+		}
+		err := validateAddOnParameterValue(parameter, values[key])
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for parameter '%s' of add-on '%s': %v",
+				values[key], key, addOn.ID(), err)
+		}
+	}
+
+	for _, parameter := range addOn.Parameters().Slice() {
+		if !parameter.Enabled() || !parameter.Required() || parameter.DefaultValue() != "" {
+			continue
+		}
+		if _, ok := values[parameter.ID()]; !ok {
+			return fmt.Errorf("Parameter '%s' is required by add-on '%s'", parameter.ID(), addOn.ID())
+		}
+	}
+	return nil
+}
+
+// validateAddOnParameterValue checks that the given value is valid for the given parameter.
+func validateAddOnParameterValue(parameter *cmv1.AddOnParameter, value string) error {
+	switch strings.ToLower(parameter.ValueType()) {
+	case "number":
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+	case "boolean":
+		_, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected 'true' or 'false'")
+		}
+	case "cidr":
+		_, _, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("expected a CIDR block like '10.0.0.0/16'")
+		}
+	}
+
+	options := parameter.Options()
+	if len(options) > 0 {
+		valid := make([]string, len(options))
+		for i, option := range options {
+			if option.Value() == value {
+				return nil
+			}
+			valid[i] = option.Value()
+		}
+		return fmt.Errorf("expected one of: %s", strings.Join(valid, ", "))
+	}
+
+	if parameter.Validation() != "" {
+		matched, err := regexp.MatchString(parameter.Validation(), value)
+		if err != nil {
+			return fmt.Errorf("failed to check validation expression '%s': %v",
+				parameter.Validation(), err)
+		}
+		if !matched {
+			if parameter.ValidationErrMsg() != "" {
+				return fmt.Errorf("%s", parameter.ValidationErrMsg())
+			}
+			return fmt.Errorf("expected a value matching '%s'", parameter.Validation())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestValidateAddOnParameters(t *testing.T) {
+	addOn, err := cmv1.NewAddOn().
+		ID("my-addon").
+		Parameters(cmv1.NewAddOnParameterList().Items(
+			cmv1.NewAddOnParameter().
+				ID("size").
+				ValueType("number").
+				Enabled(true).
+				Required(true),
+			cmv1.NewAddOnParameter().
+				ID("email").
+				ValueType("string").
+				Enabled(true).
+				Validation("^[^@]+@[^@]+$").
+				ValidationErrMsg("expected an email address"),
+			cmv1.NewAddOnParameter().
+				ID("tier").
+				ValueType("string").
+				Enabled(true).
+				Options(
+					cmv1.NewAddOnParameterOption().Name("Small").Value("small"),
+					cmv1.NewAddOnParameterOption().Name("Large").Value("large"),
+				),
+			cmv1.NewAddOnParameter().
+				ID("legacy").
+				ValueType("string").
+				Enabled(false),
+		)).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build add-on: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		err    string
+	}{
+		{
+			name:   "Valid values",
+			values: map[string]string{"size": "3", "email": "me@example.com", "tier": "large"},
+		},
+		{
+			name:   "Unknown parameter",
+			values: map[string]string{"size": "3", "color": "red"},
+			err: "Unknown parameter 'color' for add-on 'my-addon', valid parameters are: " +
+				"email, size, tier",
+		},
+		{
+			name:   "Disabled parameter",
+			values: map[string]string{"size": "3", "legacy": "x"},
+			err:    "Unknown parameter 'legacy'",
+		},
+		{
+			name:   "Missing required parameter",
+			values: map[string]string{"tier": "small"},
+			err:    "Parameter 'size' is required by add-on 'my-addon'",
+		},
+		{
+			name:   "Invalid number",
+			values: map[string]string{"size": "three"},
+			err:    "Invalid value 'three' for parameter 'size' of add-on 'my-addon': expected a number",
+		},
+		{
+			name:   "Value not matching the validation",
+			values: map[string]string{"size": "3", "email": "me"},
+			err:    "expected an email address",
+		},
+		{
+			name:   "Value not in the options",
+			values: map[string]string{"size": "3", "tier": "medium"},
+			err:    "expected one of: small, large",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAddOnParameters(addOn, test.values)
+			if test.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing '%s', got nothing", test.err)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error containing '%s', got '%v'", test.err, err)
+			}
+		})
+	}
+}
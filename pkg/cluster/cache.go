@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Cache remembers the clusters found with GetCluster, so that a command that needs the same
+// cluster in several places retrieves it from the API only once. It is intended to live as long
+// as one command, and it isn't safe for concurrent use.
+type Cache struct {
+	connection *sdk.Connection
+	clusters   map[string]*cmv1.Cluster
+}
+
+// NewCache creates a cache that uses the given connection to retrieve the clusters that it
+// doesn't contain yet.
+func NewCache(connection *sdk.Connection) *Cache {
+	return &Cache{
+		connection: connection,
+		clusters:   map[string]*cmv1.Cluster{},
+	}
+}
+
+// Get returns the cluster that has the given internal identifier, external identifier or name,
+// retrieving it from the API only the first time.
+func (c *Cache) Get(ctx context.Context, key string) (*cmv1.Cluster, error) {
+	cluster, ok := c.clusters[key]
+	if ok {
+		return cluster, nil
+	}
+	cluster, err := GetClusterContext(ctx, c.connection, key)
+	if err != nil {
+		return nil, err
+	}
+	c.clusters[key] = cluster
+	c.clusters[cluster.ID()] = cluster
+	return cluster, nil
+}
+
+// Update replaces the cached copies of the given cluster, for example after waiting for a change
+// of its state.
+func (c *Cache) Update(cluster *cmv1.Cluster) {
+	for key, cached := range c.clusters {
+		if cached.ID() == cluster.ID() {
+			c.clusters[key] = cluster
+		}
+	}
+	c.clusters[cluster.ID()] = cluster
+}
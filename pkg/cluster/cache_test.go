@@ -0,0 +1,27 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestCacheUpdate(t *testing.T) {
+	// The cache has no connection, so any request to the API would fail:
+	cache := NewCache(nil)
+	cache.clusters["mycluster"] = newTestCluster(t, cmv1.NewCluster().ID("123").
+		State(cmv1.ClusterStateInstalling))
+	cache.Update(newTestCluster(t, cmv1.NewCluster().ID("123").State(cmv1.ClusterStateReady)))
+
+	for _, key := range []string{"mycluster", "123"} {
+		cluster, err := cache.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error for key '%s': %v", key, err)
+		}
+		if cluster.State() != cmv1.ClusterStateReady {
+			t.Errorf("expected state '%s' for key '%s', got '%s'", cmv1.ClusterStateReady, key,
+				cluster.State())
+		}
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -159,7 +160,21 @@ func IsValidClusterKey(clusterKey string) bool {
 	return clusterKeyRE.MatchString(clusterKey)
 }
 
+// maxClusterCandidates is the maximum number of matching clusters that are retrieved, and listed
+// in the error message, when a cluster name is ambiguous.
+const maxClusterCandidates = 10
+
+// GetCluster finds the cluster that has the given internal identifier, external identifier or
+// name. Identifiers are unique, but names aren't, so if the key is the name of multiple clusters
+// it returns an error listing them instead of picking one. When nothing matches exactly the key
+// can also be a prefix of the name of a single cluster.
 func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster, err error) {
+	return GetClusterContext(context.Background(), connection, key)
+}
+
+// GetClusterContext is like GetCluster, but the requests to the API use the given context.
+func GetClusterContext(ctx context.Context, connection *sdk.Connection,
+	key string) (cluster *cmv1.Cluster, err error) {
 	// Prepare the resources that we will be using:
 	subsResource := connection.AccountsMgmt().V1().Subscriptions()
 	clustersResource := connection.ClustersMgmt().V1().Clusters()
@@ -171,17 +186,29 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 	)
 	subsListResponse, err := subsResource.List().
 		Search(subsSearch).
-		Size(1).
-		Send()
+		Size(maxClusterCandidates).
+		SendContext(ctx)
 	if err != nil {
 		err = fmt.Errorf("Can't retrieve subscription for key '%s': %v", key, err)
 		return
 	}
 
-	// If there is exactly one matching subscription then return the corresponding cluster:
+	// If there is exactly one matching subscription, or one of them matches by identifier, then
+	// return the corresponding cluster:
 	subsTotal := subsListResponse.Total()
-	if subsTotal == 1 {
-		sub := subsListResponse.Items().Slice()[0]
+	subs := subsListResponse.Items().Slice()
+	var sub *amsv1.Subscription
+	if subsTotal == 1 && len(subs) == 1 {
+		sub = subs[0]
+	} else {
+		for _, item := range subs {
+			if item.ClusterID() == key || item.ExternalClusterID() == key {
+				sub = item
+				break
+			}
+		}
+	}
+	if sub != nil {
 		status, ok := sub.GetStatus()
 		subID, _ := sub.GetID()
 		if !ok || (status != "Reserved" && status != "Active") {
@@ -192,7 +219,7 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 		if ok {
 			var clusterGetResponse *cmv1.ClusterGetResponse
 			clusterGetResponse, err = clustersResource.Cluster(id).Get().
-				Send()
+				SendContext(ctx)
 			if err != nil {
 				err = fmt.Errorf(
 					"Can't retrieve cluster for key '%s': %v",
@@ -208,9 +235,14 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 	// If there are multiple subscriptions that match the cluster then we should report it as
 	// an error:
 	if subsTotal > 1 {
+		candidates := make([]string, len(subs))
+		for i, item := range subs {
+			candidates[i] = fmt.Sprintf("%s (%s)", item.ClusterID(), item.DisplayName())
+		}
 		err = fmt.Errorf(
-			"There are %d subscriptions with cluster identifier or name '%s'",
-			subsTotal, key,
+			"There are %d subscriptions with cluster identifier or name '%s', use one of "+
+				"the cluster identifiers instead: %s",
+			subsTotal, key, strings.Join(candidates, ", "),
 		)
 		return
 	}
@@ -225,29 +257,49 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 	)
 	clustersListResponse, err := clustersResource.List().
 		Search(clustersSearch).
-		Size(1).
-		Send()
+		Size(maxClusterCandidates).
+		SendContext(ctx)
 	if err != nil {
 		err = fmt.Errorf("Can't retrieve clusters for key '%s': %v", key, err)
 		return
 	}
 
-	// If there is exactly one cluster matching then return it:
+	// If there is exactly one cluster matching, or one of them matches by identifier, then
+	// return it:
 	clustersTotal := clustersListResponse.Total()
-	if clustersTotal == 1 {
-		cluster = clustersListResponse.Items().Slice()[0]
+	clusters := clustersListResponse.Items().Slice()
+	if clustersTotal == 1 && len(clusters) == 1 {
+		cluster = clusters[0]
 		return
 	}
+	for _, item := range clusters {
+		if item.ID() == key || item.ExternalID() == key {
+			cluster = item
+			return
+		}
+	}
 
 	// If there are multiple matching clusters then we should report it as an error:
 	if clustersTotal > 1 {
+		candidates := make([]string, len(clusters))
+		for i, item := range clusters {
+			candidates[i] = fmt.Sprintf("%s (%s)", item.ID(), item.Name())
+		}
 		err = fmt.Errorf(
-			"There are %d clusters with identifier or name '%s'",
-			clustersTotal, key,
+			"There are %d clusters with identifier or name '%s', use one of the identifiers "+
+				"instead: %s",
+			clustersTotal, key, strings.Join(candidates, ", "),
 		)
 		return
 	}
 
+	// If no identifier or name matches exactly then the key may be the beginning of the name,
+	// so that users don't need to type complete names:
+	cluster, err = getClusterByPrefix(ctx, clustersResource, key)
+	if cluster != nil || err != nil {
+		return
+	}
+
 	// If we are here then there are no subscriptions or clusters matching the passed key:
 	err = fmt.Errorf(
 		"There are no subscriptions or clusters with identifier or name '%s'",
@@ -256,6 +308,150 @@ func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster,
 	return
 }
 
+// IsFinalState checks if the given state is one where the cluster will stay unless the user does
+// something, so that it doesn't make sense to wait for it to become ready.
+func IsFinalState(state cmv1.ClusterState) bool {
+	switch state {
+	case cmv1.ClusterStateReady,
+		cmv1.ClusterStateError,
+		cmv1.ClusterStateHibernating,
+		cmv1.ClusterStateUninstalling:
+		return true
+	}
+	return false
+}
+
+// hibernationProducts are the identifiers of the products whose clusters can be hibernated.
+var hibernationProducts = []string{"osd", "osdtrial"}
+
+// CheckHibernationSupported checks if the product of the given cluster supports hibernation, and
+// returns an error explaining why if it doesn't.
+func CheckHibernationSupported(cluster *cmv1.Cluster) error {
+	if cluster.Hypershift().Enabled() {
+		return fmt.Errorf("hibernation isn't supported for clusters with hosted control planes")
+	}
+	product := cluster.Product().ID()
+	for _, supported := range hibernationProducts {
+		if strings.EqualFold(product, supported) {
+			return nil
+		}
+	}
+	return fmt.Errorf("hibernation isn't supported for clusters of product '%s', only for "+
+		"products: %s", product, strings.Join(hibernationProducts, ", "))
+}
+
+// WaitForCluster polls the cluster with the given identifier till it is in a final state, as
+// defined by IsFinalState, and returns its latest version. If the changed function isn't nil it is
+// called with the first version of the cluster and then each time that its state changes. If the
+// context expires before the cluster is in a final state it returns context.DeadlineExceeded.
+func WaitForCluster(ctx context.Context, client *cmv1.ClustersClient, clusterID string,
+	interval time.Duration, changed func(*cmv1.Cluster)) (cluster *cmv1.Cluster, err error) {
+	var last cmv1.ClusterState
+	response, err := client.Cluster(clusterID).Poll().
+		Interval(interval).
+		Predicate(func(response *cmv1.ClusterGetResponse) bool {
+			current := response.Body()
+			if changed != nil && (last == "" || current.State() != last) {
+				changed(current)
+			}
+			last = current.State()
+			return IsFinalState(current.State())
+		}).
+		StartContext(ctx)
+	if err != nil {
+		return
+	}
+	cluster = response.Body()
+
+	// The poller stops without an error when there isn't time for another check before the
+	// deadline, so the state needs to be checked again:
+	if !IsFinalState(cluster.State()) {
+		err = context.DeadlineExceeded
+	}
+	return
+}
+
+// WaitForClusterDeletion polls the cluster with the given identifier till it doesn't exist. If
+// the changed function isn't nil it is called with the first version of the cluster and then each
+// time that its state changes. If the context expires before the cluster is deleted it returns
+// context.DeadlineExceeded.
+func WaitForClusterDeletion(ctx context.Context, client *cmv1.ClustersClient, clusterID string,
+	interval time.Duration, changed func(*cmv1.Cluster)) error {
+	var last cmv1.ClusterState
+	response, err := client.Cluster(clusterID).Poll().
+		Interval(interval).
+		Status(http.StatusNotFound).
+		Predicate(func(response *cmv1.ClusterGetResponse) bool {
+			if response.Status() == http.StatusOK {
+				current := response.Body()
+				if changed != nil && (last == "" || current.State() != last) {
+					changed(current)
+				}
+				last = current.State()
+			}
+			return true
+		}).
+		StartContext(ctx)
+
+	// The poller returns the error of the last request, which is expected once the cluster
+	// doesn't exist:
+	if response.Status() == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return context.DeadlineExceeded
+}
+
+// getClusterByPrefix finds the cluster whose name starts with the given prefix. It returns nil
+// without error if there is no such cluster, and an error listing the candidates if there are
+// several of them.
+func getClusterByPrefix(ctx context.Context, clustersResource *cmv1.ClustersClient,
+	prefix string) (cluster *cmv1.Cluster, err error) {
+	clustersListResponse, err := clustersResource.List().
+		Search(fmt.Sprintf("name like '%s%%'", prefix)).
+		Order("name asc").
+		Size(maxClusterCandidates).
+		SendContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("Can't retrieve clusters with name prefix '%s': %v", prefix, err)
+		return
+	}
+
+	// The underscore is a wildcard in 'like' expressions, so the names need to be checked to
+	// discard clusters that don't really start with the prefix:
+	clustersTotal := clustersListResponse.Total()
+	clusters := clustersListResponse.Items().Slice()
+	var matches []*cmv1.Cluster
+	for _, item := range clusters {
+		if strings.HasPrefix(item.Name(), prefix) {
+			matches = append(matches, item)
+		}
+	}
+	complete := clustersTotal == len(clusters)
+	if complete && len(matches) == 0 {
+		return
+	}
+	if complete && len(matches) == 1 {
+		cluster = matches[0]
+		return
+	}
+	if complete {
+		clustersTotal = len(matches)
+	}
+	candidates := make([]string, len(matches))
+	for i, item := range matches {
+		candidates[i] = fmt.Sprintf("%s (%s)", item.ID(), item.Name())
+	}
+	err = fmt.Errorf(
+		"There are %d clusters with a name that starts with '%s', use a longer prefix or one "+
+			"of the identifiers instead: %s",
+		clustersTotal, prefix, strings.Join(candidates, ", "),
+	)
+	return
+}
+
 func GetClusterLimitedSupportReasons(connection *sdk.Connection, clusterID string) ([]*lmtSprReasonItem, error) {
 
 	limitedSupportReasons, err := connection.ClustersMgmt().V1().
@@ -287,6 +483,38 @@ func GetClusterLimitedSupportReasons(connection *sdk.Connection, clusterID strin
 }
 
 func CreateCluster(cmv1Client *cmv1.Client, config Spec, dryRun bool) (*cmv1.Cluster, error) {
+	clusterSpec, err := BuildCluster(config)
+	if err != nil {
+		return nil, err
+	}
+	return AddCluster(cmv1Client, clusterSpec, dryRun)
+}
+
+// AddCluster sends the request to create the given cluster. In dry run mode the server only
+// validates it, and the returned cluster is nil.
+func AddCluster(cmv1Client *cmv1.Client, clusterSpec *cmv1.Cluster, dryRun bool) (*cmv1.Cluster, error) {
+	// Send a request to create the cluster:
+	request := cmv1Client.Clusters().Add().
+		Body(clusterSpec)
+	if dryRun {
+		request = request.Parameter("dryRun", "true")
+	}
+	response, err := request.Send()
+	if err != nil {
+		if dryRun {
+			return nil, fmt.Errorf("dry run: unable to create cluster: %v", err)
+		}
+		return nil, fmt.Errorf("unable to create cluster: %v", err)
+	}
+
+	if response.Status() == http.StatusNoContent {
+		return nil, nil
+	}
+	return response.Body(), nil
+}
+
+// BuildCluster creates the description of the cluster that is sent to the server to create it.
+func BuildCluster(config Spec) (*cmv1.Cluster, error) {
 	clusterProperties := map[string]string{}
 
 	if config.CustomProperties != nil {
@@ -463,25 +691,7 @@ func CreateCluster(cmv1Client *cmv1.Client, config Spec, dryRun bool) (*cmv1.Clu
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create description of cluster: %v", err)
 	}
-
-	// Send a request to create the cluster:
-	request := cmv1Client.Clusters().Add().
-		Body(clusterSpec)
-	if dryRun {
-		request = request.Parameter("dryRun", "true")
-	}
-	response, err := request.Send()
-	if err != nil {
-		if dryRun {
-			return nil, fmt.Errorf("dry run: unable to create cluster: %v", err)
-		}
-		return nil, fmt.Errorf("unable to create cluster: %v", err)
-	}
-
-	if response.Status() == http.StatusNoContent {
-		return nil, nil
-	}
-	return response.Body(), nil
+	return clusterSpec, nil
 }
 
 func isGCPNetworkExists(existingVPC ExistingVPC) bool {
@@ -579,17 +789,94 @@ func GetClusterOauthURL(cluster *cmv1.Cluster) string {
 	return oauthURL
 }
 
+// MappingMethods are the methods supported by the identity providers to map new identities to
+// users when they log in.
+var MappingMethods = []string{
+	string(cmv1.IdentityProviderMappingMethodClaim),
+	string(cmv1.IdentityProviderMappingMethodLookup),
+	string(cmv1.IdentityProviderMappingMethodGenerate),
+	string(cmv1.IdentityProviderMappingMethodAdd),
+}
+
+// ParseMappingMethod checks that the given value is one of the mapping methods supported by the
+// identity providers.
+func ParseMappingMethod(value string) (cmv1.IdentityProviderMappingMethod, error) {
+	for _, method := range MappingMethods {
+		if value == method {
+			return cmv1.IdentityProviderMappingMethod(value), nil
+		}
+	}
+	return "", fmt.Errorf("Invalid mapping method '%s', valid options are %s", value, MappingMethods)
+}
+
 func GetIdentityProviders(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.IdentityProvider, error) {
-	idpClient := client.Cluster(clusterID).IdentityProviders()
-	response, err := idpClient.List().
-		Page(1).
-		Size(-1).
-		Send()
+	return GetIdentityProvidersContext(context.Background(), client, clusterID)
+}
+
+// GetIdentityProvidersContext is like GetIdentityProviders, but the request to the API uses the
+// given context.
+func GetIdentityProvidersContext(ctx context.Context, client *cmv1.ClustersClient,
+	clusterID string) ([]*cmv1.IdentityProvider, error) {
+	idps, err := ListIdentityProviders(ctx, client.Cluster(clusterID).IdentityProviders())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get identity providers for cluster '%s': %v", clusterID, err)
 	}
+	return idps, nil
+}
 
-	return response.Items().Slice(), nil
+// identityProvidersPageSize is the number of identity providers requested in each page.
+const identityProvidersPageSize = 100
+
+// ListIdentityProviders retrieves all the identity providers of the collection, requesting the
+// pages one after the other till the total returned by the server has been retrieved, so that
+// providers beyond the first page aren't missed when looking for one by name.
+func ListIdentityProviders(ctx context.Context,
+	client *cmv1.IdentityProvidersClient) ([]*cmv1.IdentityProvider, error) {
+	var idps []*cmv1.IdentityProvider
+	for page := 1; ; page++ {
+		response, err := client.List().
+			Page(page).
+			Size(identityProvidersPageSize).
+			SendContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items := response.Items().Slice()
+		idps = append(idps, items...)
+		if len(items) == 0 || len(idps) >= response.Total() {
+			return idps, nil
+		}
+	}
+}
+
+// FindIdentityProvider finds the identity provider with the given name. If there is no identity
+// provider with that name it tries to use the value as an identifier. It fails if more than one
+// identity provider has the given name, as changing one of them could mean changing the wrong one.
+func FindIdentityProvider(idps []*cmv1.IdentityProvider, key string) (*cmv1.IdentityProvider, error) {
+	var matches []*cmv1.IdentityProvider
+	for _, item := range idps {
+		if item.Name() == key {
+			matches = append(matches, item)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		for _, item := range idps {
+			if item.ID() == key {
+				return item, nil
+			}
+		}
+		return nil, errors.New("identity provider doesn't exist")
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, item := range matches {
+			ids[i] = item.ID()
+		}
+		return nil, fmt.Errorf("there are %d identity providers with that name, use one of "+
+			"the identifiers instead: %s", len(matches), strings.Join(ids, ", "))
+	}
 }
 
 func GetIngresses(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.Ingress, error) {
@@ -644,6 +931,22 @@ func GetUpgradePolicies(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.
 	return response.Items().Slice(), nil
 }
 
+// GetUpgradePolicyState returns the state of the given upgrade policy, for example 'scheduled' or
+// 'started'.
+func GetUpgradePolicyState(client *cmv1.ClustersClient, clusterID string,
+	upgradePolicyID string) (*cmv1.UpgradePolicyState, error) {
+	response, err := client.Cluster(clusterID).UpgradePolicies().
+		UpgradePolicy(upgradePolicyID).
+		State().
+		Get().
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get state of upgrade policy '%s': %v", upgradePolicyID, err)
+	}
+
+	return response.Body(), nil
+}
+
 func GetClusterAddOns(connection *sdk.Connection, clusterID string) ([]*AddOnItem, error) {
 	// Get organization ID (used to get add-on quotas)
 	acctResponse, err := connection.AccountsMgmt().V1().CurrentAccount().
@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestFindIdentityProvider(t *testing.T) {
+	var idps []*cmv1.IdentityProvider
+	for _, values := range [][]string{
+		{"123", "github-1"},
+		{"456", "ldap-1"},
+		{"789", "ldap-1"},
+	} {
+		idp, err := cmv1.NewIdentityProvider().ID(values[0]).Name(values[1]).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		idps = append(idps, idp)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		id   string
+		err  string
+	}{
+		{
+			name: "By name",
+			key:  "github-1",
+			id:   "123",
+		},
+		{
+			name: "By identifier",
+			key:  "456",
+			id:   "456",
+		},
+		{
+			name: "Duplicated name",
+			key:  "ldap-1",
+			err: "there are 2 identity providers with that name, use one of the identifiers " +
+				"instead: 456, 789",
+		},
+		{
+			name: "Missing",
+			key:  "google-1",
+			err:  "identity provider doesn't exist",
+		},
+	}
+
+	for _, test := range tests {
+		idp, err := FindIdentityProvider(idps, test.key)
+		switch {
+		case test.err != "":
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected an error containing '%s', got '%v'", test.name, test.err, err)
+			}
+		case err != nil:
+			t.Errorf("%s: expected no error, got '%v'", test.name, err)
+		case idp.ID() != test.id:
+			t.Errorf("%s: expected identity provider '%s', got '%s'", test.name, test.id, idp.ID())
+		}
+	}
+}
@@ -18,11 +18,15 @@ package cluster
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift-online/ocm-cli/pkg/color"
 )
 
 const (
@@ -30,6 +34,16 @@ const (
 )
 
 func PrintClusterDescription(connection *sdk.Connection, cluster *cmv1.Cluster) error {
+	return printClusterDescription(connection, cluster, false)
+}
+
+// PrintClusterWideDescription prints the same description as PrintClusterDescription followed by
+// the details of the nodes and the network that are usually only needed for troubleshooting.
+func PrintClusterWideDescription(connection *sdk.Connection, cluster *cmv1.Cluster) error {
+	return printClusterDescription(connection, cluster, true)
+}
+
+func printClusterDescription(connection *sdk.Connection, cluster *cmv1.Cluster, wide bool) error {
 	// Get API URL:
 	api := cluster.API()
 	apiURL, _ := api.GetURL()
@@ -168,6 +182,11 @@ func PrintClusterDescription(connection *sdk.Connection, cluster *cmv1.Cluster)
 		)
 	}
 
+	state := string(cluster.State())
+	if color.Enabled(os.Stdout) {
+		state = color.State(state)
+	}
+
 	// Print short cluster description:
 	fmt.Printf("\n"+
 		"ID:			%s\n"+
@@ -179,7 +198,7 @@ func PrintClusterDescription(connection *sdk.Connection, cluster *cmv1.Cluster)
 		cluster.ExternalID(),
 		cluster.Name(),
 		sub.DisplayName(),
-		cluster.State(),
+		state,
 		provisioningStatus,
 	)
 
@@ -284,11 +303,44 @@ func PrintClusterDescription(connection *sdk.Connection, cluster *cmv1.Cluster)
 		fmt.Printf("Limited Support:	%t\n", cluster.Status().LimitedSupportReasonCount() > 0)
 	}
 
+	if wide {
+		printClusterWideDetails(cluster)
+	}
+
 	fmt.Println()
 
 	return nil
 }
 
+// printClusterWideDetails prints the details of the cluster that are added with the '--wide' flag.
+func printClusterWideDetails(cluster *cmv1.Cluster) {
+	nodes := cluster.Nodes()
+	network := cluster.Network()
+	fmt.Printf("Base Domain:		%s\n"+
+		"Availability Zones:	%s\n"+
+		"Master Machine Type:	%s\n"+
+		"Infra Machine Type:	%s\n"+
+		"Compute Machine Type:	%s\n"+
+		"Network Type:		%s\n"+
+		"Machine CIDR:		%s\n"+
+		"Service CIDR:		%s\n"+
+		"Pod CIDR:		%s\n"+
+		"Host Prefix:		%d\n"+
+		"Available Upgrades:	%s\n",
+		cluster.DNS().BaseDomain(),
+		strings.Join(nodes.AvailabilityZones(), ", "),
+		nodes.MasterMachineType().ID(),
+		nodes.InfraMachineType().ID(),
+		nodes.ComputeMachineType().ID(),
+		network.Type(),
+		network.MachineCIDR(),
+		network.ServiceCIDR(),
+		network.PodCIDR(),
+		network.HostPrefix(),
+		strings.Join(cluster.Version().AvailableUpgrades(), ", "),
+	)
+}
+
 // findHyperShiftMgmtSvcClusters returns the name of a HyperShift cluster's management and service clusters.
 // It essentially ignores error as these endpoint is behind specific permissions by returning empty strings when any
 // errors are encountered, which results in them not being printed in the output.
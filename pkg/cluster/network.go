@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+)
+
+// CheckNetworkOverlap checks that the machine, service and pod networks of a cluster don't
+// overlap. Networks that are empty are ignored, as the server will use its defaults for them. This
+// is checked before sending the request because the error returned by the server is hard to
+// understand.
+func CheckNetworkOverlap(machineCIDR, serviceCIDR, podCIDR string) error {
+	names := []string{"machine", "service", "pod"}
+	values := []string{machineCIDR, serviceCIDR, podCIDR}
+	networks := make([]*net.IPNet, len(values))
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("Invalid %s CIDR '%s': %v", names[i], value, err)
+		}
+		networks[i] = network
+	}
+	for i := range networks {
+		for j := i + 1; j < len(networks); j++ {
+			if networks[i] == nil || networks[j] == nil {
+				continue
+			}
+			if networks[i].Contains(networks[j].IP) || networks[j].Contains(networks[i].IP) {
+				return fmt.Errorf("The %s CIDR '%s' overlaps with the %s CIDR '%s', they must "+
+					"be disjoint", names[i], values[i], names[j], values[j])
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"testing"
+)
+
+func TestCheckNetworkOverlap(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine string
+		service string
+		pod     string
+		err     string
+	}{
+		{
+			name:    "Defaults",
+			machine: "10.0.0.0/16",
+			service: "172.30.0.0/16",
+			pod:     "10.128.0.0/14",
+		},
+		{
+			name: "Empty",
+		},
+		{
+			name:    "Only machine",
+			machine: "10.0.0.0/16",
+		},
+		{
+			name:    "Pod contains machine",
+			machine: "10.128.0.0/16",
+			service: "172.30.0.0/16",
+			pod:     "10.128.0.0/14",
+			err: "The machine CIDR '10.128.0.0/16' overlaps with the pod CIDR '10.128.0.0/14', " +
+				"they must be disjoint",
+		},
+		{
+			name:    "Service inside machine",
+			machine: "172.16.0.0/12",
+			service: "172.30.0.0/16",
+			err: "The machine CIDR '172.16.0.0/12' overlaps with the service CIDR " +
+				"'172.30.0.0/16', they must be disjoint",
+		},
+		{
+			name:    "Service equals pod",
+			service: "10.128.0.0/14",
+			pod:     "10.128.0.0/14",
+			err: "The service CIDR '10.128.0.0/14' overlaps with the pod CIDR '10.128.0.0/14', " +
+				"they must be disjoint",
+		},
+		{
+			name:    "Invalid",
+			machine: "10.0.0.0",
+			err:     "Invalid machine CIDR '10.0.0.0': invalid CIDR address: 10.0.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		err := CheckNetworkOverlap(test.machine, test.service, test.pod)
+		if test.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("%s: expected error '%s', got '%v'", test.name, test.err, err)
+		}
+	}
+}
@@ -44,30 +44,51 @@ func EnsureOpenshiftVPrefix(v string) string {
 // sorted in approximate SemVer order (handling of text parts is somewhat arbitrary).
 func GetEnabledVersions(client *cmv1.Client, channelGroup string) (
 	versions []string, defaultVersion string, err error) {
+	list, err := GetVersions(client, channelGroup, true)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, version := range list {
+		short := DropOpenshiftVPrefix(version.ID())
+		versions = append(versions, short)
+		if version.Default() {
+			defaultVersion = short
+		}
+	}
+	return versions, defaultVersion, nil
+}
+
+// GetVersions returns the versions of the given channel group, or of all the channel groups if it
+// is empty, sorted in the same order as GetEnabledVersions. If enabledOnly is false the versions
+// that can't be used to provision clusters are also returned.
+func GetVersions(client *cmv1.Client, channelGroup string, enabledOnly bool) (
+	versions []*cmv1.Version, err error) {
 	collection := client.Versions()
 	page := 1
 	size := 100
-	filter := "enabled = 'true'"
+	var terms []string
+	if enabledOnly {
+		terms = append(terms, "enabled = 'true'")
+	}
 	if channelGroup != "" {
-		filter = fmt.Sprintf("%s AND channel_group = '%s'", filter, channelGroup)
+		terms = append(terms, fmt.Sprintf("channel_group = '%s'", channelGroup))
 	}
+	filter := strings.Join(terms, " AND ")
 	for {
-		response, err := collection.List().
-			Search(filter).
+		request := collection.List().
 			Page(page).
-			Size(size).
-			Send()
+			Size(size)
+		if filter != "" {
+			request = request.Search(filter)
+		}
+		response, err := request.Send()
 		if err != nil {
-			return nil, "", err
+			return nil, err
 		}
 
 		for _, version := range response.Items().Slice() {
-			short := DropOpenshiftVPrefix(version.ID())
-			if version.Enabled() {
-				versions = append(versions, short)
-			}
-			if version.Default() {
-				defaultVersion = short
+			if version.Enabled() || !enabledOnly {
+				versions = append(versions, version)
 			}
 		}
 
@@ -77,8 +98,9 @@ func GetEnabledVersions(client *cmv1.Client, channelGroup string) (
 		page++
 	}
 
-	sort.Slice(versions, func(i, j int) (less bool) {
-		s1, s2 := versions[i], versions[j]
+	sort.SliceStable(versions, func(i, j int) (less bool) {
+		s1 := DropOpenshiftVPrefix(versions[i].ID())
+		s2 := DropOpenshiftVPrefix(versions[j].ID())
 		v1, err1 := goVersion.NewVersion(s1)
 		v2, err2 := goVersion.NewVersion(s2)
 		if err1 != nil || err2 != nil {
@@ -87,5 +109,5 @@ func GetEnabledVersions(client *cmv1.Client, channelGroup string) (
 		}
 		return v1.LessThan(v2)
 	})
-	return versions, defaultVersion, nil
+	return versions, nil
 }
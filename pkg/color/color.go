@@ -0,0 +1,94 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--no-color' command line option and to add
+// colors to the output that is intended for humans.
+
+package color
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences used to change the color of the text:
+const (
+	red    = "\033[31m"
+	green  = "\033[32m"
+	yellow = "\033[33m"
+	reset  = "\033[0m"
+)
+
+// AddFlag adds the no color flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&disabled,
+		"no-color",
+		false,
+		"Disable colors in the output. Colors are also disabled when the output isn't a "+
+			"terminal or when the 'NO_COLOR' environment variable is set.",
+	)
+}
+
+// Enabled returns a boolean flag that indicates if colors should be used when writing to the given
+// writer. That is only the case when the writer is a terminal, the '--no-color' flag hasn't been
+// used and the 'NO_COLOR' environment variable isn't set.
+func Enabled(writer io.Writer) bool {
+	if disabled || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// State returns the given state surrounded by the escape sequences of the color that corresponds
+// to it: green for states that are ready, red for errors and yellow for states that are changing.
+// States that don't have a color are returned unchanged.
+func State(state string) string {
+	code, ok := stateColors[state]
+	if !ok {
+		return state
+	}
+	return code + state + reset
+}
+
+// stateColors contains the colors used for the states of clusters, add-ons and upgrade policies.
+var stateColors = map[string]string{
+	"ready":         green,
+	"error":         red,
+	"failed":        red,
+	"installing":    yellow,
+	"pending":       yellow,
+	"validating":    yellow,
+	"waiting":       yellow,
+	"uninstalling":  yellow,
+	"deleting":      yellow,
+	"hibernating":   yellow,
+	"powering_down": yellow,
+	"resuming":      yellow,
+	"started":       yellow,
+	"scheduled":     yellow,
+}
+
+// disabled is a boolean flag that indicates that colors have been disabled with the '--no-color'
+// flag.
+var disabled bool
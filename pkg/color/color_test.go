@@ -0,0 +1,32 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	tests := []struct {
+		state    string
+		expected string
+	}{
+		{state: "ready", expected: "\033[32mready\033[0m"},
+		{state: "error", expected: "\033[31merror\033[0m"},
+		{state: "installing", expected: "\033[33minstalling\033[0m"},
+		{state: "unknown", expected: "unknown"},
+		{state: "", expected: ""},
+	}
+	for _, test := range tests {
+		actual := State(test.state)
+		if actual != test.expected {
+			t.Errorf("expected state '%s' to be %q, got %q", test.state, test.expected, actual)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	// Buffers aren't terminals, so colors should never be used for them:
+	if Enabled(&bytes.Buffer{}) {
+		t.Errorf("expected colors to be disabled for a buffer")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/golang/glog"
@@ -32,6 +33,11 @@ import (
 
 	"github.com/openshift-online/ocm-cli/pkg/debug"
 	"github.com/openshift-online/ocm-cli/pkg/info"
+	"github.com/openshift-online/ocm-cli/pkg/insecure"
+	"github.com/openshift-online/ocm-cli/pkg/keepalive"
+	"github.com/openshift-online/ocm-cli/pkg/profile"
+	"github.com/openshift-online/ocm-cli/pkg/proxy"
+	"github.com/openshift-online/ocm-cli/pkg/utils"
 )
 
 // Config is the type used to store the configuration of the client.
@@ -51,55 +57,177 @@ type Config struct {
 	URL          string   `json:"url,omitempty" doc:"URL of the API gateway. The value can be the complete URL or an alias. The valid aliases are 'production', 'staging' and 'integration'."`
 	User         string   `json:"user,omitempty" doc:"User name."`
 	Pager        string   `json:"pager,omitempty" doc:"Pager command, for example 'less'. If empty no pager will be used."`
+	Cluster      string   `json:"cluster,omitempty" doc:"Name or ID or external_id of the cluster used by commands when the '--cluster' option isn't given."`
 }
 
-// Load loads the configuration from the configuration file. If the configuration file doesn't exist
-// it will return an empty configuration object.
+// content is the complete content of the configuration file. The settings of the default profile
+// are stored at the top level, so that files created before profiles existed are still valid, and
+// the settings of the rest of the profiles are stored in the 'profiles' field, for example:
+//
+//	{
+//	  "url": "https://api.openshift.com",
+//	  "refresh_token": "...",
+//	  "profiles": {
+//	    "staging": {
+//	      "url": "https://api.stage.openshift.com",
+//	      "refresh_token": "..."
+//	    }
+//	  }
+//	}
+type content struct {
+	Config
+	Profiles map[string]*Config `json:"profiles,omitempty"`
+}
+
+// profile returns the settings of the given profile, or nil if it doesn't exist.
+func (c *content) profile(name string) *Config {
+	if name == profile.Default {
+		return &c.Config
+	}
+	return c.Profiles[name]
+}
+
+// Load loads the configuration of the active profile from the configuration file. If the
+// configuration file or the profile don't exist it will return an empty configuration object.
 func Load() (cfg *Config, err error) {
+	data, err := load()
+	if err != nil {
+		return
+	}
+	cfg = data.profile(profile.Name())
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	return
+}
+
+// Profiles returns the names of the profiles of the configuration file, starting with the default
+// one, followed by the rest in alphabetical order.
+func Profiles() (names []string, err error) {
+	data, err := load()
+	if err != nil {
+		return
+	}
+	names = make([]string, 0, 1+len(data.Profiles))
+	names = append(names, profile.Default)
+	others := make([]string, 0, len(data.Profiles))
+	for name := range data.Profiles {
+		if name != profile.Default {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+	return
+}
+
+// load loads the complete content of the configuration file. If the configuration file doesn't
+// exist it will return an empty content object.
+func load() (result *content, err error) {
 	file, err := Location()
 	if err != nil {
 		return
 	}
-	_, err = os.Stat(file)
+	description := describeLocation(file)
+	info, err := os.Stat(file)
 	if os.IsNotExist(err) {
-		cfg = &Config{}
+		result = &content{}
 		err = nil
 		return
 	}
 	if err != nil {
-		err = fmt.Errorf("can't check if config file '%s' exists: %v", file, err)
+		err = fmt.Errorf("can't check if %s exists: %v", description, err)
+		return
+	}
+	if info.IsDir() {
+		err = fmt.Errorf("%s is a directory, it should be a file", description)
 		return
 	}
 	// #nosec G304
 	data, err := os.ReadFile(file)
+	if os.IsPermission(err) {
+		err = fmt.Errorf("can't read %s, check the permissions of the file: %v", description, err)
+		return
+	}
 	if err != nil {
-		err = fmt.Errorf("can't read config file '%s': %v", file, err)
+		err = fmt.Errorf("can't read %s: %v", description, err)
 		return
 	}
-	cfg = &Config{}
+	result = &content{}
 	if len(data) == 0 {
 		return
 	}
-	err = json.Unmarshal(data, cfg)
+	err = json.Unmarshal(data, result)
 	if err != nil {
-		err = fmt.Errorf("can't parse config file '%s': %v", file, err)
+		err = fmt.Errorf("can't parse %s: %v", description, utils.DescribeJSONError(data, err))
 		return
 	}
 	return
 }
 
-// Save saves the given configuration to the configuration file.
+// MissingReason returns a text explaining that the configuration file or the active profile don't
+// exist, or an empty string if they exist.
+func MissingReason() (reason string, err error) {
+	file, err := Location()
+	if err != nil {
+		return
+	}
+	_, err = os.Stat(file)
+	if os.IsNotExist(err) {
+		reason = fmt.Sprintf("%s doesn't exist", describeLocation(file))
+		err = nil
+		return
+	}
+	name := profile.Name()
+	if name == profile.Default {
+		return
+	}
+	data, err := load()
+	if err != nil {
+		return
+	}
+	if data.profile(name) == nil {
+		reason = fmt.Sprintf("profile '%s' doesn't exist in %s", name, describeLocation(file))
+	}
+	return
+}
+
+// describeLocation returns the text used in error messages to refer to the given configuration
+// file, mentioning the 'OCM_CONFIG' environment variable when the location was taken from it, as
+// a wrong value of that variable is a common cause of errors.
+func describeLocation(file string) string {
+	if os.Getenv("OCM_CONFIG") != "" {
+		return fmt.Sprintf("config file '%s' given in the OCM_CONFIG environment variable", file)
+	}
+	return fmt.Sprintf("config file '%s'", file)
+}
+
+// Save saves the given configuration as the active profile of the configuration file, preserving
+// the rest of the profiles.
 func Save(cfg *Config) error {
 	file, err := Location()
 	if err != nil {
 		return err
 	}
+	result, err := load()
+	if err != nil {
+		return err
+	}
+	name := profile.Name()
+	if name == profile.Default {
+		result.Config = *cfg
+	} else {
+		if result.Profiles == nil {
+			result.Profiles = map[string]*Config{}
+		}
+		result.Profiles[name] = cfg
+	}
 	dir := filepath.Dir(file)
 	err = os.MkdirAll(dir, os.FileMode(0755))
 	if err != nil {
 		return fmt.Errorf("can't create directory %s: %v", dir, err)
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return fmt.Errorf("can't marshal config: %v", err)
 	}
@@ -208,7 +336,8 @@ func (c *Config) Armed() (armed bool, reason string, err error) {
 	return
 }
 
-// Disarm removes from the configuration all the settings that are needed for authentication.
+// Disarm removes from the configuration all the settings that are needed for authentication, and
+// the default cluster, as it belongs to the server that the user was logged in to.
 func (c *Config) Disarm() {
 	c.AccessToken = ""
 	c.ClientID = ""
@@ -220,6 +349,7 @@ func (c *Config) Disarm() {
 	c.TokenURL = ""
 	c.URL = ""
 	c.User = ""
+	c.Cluster = ""
 }
 
 // Connection creates a connection using this configuration.
@@ -268,7 +398,28 @@ func (c *Config) Connection() (connection *sdk.Connection, err error) {
 	if len(tokens) > 0 {
 		builder.Tokens(tokens...)
 	}
-	builder.Insecure(c.Insecure)
+	// The command line flag disables the verification only for this command, so it isn't saved
+	// to the configuration:
+	if insecure.Enabled() {
+		insecure.Warn(os.Stderr)
+	}
+	builder.Insecure(c.Insecure || insecure.Enabled())
+	if debug.HTTPEnabled() {
+		builder.TransportWrapper(debug.WrapTransport)
+	}
+	err = proxy.Check()
+	if err != nil {
+		return
+	}
+	builder.TransportWrapper(proxy.WrapTransport)
+	// The SDK applies the last wrapper first, so this one receives the transport that it creates
+	// and can change its connection pool settings:
+	err = keepalive.Check()
+	if err != nil {
+		return
+	}
+	builder.DisableKeepAlives(keepalive.Disabled())
+	builder.TransportWrapper(keepalive.WrapTransport)
 
 	// Create the connection:
 	connection, err = builder.Build()
@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2" // nolint
@@ -171,3 +173,49 @@ var _ = Describe("Armed", func() {
 		Expect(reason).To(Equal("credentials aren't set"))
 	})
 })
+
+var _ = Describe("Load", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "ocm-config-*")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("OCM_CONFIG")
+		err := os.RemoveAll(dir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Returns an empty configuration if the file doesn't exist", func() {
+		file := filepath.Join(dir, "missing.json")
+		os.Setenv("OCM_CONFIG", file)
+		cfg, err := Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg).To(Equal(&Config{}))
+		reason, err := MissingReason()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(ContainSubstring(file))
+		Expect(reason).To(ContainSubstring("OCM_CONFIG"))
+	})
+
+	It("Reports the location of invalid JSON", func() {
+		file := filepath.Join(dir, "invalid.json")
+		err := os.WriteFile(file, []byte("{\n  \"url\": \"https://api.example.com\",\n  x\n}"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		os.Setenv("OCM_CONFIG", file)
+		_, err = Load()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(file))
+		Expect(err.Error()).To(ContainSubstring("line 3, column 3"))
+	})
+
+	It("Reports that the location is a directory", func() {
+		os.Setenv("OCM_CONFIG", dir)
+		_, err := Load()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is a directory"))
+	})
+})
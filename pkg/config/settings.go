@@ -0,0 +1,194 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used by the 'ocm config get' and 'ocm config set' commands to
+// read and write individual settings of the configuration.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Names returns the names of the settings of the configuration, in the order that they are
+// declared.
+func Names() []string {
+	configType := reflect.TypeOf(Config{})
+	names := make([]string, configType.NumField())
+	for i := range names {
+		names[i] = strings.Split(configType.Field(i).Tag.Get("json"), ",")[0]
+	}
+	return names
+}
+
+// normalizeName returns the name of the setting that corresponds to the given name, which may
+// also be written with dashes instead of underscores, like the command line flags.
+func normalizeName(name string) (string, error) {
+	normalized := strings.ReplaceAll(name, "-", "_")
+	for _, known := range Names() {
+		if normalized == known {
+			return known, nil
+		}
+	}
+	return "", fmt.Errorf("Unknown setting '%s', valid settings are: %s",
+		name, strings.Join(Names(), ", "))
+}
+
+// Get returns the value of the given setting, formatted as it should be printed.
+func (c *Config) Get(name string) (value string, err error) {
+	name, err = normalizeName(name)
+	if err != nil {
+		return
+	}
+	switch name {
+	case "access_token":
+		value = c.AccessToken
+	case "client_id":
+		value = c.ClientID
+	case "client_secret":
+		value = c.ClientSecret
+	case "insecure":
+		value = strconv.FormatBool(c.Insecure)
+	case "password":
+		value = c.Password
+	case "refresh_token":
+		value = c.RefreshToken
+	case "scopes":
+		value = fmt.Sprintf("%s", c.Scopes)
+	case "token_url":
+		value = c.TokenURL
+	case "url":
+		value = c.URL
+	case "user":
+		value = c.User
+	case "pager":
+		value = c.Pager
+	case "cluster":
+		value = c.Cluster
+	}
+	return
+}
+
+// Set checks that the given value is valid for the given setting and then changes it. An empty
+// value removes the setting.
+func (c *Config) Set(name, value string) (err error) {
+	name, err = normalizeName(name)
+	if err != nil {
+		return
+	}
+	switch name {
+	case "access_token":
+		err = checkToken(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for access_token: %v", err)
+		}
+		c.AccessToken = value
+	case "client_id":
+		c.ClientID = value
+	case "client_secret":
+		c.ClientSecret = value
+	case "insecure":
+		c.Insecure, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for insecure: expected 'true' or 'false'", value)
+		}
+	case "password":
+		c.Password = value
+	case "refresh_token":
+		err = checkToken(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value for refresh_token: %v", err)
+		}
+		c.RefreshToken = value
+	case "scopes":
+		return fmt.Errorf("Setting scopes is unsupported")
+	case "token_url":
+		err = checkURL(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for token_url: %v", value, err)
+		}
+		c.TokenURL = value
+	case "url":
+		err = checkURL(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for url: %v", value, err)
+		}
+		c.URL = value
+	case "user":
+		c.User = value
+	case "pager":
+		c.Pager = value
+	case "cluster":
+		c.Cluster = value
+	}
+	return
+}
+
+// Redacted is the value that replaces the credentials in the configuration returned by the
+// Redacted method.
+const Redacted = "REDACTED"
+
+// Redacted returns a copy of the configuration where the tokens, the password and the client
+// secret have been replaced by a fixed text, so that it can be displayed without leaking them.
+// Settings that are empty are preserved, so that it is still possible to see which ones are set.
+func (c *Config) Redacted() *Config {
+	result := *c
+	redact := func(value *string) {
+		if *value != "" {
+			*value = Redacted
+		}
+	}
+	redact(&result.AccessToken)
+	redact(&result.RefreshToken)
+	redact(&result.ClientSecret)
+	redact(&result.Password)
+	return &result
+}
+
+// checkURL checks that the given text is an absolute 'http' or 'https' URL.
+func checkURL(text string) error {
+	if text == "" {
+		return nil
+	}
+	parsed, err := url.Parse(text)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("expected an URL with an 'http' or 'https' scheme")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("expected an URL with a host name")
+	}
+	return nil
+}
+
+// checkToken checks that the given text is a JSON web token. Encrypted tokens can't be parsed, so
+// they are accepted as they are.
+func checkToken(text string) error {
+	if text == "" || IsEncryptedToken(text) {
+		return nil
+	}
+	_, err := ParseToken(text)
+	if err != nil {
+		return fmt.Errorf("expected a JSON web token: %v", err)
+	}
+	return nil
+}
@@ -83,6 +83,16 @@ func tokenUsable(token string, margin time.Duration) (usable bool, err error) {
 	return
 }
 
+// TokenExpiration determines if the given token expires, and the time that remains till it
+// expires.
+func TokenExpiration(textToken string) (expires bool, left time.Duration, err error) {
+	parsed, err := ParseToken(textToken)
+	if err != nil {
+		return
+	}
+	return tokenExpiration(parsed)
+}
+
 // tokenExpiration determines if the given token expires, and the time that remains till it expires.
 func tokenExpiration(token *jwt.Token) (expires bool, left time.Duration, err error) {
 	claims, ok := token.Claims.(jwt.MapClaims)
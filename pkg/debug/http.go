@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--debug-http' command line option.
+
+package debug
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// AddHTTPFlag adds the flag that enables the logging of HTTP requests and responses to the given
+// set of command line flags.
+func AddHTTPFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&httpEnabled,
+		"debug-http",
+		false,
+		"Write to the standard error output the method, URL, headers, status and duration of "+
+			"each HTTP request. Sensitive headers like 'Authorization' are redacted.",
+	)
+}
+
+// HTTPEnabled returns a boolean flag that indicates if the logging of HTTP requests is enabled.
+func HTTPEnabled() bool {
+	return httpEnabled
+}
+
+// WrapTransport returns a transport that logs the requests sent with the given transport to the
+// standard error output, if enabled with the command line flag. Otherwise it returns the given
+// transport unchanged. Its signature is compatible with the transport wrappers of the SDK.
+func WrapTransport(transport http.RoundTripper) http.RoundTripper {
+	if !httpEnabled {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &httpLogger{
+		transport: transport,
+		writer:    os.Stderr,
+	}
+}
+
+// httpLogger is a transport that writes a summary of each request and response.
+type httpLogger struct {
+	transport http.RoundTripper
+	writer    io.Writer
+}
+
+func (l *httpLogger) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	fmt.Fprintf(l.writer, "> %s %s\n", request.Method, request.URL)
+	writeHeaders(l.writer, ">", request.Header)
+	start := time.Now()
+	response, err = l.transport.RoundTrip(request)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(l.writer, "< error after %s: %v\n", elapsed, err)
+		return
+	}
+	fmt.Fprintf(l.writer, "< %s %s after %s\n", response.Proto, response.Status, elapsed)
+	writeHeaders(l.writer, "<", response.Header)
+	return
+}
+
+// writeHeaders writes the given headers sorted by name, redacting the values of the sensitive
+// ones.
+func writeHeaders(writer io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = "REDACTED"
+			}
+			fmt.Fprintf(writer, "%s %s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+// sensitiveHeaders are the headers whose values are never written, as they contain credentials.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+}
+
+// httpEnabled is a boolean flag that indicates that the logging of HTTP requests is enabled.
+var httpEnabled bool
@@ -23,13 +23,13 @@ import (
 	"runtime"
 
 	"github.com/nwidger/jsoncolor"
-	"github.com/openshift-online/ocm-cli/pkg/output"
+	"github.com/openshift-online/ocm-cli/pkg/color"
 	"gitlab.com/c0b/go-ordered-json"
 )
 
 // Pretty dumps the given data to the given stream so that it looks pretty. If the data is a valid
-// JSON document then it will be indented before printing it. If the stream is a terminal then the
-// output will also use colors.
+// JSON document then it will be indented before printing it. If the stream is a terminal, and
+// colors haven't been disabled with the '--no-color' flag, then the output will also use colors.
 func Pretty(stream io.Writer, body []byte) error {
 	if len(body) == 0 {
 		return nil
@@ -39,7 +39,7 @@ func Pretty(stream io.Writer, body []byte) error {
 	if err != nil {
 		return dumpBytes(stream, body)
 	}
-	if output.IsTerminal(stream) && !isWindows() {
+	if color.Enabled(stream) && !isWindows() {
 		return dumpColor(stream, data)
 	}
 	return dumpMonochrome(stream, data)
@@ -69,7 +69,7 @@ func Single(stream io.Writer, body []byte) error {
 	if err != nil {
 		return dumpBytes(stream, body)
 	}
-	if output.IsTerminal(stream) && !isWindows() {
+	if color.Enabled(stream) && !isWindows() {
 		return dumpColorSingleLine(stream, data)
 	}
 	return dumpMonochromeSingleLine(stream, data)
@@ -90,6 +90,56 @@ func dumpMonochromeSingleLine(stream io.Writer, data interface{}) error {
 	return encoder.Encode(data)
 }
 
+// Document dumps the given data using Single if compact is true, or else Pretty.
+func Document(stream io.Writer, body []byte, compact bool) error {
+	if compact {
+		return Single(stream, body)
+	}
+	return Pretty(stream, body)
+}
+
+// Values dumps the given values using SingleValues if compact is true, or else PrettyValues.
+func Values(stream io.Writer, values []interface{}, compact bool) error {
+	return dumpValues(stream, values, compact)
+}
+
+// PrettyValues dumps the given values, for example the results of a JSONPath expression, one after
+// the other. Strings are written without quotes, so that they are easy to use in scripts, and the
+// rest of the values are written as in Pretty.
+func PrettyValues(stream io.Writer, values []interface{}) error {
+	return dumpValues(stream, values, false)
+}
+
+// SingleValues functions exactly the same as PrettyValues except it writes each value in a single
+// line.
+func SingleValues(stream io.Writer, values []interface{}) error {
+	return dumpValues(stream, values, true)
+}
+
+func dumpValues(stream io.Writer, values []interface{}, single bool) error {
+	colored := color.Enabled(stream) && !isWindows()
+	for _, value := range values {
+		var err error
+		text, ok := value.(string)
+		switch {
+		case ok:
+			err = dumpBytes(stream, []byte(text))
+		case single && colored:
+			err = dumpColorSingleLine(stream, value)
+		case single:
+			err = dumpMonochromeSingleLine(stream, value)
+		case colored:
+			err = dumpColor(stream, value)
+		default:
+			err = dumpMonochrome(stream, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func dumpBytes(stream io.Writer, data []byte) error {
 	_, err := stream.Write(data)
 	if err != nil {
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--insecure-skip-tls-verify' command line
+// option.
+
+package insecure
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the flag that disables the verification of TLS certificates to the given set of
+// command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&enabled,
+		"insecure-skip-tls-verify",
+		false,
+		"Don't verify the TLS certificates of the servers, only for this command. The "+
+			"configuration file isn't changed. Never use this in production.",
+	)
+}
+
+// Enabled returns a boolean flag that indicates if the verification of TLS certificates has been
+// disabled with the command line flag.
+func Enabled() bool {
+	return enabled
+}
+
+// Warn writes to the given writer the warning that explains that the verification of TLS
+// certificates is disabled. It is written only once, even if called multiple times.
+func Warn(writer io.Writer) {
+	warnOnce.Do(func() {
+		fmt.Fprintf(writer, "WARNING: TLS certificate verification is disabled with the "+
+			"--insecure-skip-tls-verify flag, the connection to the server isn't secure. Don't "+
+			"use this flag in production.\n")
+	})
+}
+
+// enabled is a boolean flag that indicates that the verification of TLS certificates is disabled.
+var enabled bool
+
+var warnOnce sync.Once
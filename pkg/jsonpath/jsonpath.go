@@ -0,0 +1,379 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonpath contains a simple implementation of JSONPath expressions, used to extract fields
+// from the responses of the server. It supports the following subset of the syntax:
+//
+//	$                  The root of the document. It is optional.
+//	.name or ['name']  The field with the given name.
+//	..name             All the fields with the given name, at any depth.
+//	.* or [*]          All the items of an array or all the values of an object.
+//	[n]                The item with the given index. Negative values count from the end.
+//	[?(@.name=='x')]   The items of an array for which the given condition is true. The operator
+//	                   can be '==' or '!=', or it can be omitted to check that the field exists.
+//
+// For compatibility with the syntax used by kubectl the expression can also be surrounded by
+// curly braces, for example '{.items[*].id}'.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is a parsed JSONPath expression.
+type Path struct {
+	text  string
+	steps []step
+}
+
+// step is one of the steps of the path. It receives one of the values selected by the previous
+// step and adds to the results the values that it selects.
+type step func(value interface{}, results []interface{}) []interface{}
+
+// Parse parses the given JSONPath expression.
+func Parse(text string) (path *Path, err error) {
+	expression := strings.TrimSpace(text)
+	if strings.HasPrefix(expression, "{") && strings.HasSuffix(expression, "}") {
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+	expression = strings.TrimPrefix(expression, "$")
+	if expression != "" && expression[0] != '.' && expression[0] != '[' {
+		expression = "." + expression
+	}
+	steps, err := parseSteps(expression)
+	if err != nil {
+		err = fmt.Errorf("invalid JSONPath expression '%s': %v", text, err)
+		return
+	}
+	path = &Path{
+		text:  text,
+		steps: steps,
+	}
+	return
+}
+
+// String returns the text of the expression.
+func (p *Path) String() string {
+	return p.text
+}
+
+// Apply applies the path to the given JSON document and returns the selected values. Numbers are
+// returned as json.Number, so that they aren't changed.
+func (p *Path) Apply(data []byte) (results []interface{}, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	err = decoder.Decode(&document)
+	if err != nil {
+		return
+	}
+	results = p.evaluate(document)
+	return
+}
+
+func (p *Path) evaluate(document interface{}) []interface{} {
+	values := []interface{}{document}
+	for _, step := range p.steps {
+		var results []interface{}
+		for _, value := range values {
+			results = step(value, results)
+		}
+		values = results
+	}
+	return values
+}
+
+func parseSteps(expression string) (steps []step, err error) {
+	for i := 0; i < len(expression); {
+		switch {
+		case strings.HasPrefix(expression[i:], ".."):
+			i += 2
+			var name string
+			name, i = parseName(expression, i)
+			if name == "" {
+				err = fmt.Errorf("expected a field name at position %d", i)
+				return
+			}
+			steps = append(steps, descendantStep(name))
+		case expression[i] == '.':
+			i++
+			if i < len(expression) && expression[i] == '*' {
+				i++
+				steps = append(steps, wildcardStep)
+				continue
+			}
+			var name string
+			name, i = parseName(expression, i)
+			if name == "" {
+				err = fmt.Errorf("expected a field name at position %d", i)
+				return
+			}
+			steps = append(steps, fieldStep(name))
+		case expression[i] == '[':
+			end := findClosingBracket(expression, i)
+			if end == -1 {
+				err = fmt.Errorf("missing closing bracket for the bracket at position %d", i)
+				return
+			}
+			var parsed step
+			parsed, err = parseBracket(strings.TrimSpace(expression[i+1 : end]))
+			if err != nil {
+				return
+			}
+			steps = append(steps, parsed)
+			i = end + 1
+		default:
+			err = fmt.Errorf("unexpected character '%c' at position %d", expression[i], i)
+			return
+		}
+	}
+	return
+}
+
+// parseName returns the field name that starts at the given position, and the position of the first
+// character after it.
+func parseName(expression string, start int) (name string, end int) {
+	end = start
+	for end < len(expression) && !strings.ContainsRune(".[]()=! ", rune(expression[end])) {
+		end++
+	}
+	name = expression[start:end]
+	return
+}
+
+// findClosingBracket returns the position of the bracket that closes the one at the given position,
+// ignoring the brackets that are inside quoted strings, or -1 if there is none.
+func findClosingBracket(expression string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(content string) (result step, err error) {
+	switch {
+	case content == "*":
+		result = wildcardStep
+	case isQuoted(content):
+		result = fieldStep(content[1 : len(content)-1])
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		result, err = parseFilter(strings.TrimSpace(content[2 : len(content)-1]))
+	default:
+		var index int
+		index, err = strconv.Atoi(content)
+		if err != nil {
+			err = fmt.Errorf("expected an index, a quoted field name, '*' or a filter, but "+
+				"found '%s'", content)
+			return
+		}
+		result = indexStep(index)
+	}
+	return
+}
+
+func parseFilter(condition string) (result step, err error) {
+	operator := ""
+	left := condition
+	right := ""
+	for _, candidate := range []string{"==", "!="} {
+		index := strings.Index(condition, candidate)
+		if index != -1 {
+			operator = candidate
+			left = strings.TrimSpace(condition[:index])
+			right = strings.TrimSpace(condition[index+len(candidate):])
+			break
+		}
+	}
+	if !strings.HasPrefix(left, "@") {
+		err = fmt.Errorf("the condition '%s' must start with '@'", condition)
+		return
+	}
+	expression := left[1:]
+	if expression == "" || expression[0] != '.' && expression[0] != '[' {
+		err = fmt.Errorf("expected a field after '@' in condition '%s'", condition)
+		return
+	}
+	steps, err := parseSteps(expression)
+	if err != nil {
+		return
+	}
+	field := &Path{text: left, steps: steps}
+	if operator == "" {
+		result = filterStep(func(item interface{}) bool {
+			return len(field.evaluate(item)) > 0
+		})
+		return
+	}
+	literal, err := parseLiteral(right)
+	if err != nil {
+		return
+	}
+	result = filterStep(func(item interface{}) bool {
+		found := false
+		for _, value := range field.evaluate(item) {
+			if equal(value, literal) {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "==")
+	})
+	return
+}
+
+func parseLiteral(text string) (value interface{}, err error) {
+	if isQuoted(text) {
+		value = text[1 : len(text)-1]
+		return
+	}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	err = decoder.Decode(&value)
+	if err != nil {
+		err = fmt.Errorf("expected a quoted string, a number, 'true', 'false' or 'null', but "+
+			"found '%s'", text)
+	}
+	return
+}
+
+func isQuoted(text string) bool {
+	return len(text) >= 2 && (text[0] == '\'' || text[0] == '"') && text[len(text)-1] == text[0]
+}
+
+// equal checks if the given values are equal, comparing numbers by value.
+func equal(a, b interface{}) bool {
+	aNumber, aOk := a.(json.Number)
+	bNumber, bOk := b.(json.Number)
+	if aOk && bOk {
+		aFloat, aErr := aNumber.Float64()
+		bFloat, bErr := bNumber.Float64()
+		if aErr == nil && bErr == nil {
+			return aFloat == bFloat
+		}
+		return aNumber == bNumber
+	}
+	return a == b
+}
+
+func fieldStep(name string) step {
+	return func(value interface{}, results []interface{}) []interface{} {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return results
+		}
+		field, ok := object[name]
+		if !ok {
+			return results
+		}
+		return append(results, field)
+	}
+}
+
+func indexStep(index int) step {
+	return func(value interface{}, results []interface{}) []interface{} {
+		array, ok := value.([]interface{})
+		if !ok {
+			return results
+		}
+		i := index
+		if i < 0 {
+			i += len(array)
+		}
+		if i < 0 || i >= len(array) {
+			return results
+		}
+		return append(results, array[i])
+	}
+}
+
+func wildcardStep(value interface{}, results []interface{}) []interface{} {
+	switch typed := value.(type) {
+	case []interface{}:
+		results = append(results, typed...)
+	case map[string]interface{}:
+		for _, key := range sortedKeys(typed) {
+			results = append(results, typed[key])
+		}
+	}
+	return results
+}
+
+func descendantStep(name string) step {
+	var visit step
+	visit = func(value interface{}, results []interface{}) []interface{} {
+		switch typed := value.(type) {
+		case []interface{}:
+			for _, item := range typed {
+				results = visit(item, results)
+			}
+		case map[string]interface{}:
+			if field, ok := typed[name]; ok {
+				results = append(results, field)
+			}
+			for _, key := range sortedKeys(typed) {
+				results = visit(typed[key], results)
+			}
+		}
+		return results
+	}
+	return visit
+}
+
+func filterStep(condition func(interface{}) bool) step {
+	return func(value interface{}, results []interface{}) []interface{} {
+		array, ok := value.([]interface{})
+		if !ok {
+			return results
+		}
+		for _, item := range array {
+			if condition(item) {
+				results = append(results, item)
+			}
+		}
+		return results
+	}
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const document = `{
+	"kind": "ClusterList",
+	"total": 2,
+	"items": [
+		{
+			"id": "123",
+			"name": "mycluster",
+			"state": "ready",
+			"nodes": {"compute": 3}
+		},
+		{
+			"id": "456",
+			"name": "yourcluster",
+			"state": "installing",
+			"nodes": {"compute": 5}
+		}
+	]
+}`
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{
+			name:       "Field",
+			expression: "$.kind",
+			expected:   `["ClusterList"]`,
+		},
+		{
+			name:       "Field without root",
+			expression: "total",
+			expected:   `[2]`,
+		},
+		{
+			name:       "Kubectl syntax",
+			expression: "{.items[*].id}",
+			expected:   `["123","456"]`,
+		},
+		{
+			name:       "Negative index",
+			expression: "$.items[-1].name",
+			expected:   `["yourcluster"]`,
+		},
+		{
+			name:       "Quoted field",
+			expression: "$['items'][0]['nodes'].compute",
+			expected:   `[3]`,
+		},
+		{
+			name:       "Descendants",
+			expression: "$..compute",
+			expected:   `[3,5]`,
+		},
+		{
+			name:       "Filter by string",
+			expression: "$.items[?(@.state=='ready')].name",
+			expected:   `["mycluster"]`,
+		},
+		{
+			name:       "Filter by number",
+			expression: "$.items[?(@.nodes.compute != 3)].id",
+			expected:   `["456"]`,
+		},
+		{
+			name:       "Missing field",
+			expression: "$.items[5].name",
+			expected:   `null`,
+		},
+	}
+
+	for _, test := range tests {
+		path, err := Parse(test.expression)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		results, err := path.Apply([]byte(document))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		actual, err := json.Marshal(results)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if string(actual) != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{
+		"$.items[0",
+		"$.items[abc]",
+		"$.items[?(name=='x')]",
+		"$.items[?(@.name==x)]",
+		"$.",
+	} {
+		_, err := Parse(expression)
+		if err == nil {
+			t.Errorf("expected an error for expression '%s'", expression)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--disable-keep-alives',
+// '--max-idle-conns-per-host' and '--idle-conn-timeout' command line options.
+
+package keepalive
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Default values of the flags. Commands like 'ocm create idp' send several requests to the same
+// server, so a few idle connections are kept to avoid a new TLS handshake for each of them. The
+// timeout is the same that the Go standard library uses for its default transport.
+const (
+	DefaultMaxIdleConnsPerHost = 10
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// AddFlags adds the flags that tune the reuse of HTTP connections to the given set of command
+// line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&disabled,
+		"disable-keep-alives",
+		false,
+		"Open a new connection for each HTTP request instead of reusing them.",
+	)
+	flags.IntVar(
+		&maxIdleConnsPerHost,
+		"max-idle-conns-per-host",
+		DefaultMaxIdleConnsPerHost,
+		"Maximum number of idle connections kept open to each server, for reuse by the next "+
+			"requests of the same command.",
+	)
+	flags.DurationVar(
+		&idleConnTimeout,
+		"idle-conn-timeout",
+		DefaultIdleConnTimeout,
+		"Time that an idle connection is kept open before closing it. Zero means no limit.",
+	)
+}
+
+// Disabled returns a boolean flag that indicates if the reuse of HTTP connections has been
+// disabled with the command line flag.
+func Disabled() bool {
+	return disabled
+}
+
+// Check checks that the values given in the command line flags are valid.
+func Check() error {
+	if maxIdleConnsPerHost < 0 {
+		return fmt.Errorf("Invalid value %d for --max-idle-conns-per-host: it can't be negative",
+			maxIdleConnsPerHost)
+	}
+	if idleConnTimeout < 0 {
+		return fmt.Errorf("Invalid value %s for --idle-conn-timeout: it can't be negative",
+			idleConnTimeout)
+	}
+	return nil
+}
+
+// WrapTransport returns a copy of the given transport configured with the values of the command
+// line flags. Transports that aren't of the type used by the standard library are returned
+// unchanged. Its signature is compatible with the transport wrappers of the SDK.
+func WrapTransport(transport http.RoundTripper) http.RoundTripper {
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		return transport
+	}
+	result := base.Clone()
+	result.DisableKeepAlives = result.DisableKeepAlives || disabled
+	result.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	result.IdleConnTimeout = idleConnTimeout
+	return result
+}
+
+// Values of the command line flags:
+var (
+	disabled            bool
+	maxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	idleConnTimeout     = DefaultIdleConnTimeout
+)
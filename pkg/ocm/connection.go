@@ -41,7 +41,8 @@ func (b *ConnectionBuilder) Config(value *config.Config) *ConnectionBuilder {
 
 // Build uses the information stored in the builder to create a new OCM connection.
 func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
-	if b.cfg == nil {
+	loaded := b.cfg == nil
+	if loaded {
 		// Load the configuration file:
 		b.cfg, err = config.Load()
 		if err != nil {
@@ -59,6 +60,13 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 		return
 	}
 	if !armed {
+		// A configuration file that doesn't exist explains better why there are no credentials:
+		if loaded {
+			missing, _ := config.MissingReason()
+			if missing != "" {
+				reason = missing
+			}
+		}
 		err = fmt.Errorf("Not logged in, %s, run the 'login' command", reason)
 		return
 	}
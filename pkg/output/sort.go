@@ -0,0 +1,179 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the code that sorts the rows of a table by one of its columns.
+
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Sorter knows how to sort the objects of a table by the values of one of its columns.
+type Sorter struct {
+	column     *Column
+	descending bool
+}
+
+// Sorter creates a sorter for the given spec, which is the name of one of the columns of the table,
+// optionally prefixed with a dash to sort in descending order, for example `-region.id`.
+func (t *Table) Sorter(spec string) (result *Sorter, err error) {
+	name := strings.TrimSpace(spec)
+	descending := strings.HasPrefix(name, "-")
+	if descending {
+		name = strings.TrimSpace(name[1:])
+	}
+	for _, column := range t.columns {
+		if column.name == name {
+			result = &Sorter{
+				column:     column,
+				descending: descending,
+			}
+			return
+		}
+	}
+	names := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		names[i] = column.name
+	}
+	err = fmt.Errorf(
+		"Invalid sort field '%s', valid fields are %s",
+		name, strings.Join(names, ", "),
+	)
+	return
+}
+
+// Sort sorts the given slice of objects by the value of the column. The sort is stable, so objects
+// with the same value preserve the order that they had, and objects that don't have a value are
+// always moved to the end.
+func (s *Sorter) Sort(objects interface{}) {
+	slice := reflect.ValueOf(objects)
+	values := make([]interface{}, slice.Len())
+	for i := range values {
+		values[i] = s.column.Value(slice.Index(i).Interface())
+	}
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		x := values[indexes[i]]
+		y := values[indexes[j]]
+		switch {
+		case x == nil:
+			return false
+		case y == nil:
+			return true
+		case s.descending:
+			return compareValues(y, x) < 0
+		default:
+			return compareValues(x, y) < 0
+		}
+	})
+
+	// Copy the objects to the sorted positions:
+	sorted := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len())
+	for i, index := range indexes {
+		sorted.Index(i).Set(slice.Index(index))
+	}
+	reflect.Copy(slice, sorted)
+}
+
+// compareValues compares two values of a column, returning a negative number if the first is
+// smaller, zero if they are equal and a positive number if the first is greater. Numbers and
+// booleans are compared by value, and everything else as text.
+func compareValues(x, y interface{}) int {
+	xv := reflect.ValueOf(x)
+	yv := reflect.ValueOf(y)
+	if xv.Kind() == yv.Kind() {
+		switch xv.Kind() {
+		case reflect.Bool:
+			switch {
+			case xv.Bool() == yv.Bool():
+				return 0
+			case yv.Bool():
+				return -1
+			default:
+				return 1
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return compareNumbers(float64(xv.Int()), float64(yv.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return compareNumbers(float64(xv.Uint()), float64(yv.Uint()))
+		case reflect.Float32, reflect.Float64:
+			return compareNumbers(xv.Float(), yv.Float())
+		}
+	}
+	return compareText(fmt.Sprintf("%v", x), fmt.Sprintf("%v", y))
+}
+
+func compareNumbers(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareText compares two texts so that the numbers that they contain are compared by value
+// instead of character by character. For example `4.9` is smaller than `4.12` and `node-2` is
+// smaller than `node-10`.
+func compareText(x, y string) int {
+	for x != "" && y != "" {
+		xChunk, xNumber := nextChunk(x)
+		yChunk, yNumber := nextChunk(y)
+		x = x[len(xChunk):]
+		y = y[len(yChunk):]
+		if xNumber && yNumber {
+			// Leading zeros don't change the value of the number:
+			xDigits := strings.TrimLeft(xChunk, "0")
+			yDigits := strings.TrimLeft(yChunk, "0")
+			if len(xDigits) != len(yDigits) {
+				return len(xDigits) - len(yDigits)
+			}
+			if result := strings.Compare(xDigits, yDigits); result != 0 {
+				return result
+			}
+			continue
+		}
+		if result := strings.Compare(xChunk, yChunk); result != 0 {
+			return result
+		}
+	}
+	return len(x) - len(y)
+}
+
+// nextChunk returns the leading run of digits or non digits of the given text, and a flag
+// indicating if it is a run of digits.
+func nextChunk(text string) (chunk string, number bool) {
+	number = isDigit(text[0])
+	end := 1
+	for end < len(text) && isDigit(text[end]) == number {
+		end++
+	}
+	chunk = text[:end]
+	return
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
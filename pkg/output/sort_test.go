@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"context"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo/v2" // nolint
+	. "github.com/onsi/gomega"    // nolint
+)
+
+var _ = Describe("Sorter", func() {
+	var table *Table
+	var clusters []*cmv1.Cluster
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		printer, err := NewPrinter().
+			Writer(&bytes.Buffer{}).
+			Build(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		table, err = printer.NewTable().
+			Name("clusters").
+			Columns("id", "openshift_version", "managed").
+			Build(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		clusters = make([]*cmv1.Cluster, 4)
+		clusters[0], err = cmv1.NewCluster().ID("c").OpenshiftVersion("4.12.1").Managed(true).Build()
+		Expect(err).ToNot(HaveOccurred())
+		clusters[1], err = cmv1.NewCluster().ID("a").OpenshiftVersion("4.9.3").Managed(false).Build()
+		Expect(err).ToNot(HaveOccurred())
+		clusters[2], err = cmv1.NewCluster().ID("d").Build()
+		Expect(err).ToNot(HaveOccurred())
+		clusters[3], err = cmv1.NewCluster().ID("b").OpenshiftVersion("4.12.1").Managed(true).Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	ids := func() []string {
+		result := make([]string, len(clusters))
+		for i, cluster := range clusters {
+			result[i] = cluster.ID()
+		}
+		return result
+	}
+
+	It("Sorts in ascending order", func() {
+		sorter, err := table.Sorter("id")
+		Expect(err).ToNot(HaveOccurred())
+		sorter.Sort(clusters)
+		Expect(ids()).To(Equal([]string{"a", "b", "c", "d"}))
+	})
+
+	It("Sorts in descending order", func() {
+		sorter, err := table.Sorter("-id")
+		Expect(err).ToNot(HaveOccurred())
+		sorter.Sort(clusters)
+		Expect(ids()).To(Equal([]string{"d", "c", "b", "a"}))
+	})
+
+	It("Compares the numbers inside the values by value", func() {
+		sorter, err := table.Sorter("openshift_version")
+		Expect(err).ToNot(HaveOccurred())
+		sorter.Sort(clusters)
+		Expect(ids()).To(Equal([]string{"a", "c", "b", "d"}))
+	})
+
+	It("Moves the objects without value to the end", func() {
+		sorter, err := table.Sorter("-managed")
+		Expect(err).ToNot(HaveOccurred())
+		sorter.Sort(clusters)
+		Expect(ids()).To(Equal([]string{"c", "b", "a", "d"}))
+	})
+
+	It("Rejects a column that isn't in the table", func() {
+		_, err := table.Sorter("name")
+		Expect(err).To(MatchError(
+			"Invalid sort field 'name', valid fields are id, openshift_version, managed",
+		))
+	})
+})
@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"reflect"
@@ -29,6 +30,8 @@ import (
 
 	"github.com/openshift-online/ocm-sdk-go/data"
 	"gopkg.in/yaml.v3"
+
+	"github.com/openshift-online/ocm-cli/pkg/color"
 )
 
 //go:embed tables
@@ -43,6 +46,7 @@ type TableBuilder struct {
 	values        map[string]reflect.Value
 	learning      bool
 	learningLimit int
+	csv           bool
 }
 
 // Table contains the data and logic needed to write tabular output.
@@ -57,6 +61,13 @@ type Table struct {
 	learning      bool
 	learningLimit int
 	learningRows  [][]string
+
+	// When writing comma separated values this is the writer that quotes them, and the headers
+	// are the names of the columns.
+	csvWriter *csv.Writer
+
+	// Flag indicating if the values of the state columns should be colored.
+	colors bool
 }
 
 // tableYAML is used to load a table description from a YAML document.
@@ -160,6 +171,15 @@ func (b *TableBuilder) LearningLimit(value int) *TableBuilder {
 	return b
 }
 
+// CSV enables or disables writing the table as comma separated values instead of aligned columns.
+// The header row then contains the names of the columns, for example `cloud_provider.id`, as they
+// don't change when the headers are made more readable, and values that contain commas or quotes
+// are quoted. Learning is disabled because the widths of the columns aren't used.
+func (b *TableBuilder) CSV(value bool) *TableBuilder {
+	b.csv = value
+	return b
+}
+
 // Build uses the configuration stored in the builder to create a table.
 func (b *TableBuilder) Build(ctx context.Context) (result *Table, err error) {
 	// Check parameters:
@@ -194,6 +214,16 @@ func (b *TableBuilder) Build(ctx context.Context) (result *Table, err error) {
 		return
 	}
 
+	// Prepare the writer for comma separated values if needed:
+	if b.csv {
+		table.learning = false
+		table.csvWriter = csv.NewWriter(b.printer)
+	}
+
+	// Colors are only used for aligned columns written to a terminal, comma separated values
+	// are intended for other tools:
+	table.colors = !b.csv && color.Enabled(b.printer.writer)
+
 	// Create the digger if needed:
 	table.digger = b.digger
 	if b.digger == nil {
@@ -351,9 +381,12 @@ func (t *Table) WriteRow(rowValues []interface{}) error {
 	rowData := make([]string, columnCount)
 	for i, columnValue := range rowValues {
 		var columnData string
-		if columnValue != nil {
+		switch {
+		case columnValue != nil:
 			columnData = fmt.Sprintf("%v", columnValue)
-		} else {
+		case t.csvWriter != nil:
+			columnData = ""
+		default:
 			columnData = "NONE"
 		}
 		rowData[i] = columnData
@@ -446,6 +479,10 @@ func (t *Table) learnColumnWidths() {
 }
 
 func (t *Table) writeRow(rowData []string) error {
+	if t.csvWriter != nil {
+		return t.csvWriter.Write(rowData)
+	}
+
 	// Prepare a buffer to write the columns (sum of the widths of the columns plus two
 	// characters to separate columns, and the new line):
 	rowWidth := 2 * len(rowData)
@@ -462,16 +499,15 @@ func (t *Table) writeRow(rowData []string) error {
 		}
 		actualWidth := len(columnValue)
 		desiredWidth := t.columns[i].Width()
-		switch {
-		case actualWidth > desiredWidth:
-			rowBuffer.WriteString(columnValue[0:desiredWidth])
-		case actualWidth < desiredWidth:
-			rowBuffer.WriteString(columnValue)
-			for j := 0; j < desiredWidth-actualWidth; j++ {
-				rowBuffer.WriteString(" ")
-			}
-		default:
-			rowBuffer.WriteString(columnValue)
+		if actualWidth > desiredWidth {
+			columnValue = columnValue[0:desiredWidth]
+		}
+		if t.colors && t.columns[i].isState() {
+			columnValue = color.State(columnValue)
+		}
+		rowBuffer.WriteString(columnValue)
+		for j := actualWidth; j < desiredWidth; j++ {
+			rowBuffer.WriteString(" ")
 		}
 	}
 	rowBuffer.WriteString("\n")
@@ -485,7 +521,11 @@ func (t *Table) writeRow(rowData []string) error {
 func (t *Table) WriteHeaders() error {
 	headers := make([]interface{}, len(t.columns))
 	for i, column := range t.columns {
-		headers[i] = column.Header()
+		if t.csvWriter != nil {
+			headers[i] = column.name
+		} else {
+			headers[i] = column.Header()
+		}
 	}
 	return t.WriteRow(headers)
 }
@@ -508,6 +548,10 @@ func (t *Table) Flush() error {
 			return err
 		}
 	}
+	if t.csvWriter != nil {
+		t.csvWriter.Flush()
+		return t.csvWriter.Error()
+	}
 	return nil
 }
 
@@ -562,3 +606,9 @@ func (c *Column) Value(object interface{}) interface{} {
 func (c *Column) Adjust(value int) {
 	c.width = value
 }
+
+// isState checks if the column contains a state, like `state` or `status.state`, so that its values
+// can be colored.
+func (c *Column) isState() bool {
+	return c.name == "state" || strings.HasSuffix(c.name, ".state")
+}
@@ -303,4 +303,32 @@ var _ = Describe("Table", func() {
 		Expect(lines[1]).To(Equal(`123   my_github`))
 		Expect(lines[2]).To(Equal(`456   your_gith`))
 	})
+
+	It("Writes comma separated values", func() {
+		// Create the table:
+		table, err := printer.NewTable().
+			Name("clusters").
+			Columns("id", "name", "state").
+			CSV(true).
+			Build(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create the object that will be written to the table:
+		cluster, err := cmv1.NewCluster().
+			ID("123").
+			Name("my,cluster").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Write the object to the table:
+		err = table.WriteHeaders()
+		Expect(err).ToNot(HaveOccurred())
+		err = table.WriteObject(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		err = table.Close()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check the generated text:
+		Expect(buffer.String()).To(Equal("id,name,state\n123,\"my,cluster\",\n"))
+	})
 })
@@ -0,0 +1,118 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Prefixes of the values of the '--output' flag that select a Go template instead of one of the
+// predefined formats:
+const (
+	TemplatePrefix     = "go-template="
+	TemplateFilePrefix = "go-template-file="
+)
+
+// TemplateFormats are the descriptions of the template formats, to be added to the list of
+// options in the help and error messages of the '--output' flag.
+var TemplateFormats = []string{TemplatePrefix + "...", TemplateFilePrefix + "..."}
+
+// Template is a Go template used to format objects. The template is executed with the JSON
+// representation of the object, so fields are referenced with the same names that are used in
+// the API, for example '{{ .id }}' or '{{ .cloud_provider.id }}'.
+type Template struct {
+	template *template.Template
+}
+
+// IsTemplate checks if the given value of the '--output' flag selects a Go template.
+func IsTemplate(format string) bool {
+	return strings.HasPrefix(format, TemplatePrefix) || strings.HasPrefix(format, TemplateFilePrefix)
+}
+
+// NewTemplate parses the template given in the value of the '--output' flag, either directly
+// after the 'go-template=' prefix or in the file whose name follows the 'go-template-file='
+// prefix.
+func NewTemplate(format string) (result *Template, err error) {
+	var text string
+	switch {
+	case strings.HasPrefix(format, TemplatePrefix):
+		text = strings.TrimPrefix(format, TemplatePrefix)
+	case strings.HasPrefix(format, TemplateFilePrefix):
+		file := strings.TrimPrefix(format, TemplateFilePrefix)
+		if file == "" {
+			err = fmt.Errorf("Expected the name of a template file after '%s'", TemplateFilePrefix)
+			return
+		}
+		// #nosec G304
+		var data []byte
+		data, err = os.ReadFile(file)
+		if err != nil {
+			err = fmt.Errorf("Failed to read template file '%s': %v", file, err)
+			return
+		}
+		text = string(data)
+	default:
+		err = fmt.Errorf("Output format '%s' isn't a template", format)
+		return
+	}
+	if text == "" {
+		err = fmt.Errorf("Template is empty")
+		return
+	}
+	parsed, err := template.New("output").
+		Funcs(templateFuncs).
+		Parse(text)
+	if err != nil {
+		err = fmt.Errorf("Failed to parse template: %v", err)
+		return
+	}
+	result = &Template{
+		template: parsed,
+	}
+	return
+}
+
+// Execute writes the given object using the template. The object should be the JSON
+// representation of the object, as generated by the SDK marshal functions.
+func (t *Template) Execute(writer io.Writer, object []byte) error {
+	var data interface{}
+	err := json.Unmarshal(object, &data)
+	if err != nil {
+		return fmt.Errorf("Failed to parse object: %v", err)
+	}
+	err = t.template.Execute(writer, data)
+	if err != nil {
+		return fmt.Errorf("Failed to execute template: %v", err)
+	}
+	return nil
+}
+
+// templateFuncs are the functions that can be used in templates, in addition to the ones
+// provided by the text/template package.
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2" // nolint
+	. "github.com/onsi/gomega"    // nolint
+)
+
+var _ = Describe("Template", func() {
+	It("Uses the JSON names of the fields", func() {
+		tmpl, err := NewTemplate(`go-template={{ .id }}/{{ .cloud_provider.id }}`)
+		Expect(err).ToNot(HaveOccurred())
+		buffer := &bytes.Buffer{}
+		err = tmpl.Execute(buffer, []byte(`{"id": "123", "cloud_provider": {"id": "aws"}}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.String()).To(Equal("123/aws"))
+	})
+
+	It("Provides the json, lower and upper functions", func() {
+		tmpl, err := NewTemplate(`go-template={{ json .labels }} {{ lower .name }} {{ upper .name }}`)
+		Expect(err).ToNot(HaveOccurred())
+		buffer := &bytes.Buffer{}
+		err = tmpl.Execute(buffer, []byte(`{"name": "MyCluster", "labels": {"a": "b"}}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.String()).To(Equal(`{"a":"b"} mycluster MYCLUSTER`))
+	})
+
+	It("Reads the template from a file", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "template.txt")
+		err := os.WriteFile(file, []byte("{{ .name }}\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		tmpl, err := NewTemplate("go-template-file=" + file)
+		Expect(err).ToNot(HaveOccurred())
+		buffer := &bytes.Buffer{}
+		err = tmpl.Execute(buffer, []byte(`{"name": "mycluster"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer.String()).To(Equal("mycluster\n"))
+	})
+
+	It("Fails if the template can't be parsed", func() {
+		_, err := NewTemplate(`go-template={{ .name `)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("Failed to parse template: "))
+	})
+
+	It("Fails if the template file doesn't exist", func() {
+		_, err := NewTemplate("go-template-file=/does/not/exist")
+		Expect(err).To(MatchError(ContainSubstring(
+			"Failed to read template file '/does/not/exist'",
+		)))
+	})
+
+	It("Detects the template formats", func() {
+		Expect(IsTemplate("go-template={{ .id }}")).To(BeTrue())
+		Expect(IsTemplate("go-template-file=my.tmpl")).To(BeTrue())
+		Expect(IsTemplate("csv")).To(BeFalse())
+	})
+})
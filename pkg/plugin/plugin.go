@@ -5,6 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift-online/ocm-cli/pkg/profile"
 )
 
 // Handler is capable of parsing command line arguments
@@ -89,10 +92,50 @@ func HandlePluginCommand(pluginHandler Handler, cmdArgs []string) (found bool, e
 		return false, nil
 	}
 
-	// invoke cmd binary relaying the current environment and args given
-	if err := pluginHandler.Execute(foundBinaryPath, cmdArgs[len(remainingArgs):], os.Environ()); err != nil {
+	// invoke cmd binary relaying the current environment, with the connection details added, and
+	// args given
+	if err := pluginHandler.Execute(foundBinaryPath, cmdArgs[len(remainingArgs):], Environment()); err != nil {
 		return true, err
 	}
 
 	return true, nil
 }
+
+// Environment returns the environment of the current process with the details of the API
+// connection added, so that plugins use the same configuration file and profile, and can find
+// the servers without parsing that file:
+//
+//   - OCM_CONFIG: location of the configuration file.
+//   - OCM_PROFILE: name of the profile.
+//   - OCM_URL, OCM_TOKEN_URL and OCM_CLIENT_ID: the URLs and the client of the profile, when the
+//     user is logged in.
+//
+// Tokens aren't added, plugins can get them running the 'ocm token' command.
+func Environment() []string {
+	environment := os.Environ()
+	location, err := config.Location()
+	if err == nil {
+		environment = setEnv(environment, "OCM_CONFIG", location)
+	}
+	environment = setEnv(environment, "OCM_PROFILE", profile.Name())
+	cfg, err := config.Load()
+	if err == nil && cfg.URL != "" {
+		environment = setEnv(environment, "OCM_URL", cfg.URL)
+		environment = setEnv(environment, "OCM_TOKEN_URL", cfg.TokenURL)
+		environment = setEnv(environment, "OCM_CLIENT_ID", cfg.ClientID)
+	}
+	return environment
+}
+
+// setEnv sets the value of the given variable in the given environment, replacing the existing
+// value if there is one.
+func setEnv(environment []string, name, value string) []string {
+	prefix := name + "="
+	for i, item := range environment {
+		if strings.HasPrefix(item, prefix) {
+			environment[i] = prefix + value
+			return environment
+		}
+	}
+	return append(environment, prefix+value)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--profile' command line option.
+
+package profile
+
+import (
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// Default is the name of the profile used when no other profile is selected. Its settings are
+// stored at the top level of the configuration file, like before profiles existed.
+const Default = "default"
+
+// AddFlag adds the flag that selects the profile of the configuration file to the given set of
+// command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&name,
+		"profile",
+		"",
+		"Name of the profile of the configuration file to use. If not given the value of the "+
+			"'OCM_PROFILE' environment variable is used, and if that is also empty then the "+
+			"'default' profile is used.",
+	)
+}
+
+// Name returns the name of the active profile, taken from the command line flag, from the
+// 'OCM_PROFILE' environment variable, or the default.
+func Name() string {
+	if name != "" {
+		return name
+	}
+	if value := os.Getenv("OCM_PROFILE"); value != "" {
+		return value
+	}
+	return Default
+}
+
+// name is the name of the profile given in the command line.
+var name string
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--proxy' and '--no-proxy' command line
+// options.
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// AddFlags adds the flags that select the HTTP proxy to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&proxy,
+		"proxy",
+		"",
+		"URL of the proxy used to connect to the API and authentication servers. Overrides "+
+			"the 'HTTPS_PROXY' and 'HTTP_PROXY' environment variables.",
+	)
+	flags.StringVar(
+		&noProxy,
+		"no-proxy",
+		"",
+		"Comma separated list of host names, domains and network CIDRs that are connected "+
+			"directly, without the proxy. Overrides the 'NO_PROXY' environment variable.",
+	)
+}
+
+// Check checks that the proxy URLs given in the command line flag or in the environment are
+// valid.
+func Check() error {
+	if proxy != "" {
+		err := checkURL(proxy)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for --proxy: %v", proxy, err)
+		}
+		return nil
+	}
+	for _, name := range envNames {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		err := checkURL(value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for the '%s' environment variable: %v",
+				value, name, err)
+		}
+	}
+	return nil
+}
+
+// WrapTransport returns a copy of the given transport that uses the proxy selected by the command
+// line flags or the environment, and that explains the errors caused by a proxy that isn't
+// reachable. Transports that aren't of the type used by the standard library are returned
+// unchanged. Its signature is compatible with the transport wrappers of the SDK.
+func WrapTransport(transport http.RoundTripper) http.RoundTripper {
+	base, ok := transport.(*http.Transport)
+	if !ok {
+		return transport
+	}
+	function := proxyConfig().ProxyFunc()
+	result := base.Clone()
+	result.Proxy = func(request *http.Request) (*url.URL, error) {
+		return function(request.URL)
+	}
+	return &proxyTransport{
+		wrapped:  result,
+		function: function,
+	}
+}
+
+// proxyConfig returns the proxy configuration from the environment, overridden with the values
+// of the command line flags.
+func proxyConfig() *httpproxy.Config {
+	config := httpproxy.FromEnvironment()
+	if proxy != "" {
+		config.HTTPProxy = proxy
+		config.HTTPSProxy = proxy
+	}
+	if noProxy != "" {
+		config.NoProxy = noProxy
+	}
+	return config
+}
+
+// checkURL checks that the given text is a proxy URL that the Go standard library accepts. Like
+// the standard library it also accepts addresses without a scheme, for example 'myproxy:3128'.
+func checkURL(text string) error {
+	parsed, err := url.Parse(text)
+	if err == nil && !supportedSchemes[parsed.Scheme] {
+		if strings.Contains(text, "://") {
+			return fmt.Errorf("unsupported scheme '%s', expected 'http', 'https' or 'socks5'",
+				parsed.Scheme)
+		}
+		parsed, err = url.Parse("http://" + text)
+	}
+	if err != nil {
+		return err
+	}
+	if parsed.Hostname() == "" {
+		return fmt.Errorf("expected an URL with a host name")
+	}
+	return nil
+}
+
+// proxyTransport is the round tripper that replaces the errors caused by a proxy that isn't
+// reachable with errors that mention the proxy, as otherwise it looks like the API server is
+// the one that isn't reachable.
+type proxyTransport struct {
+	wrapped  http.RoundTripper
+	function func(*url.URL) (*url.URL, error)
+}
+
+// RoundTrip is the implementation of the round tripper interface.
+func (t *proxyTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	response, err = t.wrapped.RoundTrip(request)
+	var opErr *net.OpError
+	if err != nil && errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		address, _ := t.function(request.URL)
+		if address != nil {
+			err = fmt.Errorf("can't connect to proxy '%s', check the --proxy flag and the "+
+				"'HTTPS_PROXY' and 'HTTP_PROXY' environment variables: %w", address.Redacted(), err)
+		}
+	}
+	return
+}
+
+// supportedSchemes are the URL schemes of proxies supported by the Go standard library.
+var supportedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"socks5": true,
+}
+
+// envNames are the names of the environment variables that contain proxy URLs.
+var envNames = []string{
+	"HTTPS_PROXY",
+	"https_proxy",
+	"HTTP_PROXY",
+	"http_proxy",
+}
+
+// Values of the command line flags:
+var (
+	proxy   string
+	noProxy string
+)
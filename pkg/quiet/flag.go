@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--quiet' command line option.
+
+package quiet
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the quiet flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&enabled,
+		"quiet",
+		false,
+		"Don't write informational messages, like progress and instructions, only the results "+
+			"of the command. Errors are still written to the standard error.",
+	)
+}
+
+// Enabled returns a boolean flag that indicates if the quiet mode is enabled.
+func Enabled() bool {
+	return enabled
+}
+
+// Printf writes an informational message to the standard error, unless the quiet mode is enabled.
+// The standard output is reserved for the results of the command, so that it can be piped to other
+// tools.
+func Printf(format string, a ...interface{}) {
+	if !enabled {
+		fmt.Fprintf(os.Stderr, format, a...)
+	}
+}
+
+// Println is like Printf, but formats the message like fmt.Println.
+func Println(a ...interface{}) {
+	if !enabled {
+		fmt.Fprintln(os.Stderr, a...)
+	}
+}
+
+// enabled is a boolean flag that indicates that the quiet mode is enabled.
+var enabled bool
@@ -18,6 +18,7 @@ package urls
 
 import (
 	"fmt"
+	"sort"
 )
 
 // Resources that return a list of multiple items
@@ -38,6 +39,23 @@ var listResourceURLs = map[string]string{
 	"versions":       "/api/clusters_mgmt/v1/versions",
 }
 
+// Paths that are commonly used but don't have an alias, offered by the shell completion together
+// with the paths of the aliases
+var otherPaths = []string{
+	"/api/accounts_mgmt/v1",
+	"/api/accounts_mgmt/v1/current_account",
+	"/api/accounts_mgmt/v1/quota_cost",
+	"/api/authorizations/v1/access_review",
+	"/api/authorizations/v1/self_access_review",
+	"/api/clusters_mgmt/v1",
+	"/api/clusters_mgmt/v1/cloud_providers",
+	"/api/clusters_mgmt/v1/flavours",
+	"/api/clusters_mgmt/v1/machine_types",
+	"/api/clusters_mgmt/v1/products",
+	"/api/clusters_mgmt/v1/provision_shards",
+	"/api/service_logs/v1/cluster_logs",
+}
+
 // Resources that apply to a specific item and require an appended argument
 var individualResourceURLs = map[string]string{
 	"account":               "/api/accounts_mgmt/v1/accounts/%s",
@@ -106,3 +124,24 @@ func expandResourceWithID(path string, argv []string) (string, error) {
 	}
 	return fmt.Sprintf(path, argv[1]), nil
 }
+
+// Paths returns the sorted list of well known API paths that don't require an identifier, to be
+// offered by the shell completion.
+func Paths() []string {
+	seen := map[string]bool{}
+	paths := make([]string, 0)
+	for _, path := range listResourceURLs {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range otherPaths {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
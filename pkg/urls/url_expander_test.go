@@ -17,6 +17,7 @@ limitations under the License.
 package urls
 
 import (
+	"sort"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -425,4 +426,15 @@ var _ = Describe("Resources", func() {
 			},
 		),
 	)
+	It("Returns the sorted well known paths without duplicates", func() {
+		paths := Paths()
+		Expect(paths).To(ContainElement("/api/clusters_mgmt/v1/clusters"))
+		Expect(paths).To(ContainElement("/api/accounts_mgmt/v1/current_account"))
+		Expect(sort.StringsAreSorted(paths)).To(BeTrue())
+		seen := map[string]bool{}
+		for _, path := range paths {
+			Expect(seen).ToNot(HaveKey(path))
+			seen[path] = true
+		}
+	})
 })
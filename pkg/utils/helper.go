@@ -1,11 +1,19 @@
 package utils
 
 import (
+	"bytes"
 	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // the following regex defines four different patterns:
@@ -90,3 +98,149 @@ func HasDuplicates(valSlice []string) (string, bool) {
 	}
 	return "", false
 }
+
+// SplitList splits a comma separated list of values, removing the white space around each value
+// and discarding the values that are empty.
+func SplitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValidateHostname checks that the given value is a hostname or IP address, like
+// `ghe.example.com`, optionally followed by a port, like `ghe.example.com:8443`.
+func ValidateHostname(value string) error {
+	host := value
+	if net.ParseIP(host) == nil && strings.Contains(host, ":") {
+		var port string
+		var err error
+		host, port, err = net.SplitHostPort(value)
+		if err != nil {
+			return fmt.Errorf("invalid hostname '%s': %v", value, err)
+		}
+		number, err := strconv.Atoi(port)
+		if err != nil || number < 1 || number > 65535 {
+			return fmt.Errorf("invalid port '%s' in hostname '%s': it must be a number between "+
+				"1 and 65535", port, value)
+		}
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	errs := validation.IsDNS1123Subdomain(strings.ToLower(host))
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid hostname '%s': %s", host, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// githubLoginRE is the pattern of the logins of GitHub users and organizations: letters and
+// digits, separated by single hyphens.
+var githubLoginRE = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+
+// githubLoginMaxLength is the maximum length of the logins of GitHub users and organizations.
+const githubLoginMaxLength = 39
+
+// ValidateGithubLogin checks that the given value is a valid login of a GitHub organization, like
+// `my-org`, so that mistakes are reported before the server rejects them.
+func ValidateGithubLogin(value string) error {
+	if !githubLoginRE.MatchString(value) {
+		return fmt.Errorf("invalid GitHub organization '%s': it must contain only letters, "+
+			"digits and single hyphens, and can't begin or end with a hyphen", value)
+	}
+	if len(value) > githubLoginMaxLength {
+		return fmt.Errorf("invalid GitHub organization '%s': it can't be longer than %d "+
+			"characters", value, githubLoginMaxLength)
+	}
+	return nil
+}
+
+// ErrGithubTeamFormat is the error returned by ValidateGithubTeam when the value doesn't have the
+// <org>/<team> format.
+var ErrGithubTeamFormat = errors.New("the format must be <org>/<team>")
+
+// ValidateGithubTeam checks that the given value is a GitHub team with the <org>/<team> format,
+// like `my-org/my-team`, where the organization is a valid GitHub login.
+func ValidateGithubTeam(value string) error {
+	chunks := strings.Split(value, "/")
+	if len(chunks) != 2 || chunks[0] == "" || chunks[1] == "" {
+		return ErrGithubTeamFormat
+	}
+	return ValidateGithubLogin(chunks[0])
+}
+
+// secretFields are the names of the fields of the API objects that contain secrets.
+var secretFields = map[string]bool{
+	"client_secret":     true,
+	"bind_password":     true,
+	"password":          true,
+	"hashed_password":   true,
+	"secret_access_key": true,
+	"private_key":       true,
+}
+
+// RedactedValue is the value that replaces secrets that must not be displayed.
+const RedactedValue = "<redacted>"
+
+// RedactSecrets replaces the values of the secret fields of the given JSON document, so that it
+// can be displayed safely.
+func RedactSecrets(body []byte) ([]byte, error) {
+	var data interface{}
+	err := json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+	var redact func(value interface{})
+	redact = func(value interface{}) {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			for key, item := range typed {
+				if secretFields[key] {
+					typed[key] = RedactedValue
+				} else {
+					redact(item)
+				}
+			}
+		case []interface{}:
+			for _, item := range typed {
+				redact(item)
+			}
+		}
+	}
+	redact(data)
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// DescribeJSONError adds to the given error returned by the JSON decoder the line and column of
+// the data where it happened, if known.
+func DescribeJSONError(data []byte, err error) error {
+	var offset int64
+	switch typed := err.(type) {
+	case *json.SyntaxError:
+		offset = typed.Offset
+	case *json.UnmarshalTypeError:
+		offset = typed.Offset
+	default:
+		return err
+	}
+	// The offset is the number of bytes read, including the one that caused the error:
+	if offset < 1 || offset > int64(len(data)) {
+		return err
+	}
+	prefix := data[:offset-1]
+	line := 1 + bytes.Count(prefix, []byte("\n"))
+	column := len(prefix) - bytes.LastIndexByte(prefix, '\n')
+	return fmt.Errorf("invalid JSON at line %d, column %d: %v", line, column, err)
+}
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "Empty",
+			value:    "",
+			expected: []string{},
+		},
+		{
+			name:     "Single value",
+			value:    "acme",
+			expected: []string{"acme"},
+		},
+		{
+			name:     "Spaces and trailing comma",
+			value:    "acme, globex ,",
+			expected: []string{"acme", "globex"},
+		},
+		{
+			name:     "Only separators",
+			value:    " , ,",
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		actual := SplitList(test.value)
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestValidateHostname(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{
+			name:  "Hostname",
+			value: "ghe.example.com",
+			valid: true,
+		},
+		{
+			name:  "Hostname with port",
+			value: "ghe.example.com:8443",
+			valid: true,
+		},
+		{
+			name:  "IPv4 address with port",
+			value: "192.168.0.1:443",
+			valid: true,
+		},
+		{
+			name:  "IPv6 address",
+			value: "fd00::1",
+			valid: true,
+		},
+		{
+			name:  "IPv6 address with port",
+			value: "[fd00::1]:8443",
+			valid: true,
+		},
+		{
+			name:  "Port out of range",
+			value: "ghe.example.com:70000",
+			valid: false,
+		},
+		{
+			name:  "Port isn't a number",
+			value: "ghe.example.com:https",
+			valid: false,
+		},
+		{
+			name:  "URL",
+			value: "https://ghe.example.com",
+			valid: false,
+		},
+		{
+			name:  "Invalid characters",
+			value: "ghe_example.com",
+			valid: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateHostname(test.value)
+		if test.valid && err != nil {
+			t.Errorf("%s: expected '%s' to be valid, got: %v", test.name, test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected '%s' to be invalid", test.name, test.value)
+		}
+	}
+}
+
+func TestValidateGithubLogin(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{
+			name:  "Letters and digits",
+			value: "acme2",
+			valid: true,
+		},
+		{
+			name:  "Single hyphens",
+			value: "my-cool-org",
+			valid: true,
+		},
+		{
+			name:  "Maximum length",
+			value: strings.Repeat("a", 39),
+			valid: true,
+		},
+		{
+			name:  "Too long",
+			value: strings.Repeat("a", 40),
+			valid: false,
+		},
+		{
+			name:  "Leading hyphen",
+			value: "-acme",
+			valid: false,
+		},
+		{
+			name:  "Trailing hyphen",
+			value: "acme-",
+			valid: false,
+		},
+		{
+			name:  "Consecutive hyphens",
+			value: "my--org",
+			valid: false,
+		},
+		{
+			name:  "Space",
+			value: "my org",
+			valid: false,
+		},
+		{
+			name:  "Underscore",
+			value: "my_org",
+			valid: false,
+		},
+		{
+			name:  "Empty",
+			value: "",
+			valid: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateGithubLogin(test.value)
+		if test.valid && err != nil {
+			t.Errorf("%s: expected '%s' to be valid, got: %v", test.name, test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected '%s' to be invalid", test.name, test.value)
+		}
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	body := []byte(`{"name":"github-1","github":{"client_id":"abc","client_secret":"xyz"},` +
+		`"htpasswd":{"users":{"items":[{"username":"alice","password":"secret"}]}}}`)
+	expected := `{"github":{"client_id":"abc","client_secret":"<redacted>"},` +
+		`"htpasswd":{"users":{"items":[{"password":"<redacted>","username":"alice"}]}},"name":"github-1"}`
+	actual, err := RedactSecrets(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(actual) != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestValidateGithubTeam(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected error
+		valid    bool
+	}{
+		{
+			name:  "Team",
+			value: "acme/developers",
+			valid: true,
+		},
+		{
+			name:     "Missing team",
+			value:    "acme",
+			expected: ErrGithubTeamFormat,
+		},
+		{
+			name:     "Empty organization",
+			value:    "/developers",
+			expected: ErrGithubTeamFormat,
+		},
+		{
+			name:     "Too many slashes",
+			value:    "acme/developers/extra",
+			expected: ErrGithubTeamFormat,
+		},
+		{
+			name:  "Invalid organization",
+			value: "-acme/developers",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateGithubTeam(test.value)
+		if test.valid && err != nil {
+			t.Errorf("%s: expected '%s' to be valid, got: %v", test.name, test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected '%s' to be invalid", test.name, test.value)
+		}
+		if test.expected != nil && !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected error '%v', got: %v", test.name, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Account roles", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	When("Listing the role bindings of the current account", func() {
+		BeforeEach(func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/accounts_mgmt/v1/current_account"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "Account",
+							"id": "123",
+							"username": "myuser"
+						}`,
+					),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/accounts_mgmt/v1/role_bindings"),
+					VerifyFormKV("search", "account_id = '123'"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "RoleBindingList",
+							"page": 1,
+							"size": 3,
+							"total": 3,
+							"items": [
+								{
+									"kind": "RoleBinding",
+									"id": "rb1",
+									"type": "Organization",
+									"role": {
+										"id": "OrganizationAdmin"
+									},
+									"organization": {
+										"id": "456"
+									}
+								},
+								{
+									"kind": "RoleBinding",
+									"id": "rb2",
+									"type": "Subscription",
+									"role": {
+										"id": "ClusterEditor"
+									},
+									"subscription": {
+										"id": "789"
+									}
+								},
+								{
+									"kind": "RoleBinding",
+									"id": "rb3",
+									"type": "Application",
+									"role": {
+										"id": "AuthenticatedUser"
+									}
+								}
+							]
+						}`,
+					),
+				),
+			)
+		})
+
+		It("Writes a table", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("account", "roles", "--mine").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(4))
+			Expect(lines[0]).To(MatchRegexp(`^ROLE\s+TYPE\s+RESOURCE$`))
+			Expect(lines[1]).To(MatchRegexp(`^OrganizationAdmin\s+Organization\s+456$`))
+			Expect(lines[2]).To(MatchRegexp(`^ClusterEditor\s+Subscription\s+789$`))
+			Expect(lines[3]).To(MatchRegexp(`^AuthenticatedUser\s+Application\s+-$`))
+		})
+
+		It("Writes JSON", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("account", "roles", "--mine", "--output", "json").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutString()).To(MatchJSON(`[
+				{
+					"kind": "RoleBinding",
+					"id": "rb1",
+					"type": "Organization",
+					"role": {
+						"kind": "Role",
+						"id": "OrganizationAdmin"
+					},
+					"organization": {
+						"kind": "Organization",
+						"id": "456"
+					}
+				},
+				{
+					"kind": "RoleBinding",
+					"id": "rb2",
+					"type": "Subscription",
+					"role": {
+						"kind": "Role",
+						"id": "ClusterEditor"
+					},
+					"subscription": {
+						"kind": "Subscription",
+						"id": "789"
+					}
+				},
+				{
+					"kind": "RoleBinding",
+					"id": "rb3",
+					"type": "Application",
+					"role": {
+						"kind": "Role",
+						"id": "AuthenticatedUser"
+					}
+				}
+			]`))
+		})
+	})
+
+	It("Rejects '--mine' together with a role name", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("account", "roles", "--mine", "OrganizationAdmin").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Option '--mine' can't be used together with a role name",
+		))
+	})
+
+	It("Rejects unknown output formats", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("account", "roles", "--mine", "--output", "yaml").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid output format 'yaml', the only option is 'json'",
+		))
+	})
+})
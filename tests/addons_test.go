@@ -0,0 +1,378 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Add-ons", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	// prepareCluster adds the handlers that return the cluster:
+	prepareCluster := func() {
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"openshift_version": "4.9.3",
+					"version": {
+						"kind": "Version",
+						"id": "openshift-v4.9.3",
+						"channel_group": "stable"
+					},
+					"product": {
+						"id": "osd"
+					}
+				}`,
+			),
+		)
+	}
+
+	// prepareAddOn adds the handler that returns the add-on and the parameters that it declares:
+	prepareAddOn := func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "AddOn",
+						"id": "my-addon",
+						"parameters": {
+							"items": [
+								{
+									"id": "size",
+									"value_type": "number",
+									"enabled": true,
+									"required": true
+								},
+								{
+									"id": "tier",
+									"value_type": "string",
+									"enabled": true,
+									"options": [
+										{"name": "Small", "value": "small"},
+										{"name": "Large", "value": "large"}
+									]
+								}
+							]
+						}
+					}`,
+				),
+			),
+		)
+	}
+
+	It("Installs the add-on with the parameters", func() {
+		prepareCluster()
+		prepareAddOn()
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/addons"),
+				VerifyJSON(`{
+					"kind": "AddOnInstallation",
+					"id": "my-addon",
+					"addon": {
+						"kind": "AddOn",
+						"id": "my-addon"
+					},
+					"parameters": {
+						"items": [
+							{
+								"kind": "AddOnInstallationParameter",
+								"id": "size",
+								"value": "3"
+							},
+							{
+								"kind": "AddOnInstallationParameter",
+								"id": "tier",
+								"value": "large"
+							}
+						]
+					}
+				}`),
+				RespondWithJSON(http.StatusCreated, `{
+					"kind": "AddOnInstallation",
+					"id": "my-addon"
+				}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--parameters", "tier=large",
+				"--parameters", "size=3",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal(
+			"Installing add-on 'my-addon' on cluster 'mycluster'\n",
+		))
+	})
+
+	It("Rejects unknown parameters before installing the add-on", func() {
+		prepareCluster()
+		prepareAddOn()
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--parameters", "size=3",
+				"--parameters", "color=red",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Unknown parameter 'color' for add-on 'my-addon', valid parameters are: size, tier",
+		))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("Rejects parameters that aren't in key=value format", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--parameters", "size",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Expected key=value format for parameter 'size'",
+		))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("Waits till the add-on is ready", func() {
+		prepareCluster()
+		prepareAddOn()
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusCreated, `{
+				"kind": "AddOnInstallation",
+				"id": "my-addon",
+				"state": "pending"
+			}`),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, `{
+					"kind": "AddOnInstallation",
+					"id": "my-addon",
+					"state": "installing"
+				}`),
+			),
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "AddOnInstallation",
+				"id": "my-addon",
+				"state": "ready"
+			}`),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--parameters", "size=3",
+				"--wait", "--interval", "10ms",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrLines()).To(Equal([]string{
+			"Installing add-on 'my-addon' on cluster 'mycluster'",
+			"Add-on 'my-addon' is installing",
+			"Add-on 'my-addon' is ready",
+		}))
+	})
+
+	It("Reports the reason when the add-on fails to install", func() {
+		prepareCluster()
+		prepareAddOn()
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusCreated, `{
+				"kind": "AddOnInstallation",
+				"id": "my-addon"
+			}`),
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "AddOnInstallation",
+				"id": "my-addon",
+				"state": "failed",
+				"state_description": "Operator subscription failed"
+			}`),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--parameters", "size=3",
+				"--wait", "--interval", "10ms",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Add-on 'my-addon' failed to install on cluster 'mycluster': " +
+				"Operator subscription failed",
+		))
+	})
+
+	It("Fails if the add-on isn't ready before the timeout", func() {
+		prepareCluster()
+		prepareAddOn()
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusCreated, `{
+				"kind": "AddOnInstallation",
+				"id": "my-addon"
+			}`),
+		)
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/clusters/123/addons/my-addon",
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "AddOnInstallation",
+				"id": "my-addon",
+				"state": "installing"
+			}`),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--parameters", "size=3",
+				"--wait", "--interval", "10ms", "--timeout", "200ms",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Add-on 'my-addon' isn't ready on cluster 'mycluster' after 200ms",
+		))
+	})
+
+	It("Rejects --timeout without --wait", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"install", "addon", "--cluster", "mycluster",
+				"--timeout", "10m",
+				"my-addon",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"can only be used together with '--wait'",
+		))
+	})
+
+	It("Uninstalls the add-on", func() {
+		prepareCluster()
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusNoContent, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("uninstall", "addon", "--cluster", "mycluster", "--yes", "my-addon").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal(
+			"Uninstalling add-on 'my-addon' from cluster 'mycluster'\n",
+		))
+	})
+})
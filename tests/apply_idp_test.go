@@ -0,0 +1,367 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Apply identity providers", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+	var tmp string
+	var manifest string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Login:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(MakeTokenString("Bearer", 15*time.Minute)),
+		)
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The cluster, with one identity provider that matches the manifest, one that is
+		// different and one that isn't in the manifest:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+					  {
+						"id": "111",
+						"kind": "Subscription",
+						"status": "Active",
+						"cluster_id": "123"
+					  }
+					]
+				  }`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"console": {
+					  "url": "https://console-openshift-console.apps.mycluster.example.com"
+					}
+				  }`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "IdentityProviderList",
+					"page": 1,
+					"size": 3,
+					"total": 3,
+					"items": [
+					  {
+						"kind": "IdentityProvider",
+						"id": "111",
+						"name": "github-1",
+						"type": "GithubIdentityProvider",
+						"mapping_method": "claim",
+						"github": {
+						  "client_id": "abc",
+						  "organizations": ["org-b", "org-a"]
+						}
+					  },
+					  {
+						"kind": "IdentityProvider",
+						"id": "222",
+						"name": "github-2",
+						"type": "GithubIdentityProvider",
+						"mapping_method": "claim",
+						"github": {
+						  "client_id": "abc",
+						  "organizations": ["old-org"]
+						}
+					  },
+					  {
+						"kind": "IdentityProvider",
+						"id": "333",
+						"name": "htpasswd-1",
+						"type": "HTPasswdIdentityProvider",
+						"mapping_method": "claim"
+					  }
+					]
+				  }`,
+			),
+		)
+
+		// Create the manifest:
+		tmp, err = os.MkdirTemp("", "ocm-test-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		manifest = filepath.Join(tmp, "idps.yaml")
+		err = os.WriteFile(manifest, []byte(`identity_providers:
+- name: github-1
+  github:
+    client_id: abc
+    client_secret: xyz
+    organizations:
+    - org-a
+    - org-b
+- name: github-2
+  github:
+    client_id: abc
+    client_secret: xyz
+    organizations:
+    - new-org
+- name: github-3
+  github:
+    client_id: abc
+    client_secret: xyz
+    teams:
+    - myorg/myteam
+`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+
+		// Delete the manifest:
+		err := os.RemoveAll(tmp)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Creates, updates and deletes the identity providers", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/222",
+				),
+				VerifyJSON(`{
+					"kind": "IdentityProvider",
+					"name": "github-2",
+					"type": "GithubIdentityProvider",
+					"mapping_method": "claim",
+					"github": {
+					  "client_id": "abc",
+					  "client_secret": "xyz",
+					  "organizations": ["new-org"]
+					}
+				}`),
+				RespondWithJSON(http.StatusOK, `{
+					"kind": "IdentityProvider",
+					"id": "222",
+					"name": "github-2"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers",
+				),
+				RespondWithJSON(http.StatusCreated, `{
+					"kind": "IdentityProvider",
+					"id": "444",
+					"name": "github-3"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/333",
+				),
+				RespondWithJSON(http.StatusNoContent, ""),
+			),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"apply", "idp",
+				"--cluster", "mycluster",
+				"--from-file", manifest,
+				"--prune",
+			).
+			Run(ctx)
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Identity provider 'github-1' is unchanged\n"))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Identity provider 'github-2' has been updated\n"))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Identity provider 'github-3' has been created\n"))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Identity provider 'htpasswd-1' has been deleted\n"))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"1 created, 1 updated, 1 unchanged, 1 deleted\n"))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(6))
+	})
+
+	It("Doesn't delete identity providers without --prune", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/222",
+				),
+				RespondWithJSON(http.StatusOK, `{}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers",
+				),
+				RespondWithJSON(http.StatusCreated, `{}`),
+			),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"apply", "idp",
+				"--cluster", "mycluster",
+				"--from-file", manifest,
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).ToNot(ContainSubstring("htpasswd-1"))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"1 created, 1 updated, 1 unchanged, 0 deleted\n"))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(5))
+	})
+
+	It("Doesn't change anything in dry run mode", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"apply", "idp",
+				"--cluster", "mycluster",
+				"--from-file", manifest,
+				"--prune",
+				"--dry-run",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutLines()).To(Equal([]string{
+			"Identity provider 'github-1' is unchanged",
+			"Identity provider 'github-2' would be updated",
+			"Identity provider 'github-3' would be created",
+			"Identity provider 'htpasswd-1' would be deleted",
+			"Dry run, no changes were made to cluster 'mycluster': 1 created, 1 updated, " +
+				"1 unchanged, 1 deleted",
+		}))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("Requires names for all the identity providers", func() {
+		err := os.WriteFile(manifest, []byte(`github:
+  client_id: abc
+  client_secret: xyz
+  organizations:
+  - myorg
+`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"apply", "idp",
+				"--cluster", "mycluster",
+				"--from-file", manifest,
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("doesn't have a name"))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("Requires an explicit flag to allow any GitHub user", func() {
+		err := os.WriteFile(manifest, []byte(`identity_providers:
+- name: github-3
+  github:
+    client_id: abc
+    client_secret: xyz
+`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"apply", "idp",
+				"--cluster", "mycluster",
+				"--from-file", manifest,
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Either 'organizations' or 'teams' is required, use --allow-any-github-user",
+		))
+	})
+
+	It("Allows any GitHub user with --allow-any-github-user", func() {
+		err := os.WriteFile(manifest, []byte(`identity_providers:
+- name: github-3
+  github:
+    client_id: abc
+    client_secret: xyz
+`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"apply", "idp",
+				"--cluster", "mycluster",
+				"--from-file", manifest,
+				"--allow-any-github-user",
+				"--dry-run",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(ContainSubstring(
+			"Identity provider 'github-3' would be created",
+		))
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster kubeconfig", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	// kubeconfig is the content of the credentials returned by the server:
+	kubeconfig := "apiVersion: v1\nkind: Config\nclusters:\n- name: mycluster\n"
+
+	// respondWithCluster returns a handler that responds with the cluster in the given state:
+	respondWithCluster := func(state string) http.HandlerFunc {
+		return RespondWithJSONTemplate(
+			http.StatusOK,
+			`{
+				"kind": "Cluster",
+				"id": "123",
+				"name": "mycluster",
+				"state": "{{ .state }}"
+			}`,
+			"state", state,
+		)
+	}
+
+	// respondWithCredentials returns a handler that responds with the credentials of the cluster:
+	respondWithCredentials := func() http.HandlerFunc {
+		return CombineHandlers(
+			VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/credentials"),
+			RespondWithJSONTemplate(
+				http.StatusOK,
+				`{
+					"kind": "ClusterCredentials",
+					"id": "123",
+					"kubeconfig": "{{ .kubeconfig }}"
+				}`,
+				"kubeconfig", "apiVersion: v1\\nkind: Config\\nclusters:\\n- name: mycluster\\n",
+			),
+		)
+	}
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The cluster is found using the subscription:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Writes the kubeconfig to the standard output", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("ready"),
+			respondWithCredentials(),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "kubeconfig", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(Equal(kubeconfig))
+	})
+
+	It("Writes the kubeconfig to the file given with --output-file", func() {
+		tmp, err := os.MkdirTemp("", "ocm-test-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmp)
+		file := filepath.Join(tmp, "kubeconfig")
+
+		apiServer.AppendHandlers(
+			respondWithCluster("ready"),
+			respondWithCredentials(),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "kubeconfig", "mycluster", "--output-file", file).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(BeEmpty())
+		data, err := os.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(kubeconfig))
+		info, err := os.Stat(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("Explains that the credentials aren't available while installing", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("installing"),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "kubeconfig", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"The credentials of cluster 'mycluster' aren't available yet, its state is " +
+				"'installing'",
+		))
+		Expect(result.ErrString()).To(ContainSubstring("ocm cluster wait-ready mycluster"))
+	})
+
+	It("Explains that the credentials aren't available when the server doesn't have them", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("ready"),
+			RespondWithJSON(
+				http.StatusNotFound,
+				`{
+					"kind": "Error",
+					"id": "404",
+					"href": "/api/clusters_mgmt/v1/errors/404",
+					"code": "CLUSTERS-MGMT-404",
+					"reason": "Credentials for cluster '123' not found"
+				}`,
+			),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "kubeconfig", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"The credentials of cluster 'mycluster' aren't available",
+		))
+	})
+})
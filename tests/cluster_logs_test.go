@@ -0,0 +1,224 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster logs", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	// respondWithCluster returns a handler that responds with the cluster in the given state:
+	respondWithCluster := func(state string) http.HandlerFunc {
+		return RespondWithJSONTemplate(
+			http.StatusOK,
+			`{
+				"kind": "Cluster",
+				"id": "123",
+				"name": "mycluster",
+				"state": "{{ .state }}"
+			}`,
+			"state", state,
+		)
+	}
+
+	// respondWithLog returns a handler that checks the offset of the request for the install log
+	// and responds with the given content:
+	respondWithLog := func(offset string, content string) http.HandlerFunc {
+		handlers := []http.HandlerFunc{
+			VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/logs/install"),
+		}
+		if offset != "" {
+			handlers = append(handlers, VerifyFormKV("offset", offset))
+		} else {
+			handlers = append(handlers, func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Has("offset")).To(BeFalse())
+			})
+		}
+		handlers = append(handlers, RespondWithJSONTemplate(
+			http.StatusOK,
+			`{
+				"kind": "Log",
+				"id": "install",
+				"content": "{{ .content }}"
+			}`,
+			"content", content,
+		))
+		return CombineHandlers(handlers...)
+	}
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The cluster is found using the subscription:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Writes the install logs", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("installing"),
+			respondWithLog("", `line 1\nline 2\n`),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "logs", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(Equal("line 1\nline 2\n"))
+	})
+
+	It("Follows the logs till the installation finishes", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("installing"),
+			respondWithCluster("installing"),
+			respondWithLog("", `line 1\nline 2\nline`),
+			respondWithCluster("installing"),
+			respondWithLog("2", `line`),
+			respondWithCluster("ready"),
+			respondWithLog("2", `line 3\nline 4\n`),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "logs", "mycluster", "--follow", "--interval", "10ms").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(Equal("line 1\nline 2\nline 3\nline 4\n"))
+	})
+
+	It("Writes the logs again when they are rotated", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("installing"),
+			respondWithCluster("installing"),
+			respondWithLog("", `line 1\nline 2\n`),
+			respondWithCluster("installing"),
+			respondWithLog("2", ``),
+			respondWithLog("1", `line 2\n`),
+			respondWithCluster("installing"),
+			respondWithLog("2", ``),
+			respondWithLog("1", ``),
+			respondWithLog("", `new 1\n`),
+			respondWithCluster("ready"),
+			respondWithLog("1", `new 2\n`),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "logs", "mycluster", "--follow", "--interval", "10ms").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(Equal("The log was rotated, writing it from the beginning\n"))
+		Expect(result.OutString()).To(Equal("line 1\nline 2\nnew 1\nnew 2\n"))
+	})
+
+	It("Explains that the logs aren't available yet", func() {
+		apiServer.AppendHandlers(
+			respondWithCluster("pending"),
+			RespondWithJSON(
+				http.StatusNotFound,
+				`{
+					"kind": "Error",
+					"id": "404",
+					"href": "/api/clusters_mgmt/v1/errors/404",
+					"code": "CLUSTERS-MGMT-404",
+					"reason": "Log for cluster '123' not found"
+				}`,
+			),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "logs", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"The install logs of cluster 'mycluster' aren't available, its state is 'pending'",
+		))
+	})
+
+	It("Rejects an unknown type", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "logs", "mycluster", "--type", "upgrade").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid log type 'upgrade', options are [install uninstall]",
+		))
+	})
+})
@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster wait-ready", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	// respondWithCluster returns a handler that responds with the cluster in the given state:
+	respondWithCluster := func(state string) http.HandlerFunc {
+		return RespondWithJSONTemplate(
+			http.StatusOK,
+			`{
+				"kind": "Cluster",
+				"id": "123",
+				"name": "mycluster",
+				"state": "{{ .state }}",
+				"status": {
+					"state": "{{ .state }}",
+					"provision_error_code": "OCM3055",
+					"provision_error_message": "Quota exceeded"
+				}
+			}`,
+			"state", state,
+		)
+	}
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The cluster is found using the subscription:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			respondWithCluster("installing"),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Prints the state changes till the cluster is ready", func() {
+		// Prepare the server:
+		apiServer.AppendHandlers(
+			respondWithCluster("installing"),
+			respondWithCluster("installing"),
+			respondWithCluster("ready"),
+		)
+
+		// Run the command:
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "wait-ready", "mycluster", "--interval", "10ms").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrLines()).To(Equal([]string{
+			"Cluster 'mycluster' is installing",
+			"Cluster 'mycluster' is ready",
+		}))
+	})
+
+	It("Doesn't print the state changes with --quiet", func() {
+		// Prepare the server:
+		apiServer.AppendHandlers(
+			respondWithCluster("ready"),
+		)
+
+		// Run the command:
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "wait-ready", "mycluster", "--interval", "10ms", "--quiet").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+	})
+
+	It("Fails if the cluster is in the error state", func() {
+		// Prepare the server:
+		apiServer.AppendHandlers(
+			respondWithCluster("error"),
+		)
+
+		// Run the command:
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "wait-ready", "mycluster", "--interval", "10ms", "--quiet").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Cluster 'mycluster' failed to install: Quota exceeded (OCM3055)",
+		))
+	})
+
+	It("Fails if the cluster isn't ready before the timeout", func() {
+		// Prepare the server:
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/clusters/123",
+			respondWithCluster("installing"),
+		)
+
+		// Run the command:
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"cluster", "wait-ready", "mycluster",
+				"--interval", "10ms",
+				"--timeout", "200ms",
+				"--quiet",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Cluster 'mycluster' isn't ready after 200ms",
+		))
+	})
+})
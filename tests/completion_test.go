@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Completion", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+	})
+
+	When("Not logged in", func() {
+		It("Returns no candidates and no errors", func() {
+			result := NewCommand().
+				Args("__complete", "list", "idps", "--cluster", "").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).To(Equal(":4\n"))
+			Expect(result.ErrString()).ToNot(ContainSubstring("Not logged in"))
+		})
+	})
+
+	It("Completes the output formats", func() {
+		result := NewCommand().
+			Args("__complete", "describe", "idp", "--output", "").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutLines()).To(Equal([]string{
+			"json",
+			"yaml",
+			"go-template=",
+			"go-template-file=",
+			":4",
+		}))
+	})
+
+	It("Doesn't add a space after the template prefixes", func() {
+		result := NewCommand().
+			Args("__complete", "list", "clusters", "--output", "go-").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutLines()).To(Equal([]string{
+			"go-template=",
+			"go-template-file=",
+			":6",
+		}))
+	})
+
+	It("Completes the API paths without a connection", func() {
+		result := NewCommand().
+			Args("__complete", "get", "/api/clusters_mgmt/v1/cl").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutLines()).To(Equal([]string{
+			"/api/clusters_mgmt/v1/cloud_providers",
+			"/api/clusters_mgmt/v1/clusters",
+			":6",
+		}))
+	})
+
+	It("Completes the resource aliases", func() {
+		result := NewCommand().
+			Args("__complete", "delete", "clus").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutLines()).To(Equal([]string{
+			"cluster",
+			"clusters",
+			":4",
+		}))
+	})
+
+	It("Returns no GitHub organizations without client credentials", func() {
+		result := NewCommand().
+			Args("__complete", "create", "idp", "--type", "github", "--organizations", "").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(Equal(":6\n"))
+	})
+
+	When("Logged in", func() {
+		var ssoServer *Server
+		var apiServer *Server
+		var config string
+
+		BeforeEach(func() {
+			// Create the servers:
+			ssoServer = MakeTCPServer()
+			apiServer = MakeTCPServer()
+
+			// Login:
+			ssoServer.AppendHandlers(
+				RespondWithAccessToken(MakeTokenString("Bearer", 15*time.Minute)),
+			)
+			result := NewCommand().
+				Args(
+					"login",
+					"--client-id", "my-client",
+					"--client-secret", "my-secret",
+					"--token-url", ssoServer.URL(),
+					"--url", apiServer.URL(),
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			config = result.ConfigString()
+		})
+
+		AfterEach(func() {
+			// Close the servers:
+			ssoServer.Close()
+			apiServer.Close()
+		})
+
+		It("Offers the names and identifiers of the clusters", func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyFormKV("search", "name like 'my%' or id like 'my%'"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "ClusterList",
+							"page": 1,
+							"size": 1,
+							"total": 1,
+							"items": [
+							  {
+								"kind": "Cluster",
+								"id": "123",
+								"name": "mycluster"
+							  }
+							]
+						  }`,
+					),
+				),
+			)
+			result := NewCommand().
+				ConfigString(config).
+				Args("__complete", "list", "idps", "--cluster", "my").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).To(Equal("mycluster\t123\n:4\n"))
+		})
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2" // nolint
+	. "github.com/onsi/gomega"    // nolint
+)
+
+var _ = Describe("Config", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("Sets a valid URL", func() {
+		result := NewCommand().
+			ConfigString(`{}`).
+			Args("config", "set", "url", "https://api.example.com").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ConfigString()).To(MatchJSON(`{
+			"url": "https://api.example.com"
+		}`))
+	})
+
+	It("Rejects an invalid URL", func() {
+		result := NewCommand().
+			ConfigString(`{}`).
+			Args("config", "set", "url", "api.example.com").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("Invalid value 'api.example.com' for url"))
+		Expect(result.ConfigString()).To(MatchJSON(`{}`))
+	})
+
+	It("Rejects an invalid token", func() {
+		result := NewCommand().
+			ConfigString(`{}`).
+			Args("config", "set", "refresh_token", "junk").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("expected a JSON web token"))
+	})
+
+	It("Rejects an unknown setting listing the valid ones", func() {
+		result := NewCommand().
+			ConfigString(`{}`).
+			Args("config", "set", "junk", "value").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Unknown setting 'junk', valid settings are: access_token, client_id",
+		))
+	})
+
+	It("Accepts names with dashes", func() {
+		result := NewCommand().
+			ConfigString(`{}`).
+			Args("config", "set", "client-id", "my-client").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ConfigString()).To(MatchJSON(`{
+			"client_id": "my-client"
+		}`))
+	})
+
+	It("Prints only the value of a setting", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"url": "https://api.example.com"
+			}`).
+			Args("config", "get", "url").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(Equal("https://api.example.com\n"))
+	})
+
+	It("Prints the user", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"user": "my-user"
+			}`).
+			Args("config", "get", "user").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(Equal("my-user\n"))
+	})
+
+	It("Sets a value in a profile preserving the default one", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"url": "https://api.example.com"
+			}`).
+			Args("config", "set", "--profile", "staging", "url", "https://api.stage.example.com").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ConfigString()).To(MatchJSON(`{
+			"url": "https://api.example.com",
+			"profiles": {
+				"staging": {
+					"url": "https://api.stage.example.com"
+				}
+			}
+		}`))
+	})
+
+	It("Gets a value from the profile given in the environment", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"url": "https://api.example.com",
+				"profiles": {
+					"staging": {
+						"url": "https://api.stage.example.com"
+					}
+				}
+			}`).
+			Env("OCM_PROFILE", "staging").
+			Args("config", "get", "url").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(Equal("https://api.stage.example.com\n"))
+	})
+
+	It("Lists the profiles marking the active one", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"profiles": {
+					"staging": {},
+					"integration": {}
+				}
+			}`).
+			Args("config", "list-profiles", "--profile", "staging").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutLines()).To(Equal([]string{
+			"  default",
+			"  integration",
+			"* staging",
+		}))
+	})
+
+	It("Prints the config with the credentials redacted", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"client_id": "my-client",
+				"client_secret": "my-secret",
+				"url": "https://api.example.com",
+				"profiles": {
+					"staging": {
+						"url": "https://api.stage.example.com",
+						"access_token": "my-access-token",
+						"refresh_token": "my-refresh-token"
+					}
+				}
+			}`).
+			Args("config", "view", "--profile", "staging").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(MatchJSON(`{
+			"file": "` + result.ConfigFile() + `",
+			"profile": "staging",
+			"config": {
+				"url": "https://api.stage.example.com",
+				"access_token": "REDACTED",
+				"refresh_token": "REDACTED"
+			}
+		}`))
+	})
+
+	It("Prints the config in YAML", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"client_id": "my-client",
+				"client_secret": "my-secret"
+			}`).
+			Args("config", "view", "--output", "yaml").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(MatchYAML(`
+file: ` + result.ConfigFile() + `
+profile: default
+config:
+  client_id: my-client
+  client_secret: REDACTED
+`))
+		Expect(result.OutString()).ToNot(ContainSubstring("my-secret"))
+	})
+})
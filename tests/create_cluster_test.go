@@ -0,0 +1,412 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Create cluster", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The options used to validate the flags are requested before creating the cluster:
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/cloud_providers/aws/regions",
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "CloudRegionList",
+				"page": 1,
+				"size": 1,
+				"total": 1,
+				"items": [
+					{
+						"kind": "CloudRegion",
+						"id": "us-east-1",
+						"enabled": true,
+						"supports_multi_az": true
+					}
+				]
+			}`),
+		)
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "VersionList",
+				"page": 1,
+				"size": 1,
+				"total": 1,
+				"items": [
+					{
+						"kind": "Version",
+						"id": "openshift-v4.12.1",
+						"enabled": true,
+						"default": true
+					}
+				]
+			}`),
+		)
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/flavours",
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "FlavourList",
+				"page": 1,
+				"size": 1,
+				"total": 1,
+				"items": [
+					{
+						"kind": "Flavour",
+						"id": "osd-4"
+					}
+				]
+			}`),
+		)
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/machine_types",
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "MachineTypeList",
+				"page": 1,
+				"size": 1,
+				"total": 1,
+				"items": [
+					{
+						"kind": "MachineType",
+						"id": "m5.xlarge",
+						"name": "m5.xlarge - General Purpose"
+					}
+				]
+			}`),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Writes the description of the cluster in dry run mode", func() {
+		apiServer.RouteToHandler(
+			http.MethodPost,
+			"/api/clusters_mgmt/v1/clusters",
+			CombineHandlers(
+				VerifyFormKV("dryRun", "true"),
+				RespondWithJSON(http.StatusNoContent, ""),
+			),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "cluster",
+				"--dry-run",
+				"--region", "us-east-1",
+				"--compute-nodes", "4",
+				"my-cluster",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(Equal("dry run: Would be successful.\n"))
+		Expect(result.OutString()).To(MatchJSON(`{
+			"kind": "Cluster",
+			"api": {
+				"listening": "external"
+			},
+			"cloud_provider": {
+				"kind": "CloudProvider",
+				"id": "aws"
+			},
+			"etcd_encryption": false,
+			"flavour": {
+				"kind": "Flavour",
+				"id": "osd-4"
+			},
+			"multi_az": false,
+			"name": "my-cluster",
+			"nodes": {
+				"compute": 4
+			},
+			"properties": {},
+			"region": {
+				"kind": "CloudRegion",
+				"id": "us-east-1"
+			},
+			"version": {
+				"kind": "Version",
+				"id": "openshift-v4.12.1",
+				"channel_group": ""
+			}
+		}`))
+	})
+
+	It("Reports the validation errors of the server in dry run mode", func() {
+		apiServer.RouteToHandler(
+			http.MethodPost,
+			"/api/clusters_mgmt/v1/clusters",
+			RespondWithJSON(http.StatusBadRequest, `{
+				"kind": "Error",
+				"id": "400",
+				"href": "/api/clusters_mgmt/v1/errors/400",
+				"code": "CLUSTERS-MGMT-400",
+				"reason": "Cluster name 'my-cluster' already exists"
+			}`),
+		)
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "cluster",
+				"--dry-run",
+				"--region", "us-east-1",
+				"my-cluster",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"dry run: unable to create cluster",
+		))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Cluster name 'my-cluster' already exists",
+		))
+	})
+
+	It("Rejects a region that isn't available", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "cluster",
+				"--dry-run",
+				"--region", "mars-north-1",
+				"my-cluster",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("A valid --region must be specified"))
+	})
+
+	When("The cluster is described in a manifest file", func() {
+		var tmp string
+
+		BeforeEach(func() {
+			var err error
+			tmp, err = os.MkdirTemp("", "ocm-test-*.d")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(tmp)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		writeManifest := func(content string) string {
+			file := filepath.Join(tmp, "cluster.yaml")
+			err := os.WriteFile(file, []byte(RemoveLeadingTabs(content)), 0600)
+			Expect(err).ToNot(HaveOccurred())
+			return file
+		}
+
+		It("Sends the cluster of the manifest", func() {
+			file := writeManifest(`
+				name: my-cluster
+				cloud_provider:
+				  id: aws
+				region:
+				  id: us-east-1
+				network:
+				  machine_cidr: 10.0.0.0/16
+				  service_cidr: 172.30.0.0/16
+				  pod_cidr: 10.128.0.0/14
+				  host_prefix: 23
+				nodes:
+				  compute: 4
+				aws:
+				  access_key_id: my-key
+				  secret_access_key: my-secret
+			`)
+			apiServer.RouteToHandler(
+				http.MethodPost,
+				"/api/clusters_mgmt/v1/clusters",
+				CombineHandlers(
+					VerifyFormKV("dryRun", "true"),
+					VerifyJSON(`{
+						"kind": "Cluster",
+						"name": "my-cluster",
+						"cloud_provider": {
+							"kind": "CloudProvider",
+							"id": "aws"
+						},
+						"region": {
+							"kind": "CloudRegion",
+							"id": "us-east-1"
+						},
+						"network": {
+							"machine_cidr": "10.0.0.0/16",
+							"service_cidr": "172.30.0.0/16",
+							"pod_cidr": "10.128.0.0/14",
+							"host_prefix": 23
+						},
+						"nodes": {
+							"compute": 4
+						},
+						"aws": {
+							"access_key_id": "my-key",
+							"secret_access_key": "my-secret"
+						}
+					}`),
+					RespondWithJSON(http.StatusNoContent, ""),
+				),
+			)
+			result := NewCommand().
+				ConfigString(config).
+				Args("create", "cluster", "--from-file", file, "--dry-run").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(Equal("dry run: Would be successful.\n"))
+			Expect(result.OutString()).To(MatchJSON(`{
+				"kind": "Cluster",
+				"name": "my-cluster",
+				"cloud_provider": {
+					"kind": "CloudProvider",
+					"id": "aws"
+				},
+				"region": {
+					"kind": "CloudRegion",
+					"id": "us-east-1"
+				},
+				"network": {
+					"machine_cidr": "10.0.0.0/16",
+					"service_cidr": "172.30.0.0/16",
+					"pod_cidr": "10.128.0.0/14",
+					"host_prefix": 23
+				},
+				"nodes": {
+					"compute": 4
+				},
+				"aws": {
+					"access_key_id": "my-key",
+					"secret_access_key": "<redacted>"
+				}
+			}`))
+		})
+
+		It("Rejects overlapping networks before sending the request", func() {
+			file := writeManifest(`
+				name: my-cluster
+				network:
+				  machine_cidr: 10.128.0.0/16
+				  pod_cidr: 10.128.0.0/14
+			`)
+			result := NewCommand().
+				ConfigString(config).
+				Args("create", "cluster", "--from-file", file).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"The machine CIDR '10.128.0.0/16' overlaps with the pod CIDR '10.128.0.0/14'",
+			))
+			Expect(apiServer.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("Rejects a manifest without name", func() {
+			file := writeManifest(`
+				region:
+				  id: us-east-1
+			`)
+			result := NewCommand().
+				ConfigString(config).
+				Args("create", "cluster", "--from-file", file).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("it must contain the 'name' of the cluster"))
+		})
+
+		It("Rejects the flags that describe the cluster", func() {
+			file := writeManifest(`
+				name: my-cluster
+			`)
+			result := NewCommand().
+				ConfigString(config).
+				Args("create", "cluster", "--from-file", file, "--region", "us-east-1").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"The --region flag can't be used together with --from-file",
+			))
+			Expect(apiServer.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	It("Rejects overlapping networks given with flags", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "cluster",
+				"--region", "us-east-1",
+				"--service-cidr", "10.0.0.0/24",
+				"--machine-cidr", "10.0.0.0/16",
+				"my-cluster",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"The machine CIDR '10.0.0.0/16' overlaps with the service CIDR '10.0.0.0/24'",
+		))
+	})
+})
@@ -0,0 +1,197 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Create machine pool", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The command finds the multi-zone cluster and then retrieves the machine types:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"multi_az": true,
+					"cloud_provider": {
+						"id": "aws"
+					},
+					"ccs": {
+						"enabled": true
+					}
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "MachineTypeList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "MachineType",
+							"id": "m5.xlarge",
+							"name": "m5.xlarge - General Purpose"
+						}
+					]
+				}`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Rejects a number of replicas that isn't a multiple of 3 in multi-zone clusters", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "machinepool",
+				"--cluster", "mycluster",
+				"--instance-type", "m5.xlarge",
+				"--replicas", "2",
+				"mp-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Multi-zone clusters require --replicas to be a multiple of 3",
+		))
+	})
+
+	It("Rejects a negative number of replicas", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "machinepool",
+				"--cluster", "mycluster",
+				"--instance-type", "m5.xlarge",
+				"--replicas", "-3",
+				"mp-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Minimum number of replicas is 0, but --replicas is -3",
+		))
+	})
+
+	It("Creates the machine pool with the labels", func() {
+		// Prepare the server:
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/machine_pools"),
+				VerifyJSON(`{
+					"kind": "MachinePool",
+					"id": "mp-1",
+					"instance_type": "m5.xlarge",
+					"labels": {
+						"foo": "bar"
+					},
+					"replicas": 3,
+					"taints": []
+				}`),
+				RespondWithJSON(http.StatusCreated, `{
+					"kind": "MachinePool",
+					"id": "mp-1"
+				}`),
+			),
+		)
+
+		// Run the command:
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"create", "machinepool",
+				"--cluster", "mycluster",
+				"--instance-type", "m5.xlarge",
+				"--replicas", "3",
+				"--labels", "foo=bar",
+				"mp-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Default cluster", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	// clusterHandlers returns the handlers that respond to the requests sent to find the cluster
+	// with the given key:
+	clusterHandlers := func(key string) []http.HandlerFunc {
+		return []http.HandlerFunc{
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/accounts_mgmt/v1/subscriptions"),
+				VerifyFormKV("search", "(display_name = '"+key+"' or cluster_id = '"+key+
+					"' or external_cluster_id = '"+key+"')"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "SubscriptionList",
+						"page": 1,
+						"size": 1,
+						"total": 1,
+						"items": [
+							{
+								"kind": "Subscription",
+								"id": "456",
+								"status": "Active",
+								"cluster_id": "123"
+							}
+						]
+					}`,
+				),
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready"
+				}`,
+			),
+		}
+	}
+
+	// machinePoolsHandler responds to the request sent to list the machine pools:
+	machinePoolsHandler := CombineHandlers(
+		VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/machine_pools"),
+		RespondWithJSON(
+			http.StatusOK,
+			`{
+				"kind": "MachinePoolList",
+				"page": 1,
+				"size": 0,
+				"total": 0,
+				"items": []
+			}`,
+		),
+	)
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Saves the identifier of the cluster", func() {
+		apiServer.AppendHandlers(clusterHandlers("mycluster")...)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "use", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal("Using cluster 'mycluster' (123) by default\n"))
+		Expect(result.ConfigString()).To(ContainSubstring(`"cluster": "123"`))
+
+		// Check that the saved cluster is printed:
+		result = NewCommand().
+			ConfigString(result.ConfigString()).
+			Args("cluster", "current").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(Equal("123\n"))
+	})
+
+	It("Fails to print the cluster if none has been selected", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("cluster", "current").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"No default cluster has been selected, use 'ocm cluster use' to select one",
+		))
+	})
+
+	When("A default cluster has been selected", func() {
+		BeforeEach(func() {
+			apiServer.AppendHandlers(clusterHandlers("mycluster")...)
+			result := NewCommand().
+				ConfigString(config).
+				Args("cluster", "use", "mycluster").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			config = result.ConfigString()
+		})
+
+		It("Uses it when '--cluster' isn't given", func() {
+			apiServer.AppendHandlers(clusterHandlers("123")...)
+			apiServer.AppendHandlers(machinePoolsHandler)
+
+			result := NewCommand().
+				ConfigString(config).
+				Args("list", "machinepools").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()[0]).To(HavePrefix("ID"))
+		})
+
+		It("Uses '--cluster' when it is given", func() {
+			apiServer.AppendHandlers(clusterHandlers("othercluster")...)
+			apiServer.AppendHandlers(machinePoolsHandler)
+
+			result := NewCommand().
+				ConfigString(config).
+				Args("list", "machinepools", "--cluster", "othercluster").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
+		It("Is removed when logging out", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("logout").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ConfigString()).ToNot(ContainSubstring(`"cluster"`))
+		})
+	})
+
+	It("Requires '--cluster' if no default cluster has been selected", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "machinepools").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Option '--cluster' is mandatory unless a default cluster has been selected " +
+				"with 'ocm cluster use'",
+		))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+})
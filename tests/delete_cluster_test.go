@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Delete cluster", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	// respondWithCluster returns a handler that sends the cluster in the given state:
+	respondWithCluster := func(state string) http.HandlerFunc {
+		return CombineHandlers(
+			VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+			RespondWithJSONTemplate(http.StatusOK, `{
+				"kind": "Cluster",
+				"id": "123",
+				"state": "{{ .State }}"
+			}`, "State", state),
+		)
+	}
+
+	It("Doesn't wait without --watch", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusNoContent, ""),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("delete", "cluster", "123").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Waits till the cluster doesn't exist with --watch", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusNoContent, ""),
+			),
+			respondWithCluster("uninstalling"),
+			respondWithCluster("uninstalling"),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusNotFound, `{
+					"kind": "Error",
+					"id": "404",
+					"reason": "Cluster '123' not found"
+				}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("delete", "cluster", "123", "--watch", "--interval", "10ms").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrLines()).To(Equal([]string{
+			"Cluster '123' is uninstalling",
+			"Cluster '123' has been deleted",
+		}))
+	})
+
+	It("Fails if the cluster still exists after the timeout", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusNoContent, ""),
+			),
+		)
+		apiServer.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/clusters/123",
+			respondWithCluster("uninstalling"),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"delete", "cluster", "123",
+				"--watch", "--interval", "10ms", "--timeout", "200ms",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Cluster '123' still exists after 200ms",
+		))
+	})
+
+	It("Rejects --watch for other resources", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("delete", "/api/clusters_mgmt/v1/clusters/123/groups/dedicated-admins", "--watch").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Option '--watch' can only be used to delete clusters",
+		))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+})
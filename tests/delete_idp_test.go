@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Delete IDP", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The command finds the cluster before the identity provider:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "111",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready"
+				}`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Deletes an identity provider that is in the second page", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyFormKV("page", "1"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProviderList",
+						"page": 1,
+						"size": 1,
+						"total": 2,
+						"items": [
+							{
+								"kind": "IdentityProvider",
+								"id": "456",
+								"name": "github-1",
+								"type": "GithubIdentityProvider"
+							}
+						]
+					}`,
+				),
+			),
+			CombineHandlers(
+				VerifyFormKV("page", "2"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProviderList",
+						"page": 2,
+						"size": 1,
+						"total": 2,
+						"items": [
+							{
+								"kind": "IdentityProvider",
+								"id": "789",
+								"name": "ldap-1",
+								"type": "LDAPIdentityProvider"
+							}
+						]
+					}`,
+				),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+				),
+				RespondWithJSON(http.StatusNoContent, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"delete", "idp",
+				"--cluster", "mycluster",
+				"--yes",
+				"ldap-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(Equal(
+			"Deleted identity provider 'ldap-1' on cluster 'mycluster'\n",
+		))
+	})
+
+	It("Refuses to delete when more than one identity provider has the name", func() {
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "IdentityProviderList",
+					"page": 1,
+					"size": 2,
+					"total": 2,
+					"items": [
+						{
+							"kind": "IdentityProvider",
+							"id": "456",
+							"name": "ldap-1",
+							"type": "LDAPIdentityProvider"
+						},
+						{
+							"kind": "IdentityProvider",
+							"id": "789",
+							"name": "ldap-1",
+							"type": "LDAPIdentityProvider"
+						}
+					]
+				}`,
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"delete", "idp",
+				"--cluster", "mycluster",
+				"--yes",
+				"ldap-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"use one of the identifiers instead: 456, 789",
+		))
+		for _, request := range apiServer.ReceivedRequests() {
+			Expect(request.Method).To(Equal(http.MethodGet))
+		}
+	})
+})
@@ -108,6 +108,16 @@ var _ = Describe("Describe clusters", func() {
 						"items": []
 					  }`,
 				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 0,
+						"total": 0,
+						"items": []
+					  }`,
+				),
 			)
 
 			// Run the command:
@@ -121,6 +131,169 @@ var _ = Describe("Describe clusters", func() {
 			))
 		})
 
+		It("Describe a cluster using a prefix of its name", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "SubscriptionList",
+						"page": 1,
+						"size": 0,
+						"total": 0,
+						"items": []
+					  }`,
+				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 0,
+						"total": 0,
+						"items": []
+					  }`,
+				),
+				CombineHandlers(
+					VerifyFormKV("search", "name like 'my_%'"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "ClusterList",
+							"page": 1,
+							"size": 2,
+							"total": 2,
+							"items": [
+							  {
+								"kind": "Cluster",
+								"id": "123",
+								"name": "my_cluster"
+							  },
+							  {
+								"kind": "Cluster",
+								"id": "456",
+								"name": "mycluster"
+							  }
+							]
+						  }`,
+					),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args("describe", "cluster", "--json", "my_").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutString()).To(MatchJSON(`{
+				"kind": "Cluster",
+				"id": "123",
+				"name": "my_cluster"
+			}`))
+		})
+
+		It("Describe a cluster with an ambiguous prefix", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "SubscriptionList",
+						"page": 1,
+						"size": 0,
+						"total": 0,
+						"items": []
+					  }`,
+				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 0,
+						"total": 0,
+						"items": []
+					  }`,
+				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 2,
+						"total": 2,
+						"items": [
+						  {
+							"kind": "Cluster",
+							"id": "123",
+							"name": "prod-east"
+						  },
+						  {
+							"kind": "Cluster",
+							"id": "456",
+							"name": "prod-west"
+						  }
+						]
+					  }`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args("describe", "cluster", "prod").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"There are 2 clusters with a name that starts with 'prod', use a longer prefix " +
+					"or one of the identifiers instead: 123 (prod-east), 456 (prod-west)",
+			))
+		})
+
+		It("Describe a cluster with an ambiguous name", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "SubscriptionList",
+						"page": 1,
+						"size": 2,
+						"total": 2,
+						"items": [
+						  {
+							"id": "111",
+							"kind": "Subscription",
+							"display_name": "test",
+							"status": "Active",
+							"cluster_id": "123"
+						  },
+						  {
+							"id": "222",
+							"kind": "Subscription",
+							"display_name": "test",
+							"status": "Active",
+							"cluster_id": "456"
+						  }
+						]
+					  }`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args("describe", "cluster", "test").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"There are 2 subscriptions with cluster identifier or name 'test', use one of " +
+					"the cluster identifiers instead: 123 (test), 456 (test)",
+			))
+		})
+
 		It("Describe an exist cluster", func() {
 			// Prepare the server:
 			apiServer.AppendHandlers(
@@ -319,6 +492,102 @@ var _ = Describe("Describe clusters", func() {
 
 		})
 
+		It("Adds the node and network details with --wide", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "SubscriptionList",
+						"page": 1,
+						"size": 1,
+						"total": 1,
+						"items": [
+							{
+								"id": "111",
+								"kind": "Subscription",
+								"status": "Active",
+								"cluster_id": "111"
+							}
+						]
+					}`,
+				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "Cluster",
+						"id": "111",
+						"name": "test",
+						"subscription": {
+							"kind": "SubscriptionLink",
+							"id": "111"
+						},
+						"dns": {
+							"base_domain": "example.org"
+						},
+						"nodes": {
+							"master": 3,
+							"infra": 2,
+							"compute": 2,
+							"availability_zones": [
+								"us-east-1a",
+								"us-east-1b"
+							],
+							"compute_machine_type": {
+								"id": "m5.xlarge"
+							}
+						},
+						"network": {
+							"type": "OVNKubernetes",
+							"machine_cidr": "10.0.0.0/16",
+							"service_cidr": "172.30.0.0/16",
+							"pod_cidr": "10.128.0.0/14",
+							"host_prefix": 23
+						},
+						"version": {
+							"id": "openshift-v4.12.1",
+							"channel_group": "stable",
+							"available_upgrades": [
+								"4.12.2",
+								"4.12.3"
+							]
+						},
+						"state": "ready"
+					}`,
+				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"id": "111",
+						"kind": "Subscription",
+						"status": "Active"
+					}`,
+				),
+				RespondWithJSON(
+					http.StatusNotFound,
+					`{}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"describe", "cluster", "test", "--wide",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			output := result.OutString()
+			Expect(output).To(MatchRegexp(`Base Domain:\s+example.org\n`))
+			Expect(output).To(MatchRegexp(`Availability Zones:\s+us-east-1a, us-east-1b\n`))
+			Expect(output).To(MatchRegexp(`Compute Machine Type:\s+m5.xlarge\n`))
+			Expect(output).To(MatchRegexp(`Network Type:\s+OVNKubernetes\n`))
+			Expect(output).To(MatchRegexp(`Service CIDR:\s+172.30.0.0/16\n`))
+			Expect(output).To(MatchRegexp(`Host Prefix:\s+23\n`))
+			Expect(output).To(MatchRegexp(`Available Upgrades:\s+4.12.2, 4.12.3\n`))
+		})
+
 		It("Describe a cluster with multiple matching subscriptions", func() {
 			// Prepare the server:
 			apiServer.AppendHandlers(
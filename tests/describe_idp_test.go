@@ -0,0 +1,277 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Describe identity providers", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The cluster and its identity providers:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+					  {
+						"id": "111",
+						"kind": "Subscription",
+						"status": "Active",
+						"cluster_id": "123"
+					  }
+					]
+				  }`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"console": {
+					  "url": "https://console-openshift-console.apps.mycluster.example.com"
+					}
+				  }`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "IdentityProviderList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+					  {
+						"kind": "IdentityProvider",
+						"id": "456",
+						"name": "github-1",
+						"type": "GithubIdentityProvider",
+						"mapping_method": "claim",
+						"github": {
+						  "client_id": "abcdefgh1234",
+						  "client_secret": "my-secret-value",
+						  "organizations": ["myorg"]
+						}
+					  }
+					]
+				  }`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Describes the identity provider without the client secret", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("describe", "idp", "github-1", "--cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(ContainSubstring("myorg"))
+		Expect(result.OutString()).To(ContainSubstring("********1234"))
+		Expect(result.OutString()).To(ContainSubstring("<redacted>"))
+		Expect(result.OutString()).ToNot(ContainSubstring("my-secret-value"))
+	})
+
+	It("Describes the identity provider in YAML without the client secret", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("describe", "idp", "github-1", "--cluster", "mycluster", "--output", "yaml").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(ContainSubstring("client_secret: <redacted>"))
+		Expect(result.OutString()).ToNot(ContainSubstring("my-secret-value"))
+		Expect(result.OutString()).To(ContainSubstring("client_id: '********1234'"))
+		Expect(result.OutString()).ToNot(ContainSubstring("abcdefgh1234"))
+	})
+
+	It("Describes the identity provider in JSON with the client identifier hidden", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("describe", "idp", "github-1", "--cluster", "mycluster", "--output", "json").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(ContainSubstring(`"client_id": "********1234"`))
+		Expect(result.OutString()).ToNot(ContainSubstring("abcdefgh1234"))
+		Expect(result.OutString()).ToNot(ContainSubstring("my-secret-value"))
+	})
+})
+
+var _ = Describe("Describe identity providers beyond the first page", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The cluster and its identity providers, returned in two pages:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+					  {
+						"id": "111",
+						"kind": "Subscription",
+						"status": "Active",
+						"cluster_id": "123"
+					  }
+					]
+				  }`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready"
+				  }`,
+			),
+			CombineHandlers(
+				VerifyFormKV("page", "1"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProviderList",
+						"page": 1,
+						"size": 1,
+						"total": 2,
+						"items": [
+						  {
+							"kind": "IdentityProvider",
+							"id": "456",
+							"name": "github-1",
+							"type": "GithubIdentityProvider"
+						  }
+						]
+					  }`,
+				),
+			),
+			CombineHandlers(
+				VerifyFormKV("page", "2"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProviderList",
+						"page": 2,
+						"size": 1,
+						"total": 2,
+						"items": [
+						  {
+							"kind": "IdentityProvider",
+							"id": "789",
+							"name": "ldap-1",
+							"type": "LDAPIdentityProvider",
+							"mapping_method": "lookup"
+						  }
+						]
+					  }`,
+				),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Finds the identity provider in the second page", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("describe", "idp", "ldap-1", "--cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(ContainSubstring("789"))
+		Expect(result.OutString()).To(ContainSubstring("lookup"))
+	})
+})
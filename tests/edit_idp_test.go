@@ -0,0 +1,427 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Edit IDP", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The command finds the cluster and the identity provider before editing it:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready"
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "IdentityProviderList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "IdentityProvider",
+							"id": "789",
+							"name": "github-1",
+							"type": "GithubIdentityProvider",
+							"mapping_method": "claim",
+							"github": {
+								"client_id": "abc",
+								"organizations": [
+									"oldorg"
+								]
+							}
+						}
+					]
+				}`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Changes the hostname and the organizations in a single request", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+				),
+				VerifyJSON(`{
+					"kind": "IdentityProvider",
+					"type": "GithubIdentityProvider",
+					"github": {
+						"hostname": "ghe.example.com",
+						"organizations": [
+							"myorg",
+							"otherorg"
+						]
+					}
+				}`),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProvider",
+						"id": "789",
+						"name": "github-1"
+					}`,
+				),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "idp",
+				"--cluster", "mycluster",
+				"--hostname", "ghe.example.com",
+				"--organizations", "myorg,otherorg",
+				"github-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal(
+			"Updated identity provider 'github-1' on cluster 'mycluster'\n",
+		))
+
+		// Check that there is exactly one request that changes the identity provider:
+		patches := 0
+		for _, request := range apiServer.ReceivedRequests() {
+			if request.Method != http.MethodGet {
+				Expect(request.Method).To(Equal(http.MethodPatch))
+				patches++
+			}
+		}
+		Expect(patches).To(Equal(1))
+	})
+
+	It("Fails without sending a request if nothing is changed", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "idp",
+				"--cluster", "mycluster",
+				"github-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Nothing to edit, at least one value must be changed",
+		))
+		for _, request := range apiServer.ReceivedRequests() {
+			Expect(request.Method).To(Equal(http.MethodGet))
+		}
+	})
+
+	It("Rejects an invalid mapping method without sending a request", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "idp",
+				"--cluster", "mycluster",
+				"--mapping-method", "wrong",
+				"github-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid mapping method 'wrong', valid options are [claim lookup generate add]",
+		))
+		for _, request := range apiServer.ReceivedRequests() {
+			Expect(request.Method).To(Equal(http.MethodGet))
+		}
+	})
+
+	It("Preserves the organizations if they aren't given", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+				),
+				VerifyJSON(`{
+					"kind": "IdentityProvider",
+					"type": "GithubIdentityProvider",
+					"github": {
+						"hostname": "ghe.example.com",
+						"organizations": [
+							"oldorg"
+						]
+					}
+				}`),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProvider",
+						"id": "789",
+						"name": "github-1"
+					}`,
+				),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "idp",
+				"--cluster", "mycluster",
+				"--hostname", "ghe.example.com",
+				"github-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(Equal(
+			"Updated identity provider 'github-1' on cluster 'mycluster'\n",
+		))
+	})
+
+	It("Rejects switching from organizations to teams without sending a request", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "idp",
+				"--cluster", "mycluster",
+				"--teams", "myorg/myteam",
+				"github-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"GitHub IDP only allows either organizations or teams, but not both",
+		))
+		for _, request := range apiServer.ReceivedRequests() {
+			Expect(request.Method).To(Equal(http.MethodGet))
+		}
+	})
+
+	It("Finds the identity provider by identifier", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+				),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "IdentityProvider",
+						"id": "789",
+						"name": "github-1"
+					}`,
+				),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "idp",
+				"--cluster", "mycluster",
+				"--mapping-method", "lookup",
+				"789",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(Equal(
+			"Updated identity provider 'github-1' on cluster 'mycluster'\n",
+		))
+	})
+
+	When("Running in a terminal", func() {
+		// Keys typed in the terminal:
+		const (
+			space = " "
+			down  = "\x1b[B"
+			enter = "\r"
+		)
+
+		It("Sends the values selected in the menu in a single request", func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(
+						http.MethodPatch,
+						"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+					),
+					VerifyJSON(`{
+						"kind": "IdentityProvider",
+						"type": "GithubIdentityProvider",
+						"mapping_method": "lookup",
+						"github": {
+							"client_id": "def",
+							"organizations": [
+								"oldorg"
+							]
+						}
+					}`),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "IdentityProvider",
+							"id": "789",
+							"name": "github-1"
+						}`,
+					),
+				),
+			)
+
+			// Select the client identifier and the mapping method, the first and third
+			// options of the menu, and then answer the prompts of those two values only:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"edit", "idp",
+					"--cluster", "mycluster",
+					"github-1",
+				).
+				Answer("Values to change:", space+down+down+space+enter).
+				Answer("Client ID:", "def"+enter).
+				Answer("Mapping method:", down+enter).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).ToNot(ContainSubstring("Client secret:"))
+			Expect(result.OutString()).To(ContainSubstring(
+				"Updated identity provider 'github-1' on cluster 'mycluster'",
+			))
+
+			// Check that there is exactly one request that changes the identity provider:
+			patches := 0
+			for _, request := range apiServer.ReceivedRequests() {
+				if request.Method != http.MethodGet {
+					Expect(request.Method).To(Equal(http.MethodPatch))
+					patches++
+				}
+			}
+			Expect(patches).To(Equal(1))
+		})
+
+		It("Doesn't display the menu if a value is given in the command line", func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(
+						http.MethodPatch,
+						"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+					),
+					VerifyJSON(`{
+						"kind": "IdentityProvider",
+						"type": "GithubIdentityProvider",
+						"mapping_method": "lookup",
+						"github": {
+							"organizations": [
+								"oldorg"
+							]
+						}
+					}`),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "IdentityProvider",
+							"id": "789",
+							"name": "github-1"
+						}`,
+					),
+				),
+			)
+
+			result := NewCommand().
+				ConfigString(config).
+				Terminal().
+				Args(
+					"edit", "idp",
+					"--cluster", "mycluster",
+					"--mapping-method", "lookup",
+					"github-1",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).ToNot(ContainSubstring("Values to change:"))
+			Expect(result.OutString()).To(ContainSubstring(
+				"Updated identity provider 'github-1' on cluster 'mycluster'",
+			))
+		})
+	})
+})
@@ -0,0 +1,179 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Edit machine pool", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// The command finds the cluster before checking the flags:
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"multi_az": false,
+					"cloud_provider": {
+						"id": "aws"
+					},
+					"ccs": {
+						"enabled": true
+					}
+				}`,
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Rejects fixed replicas together with autoscaling bounds", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "machinepool",
+				"--cluster", "mycluster",
+				"--replicas", "3",
+				"--min-replicas", "2",
+				"mp-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"--replicas can't be used together with autoscaling",
+		))
+	})
+
+	It("Rejects a minimum number of replicas greater than the maximum", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "machinepool",
+				"--cluster", "mycluster",
+				"--min-replicas", "5",
+				"--max-replicas", "2",
+				"mp-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"--max-replicas must be greater than or equal to --min-replicas",
+		))
+	})
+
+	It("Updates the autoscaling bounds", func() {
+		// Prepare the server:
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/machine_pools/mp-1"),
+				VerifyJSON(`{
+					"kind": "MachinePool",
+					"id": "mp-1",
+					"autoscaling": {
+						"kind": "MachinePoolAutoscaling",
+						"min_replicas": 2,
+						"max_replicas": 5
+					}
+				}`),
+				RespondWithJSON(http.StatusOK, `{
+					"kind": "MachinePool",
+					"id": "mp-1"
+				}`),
+			),
+		)
+
+		// Run the command:
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"edit", "machinepool",
+				"--cluster", "mycluster",
+				"--min-replicas", "2",
+				"--max-replicas", "5",
+				"mp-1",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+	})
+})
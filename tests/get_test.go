@@ -138,6 +138,32 @@ var _ = Describe("Get", func() {
 			Expect(result.ErrString()).To(BeEmpty())
 		})
 
+		It("Encodes the values of the --parameter flag", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/my_service/v1/my_objects"),
+					VerifyFormKV("search", "name like 'my%' and tag = 'a&b=c'"),
+					VerifyFormKV("size", "10"),
+					VerifyFormKV("page", "2"),
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+
+			// Run the command, with one of the parameters in the path:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--parameter", "search=name like 'my%' and tag = 'a&b=c'",
+					"--parameter", "size=10",
+					"/api/my_service/v1/my_objects?page=2",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
 		It("Honours the -p flag as alias to --parameter", func() {
 			// Prepare the server:
 			apiServer.AppendHandlers(
@@ -182,7 +208,83 @@ var _ = Describe("Get", func() {
 			Expect(result.ErrString()).To(BeEmpty())
 		})
 
-		It("Indents by default", func() {
+		It("Accepts headers separated by a colon", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyHeaderKV("Prefer", "return=minimal"),
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--header", "Prefer: return=minimal",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
+		It("Rejects the authorization header", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--header", "Authorization: Bearer abc",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Invalid header 'Authorization: Bearer abc'"))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Rejects invalid header names", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--header", "my header=my_value",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("Invalid header 'my header=my_value'"))
+		})
+
+		It("Is compact by default when the output isn't a terminal", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					RespondWithJSON(http.StatusOK, `{
+						"my_field": "my_value",
+						"your_field": "your_value"
+					}`),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutString()).To(Equal(
+				`{"my_field":"my_value","your_field":"your_value"}` + "\n",
+			))
+		})
+
+		It("Honours the --pretty flag", func() {
 			// Prepare the server:
 			apiServer.AppendHandlers(
 				CombineHandlers(
@@ -198,6 +300,7 @@ var _ = Describe("Get", func() {
 				ConfigString(config).
 				Args(
 					"get",
+					"--pretty",
 					"/api/my_service/v1/my_object",
 				).
 				Run(ctx)
@@ -240,6 +343,71 @@ var _ = Describe("Get", func() {
 			)))
 		})
 
+		It("Rejects --pretty together with --compact", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--pretty",
+					"--compact",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("compact"))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Honours the --filter flag", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					RespondWithJSON(http.StatusOK, `{
+						"items": [
+							{
+								"id": "123",
+								"nodes": {"compute": 3}
+							},
+							{
+								"id": "456",
+								"nodes": {"compute": 5}
+							}
+						]
+					}`),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--filter", "$.items[*].id",
+					"/api/my_service/v1/my_objects",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()).To(Equal([]string{
+				"123",
+				"456",
+			}))
+		})
+
+		It("Rejects an invalid --filter before sending the request", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--filter", "$.items[",
+					"/api/my_service/v1/my_objects",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("Can't parse filter"))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
 		It("Preserves long integers", func() {
 			// Prepare the server:
 			apiServer.AppendHandlers(
@@ -255,6 +423,7 @@ var _ = Describe("Get", func() {
 				ConfigString(config).
 				Args(
 					"get",
+					"--pretty",
 					"/api/my_service/v1/my_object",
 				).
 				Run(ctx)
@@ -267,5 +436,68 @@ var _ = Describe("Get", func() {
 				`,
 			)))
 		})
+
+		It("Keeps the connection alive by default", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.Close).To(BeFalse())
+					},
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--max-idle-conns-per-host", "1",
+					"--idle-conn-timeout", "5s",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
+		It("Honours the --disable-keep-alives flag", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.Close).To(BeTrue())
+					},
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--disable-keep-alives",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
+		It("Rejects a negative --max-idle-conns-per-host", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"get",
+					"--max-idle-conns-per-host", "-1",
+					"/api/my_service/v1/my_object",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("--max-idle-conns-per-host"))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
 	})
 })
@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Hibernate and resume cluster", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	// prepareCluster adds the handlers that return the cluster with the given state and product:
+	prepareCluster := func(state, product string) {
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSONTemplate(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "{{ .State }}",
+					"product": {
+						"id": "{{ .Product }}"
+					}
+				}`,
+				"State", state,
+				"Product", product,
+			),
+		)
+	}
+
+	It("Hibernates a ready cluster", func() {
+		prepareCluster("ready", "osd")
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/hibernate"),
+				RespondWithJSON(http.StatusAccepted, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("hibernate", "cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal("Hibernating cluster 'mycluster'\n"))
+	})
+
+	It("Doesn't write the progress message with --quiet", func() {
+		prepareCluster("ready", "osd")
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/hibernate"),
+				RespondWithJSON(http.StatusAccepted, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("hibernate", "cluster", "mycluster", "--quiet").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(BeEmpty())
+	})
+
+	It("Rejects hibernating a cluster that isn't ready", func() {
+		prepareCluster("installing", "osd")
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("hibernate", "cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Can't hibernate cluster 'mycluster' because it is installing",
+		))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Rejects hibernating a cluster of a product that doesn't support it", func() {
+		prepareCluster("ready", "rosa")
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("hibernate", "cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Can't hibernate cluster 'mycluster': hibernation isn't supported for clusters " +
+				"of product 'rosa', only for products: osd, osdtrial",
+		))
+	})
+
+	It("Resumes a hibernating cluster", func() {
+		prepareCluster("hibernating", "osd")
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/resume"),
+				RespondWithJSON(http.StatusAccepted, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("resume", "cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal("Resuming cluster 'mycluster'\n"))
+	})
+
+	It("Rejects resuming a cluster that isn't hibernating", func() {
+		prepareCluster("ready", "osd")
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("resume", "cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Can't resume cluster 'mycluster' because it is ready",
+		))
+	})
+})
@@ -18,7 +18,9 @@ package tests
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"    // nolint
@@ -264,5 +266,656 @@ var _ = Describe("List clusters", func() {
 				`^\s*123\s+e30bac0b-b337-47d7-a378-2c302b4c868a\s+my_cluster\s*$`,
 			))
 		})
+
+		It("Writes comma separated values", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 1,
+						"total": 1,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"name": "my,cluster",
+								"state": "ready"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--columns", "id,name,state",
+					"--output", "csv",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()).To(Equal([]string{
+				"id,name,state",
+				`123,"my,cluster",ready`,
+			}))
+		})
+
+		It("Writes the fields given with --fields in the given order", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 1,
+						"total": 1,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"name": "my_cluster",
+								"state": "ready",
+								"region": {
+									"id": "us-east-1"
+								}
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--fields", "name,id,state,region",
+					"--output", "csv",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()).To(Equal([]string{
+				"name,id,state,region.id",
+				"my_cluster,123,ready,us-east-1",
+			}))
+		})
+
+		It("Rejects unknown fields", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--fields", "name,regoin",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Unknown field 'regoin', valid fields are id, external_id, name, api_url, " +
+					"version, product, hcp, provider, region, state",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Rejects --fields together with --columns", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--fields", "name",
+					"--columns", "id",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Options '--fields' and '--columns' can't be used together",
+			))
+		})
+
+		It("Doesn't color the state when the output isn't a terminal", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 1,
+						"total": 1,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"name": "my_cluster",
+								"state": "ready"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--columns", "id,name,state",
+					"--no-color",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutString()).ToNot(ContainSubstring("\033["))
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[1]).To(MatchRegexp(`^123\s+my_cluster\s+ready\s*$`))
+		})
+
+		It("Retries a page that fails in the middle of the list", func() {
+			// makePage generates a page of clusters with consecutive identifiers:
+			makePage := func(page, size int) string {
+				items := make([]string, size)
+				for i := range items {
+					items[i] = fmt.Sprintf(`{"kind": "Cluster", "id": "%d"}`, (page-1)*100+i)
+				}
+				return fmt.Sprintf(
+					`{"kind": "ClusterList", "page": %d, "size": %d, "items": [%s]}`,
+					page, size, strings.Join(items, ","),
+				)
+			}
+
+			// Prepare the server so that the request for the third page fails more times than
+			// the SDK retries it:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyFormKV("page", "1"),
+					RespondWithJSON(http.StatusOK, makePage(1, 100)),
+				),
+				CombineHandlers(
+					VerifyFormKV("page", "2"),
+					RespondWithJSON(http.StatusOK, makePage(2, 100)),
+				),
+				CombineHandlers(
+					VerifyFormKV("page", "3"),
+					RespondWithJSON(
+						http.StatusInternalServerError,
+						`{"kind": "Error", "id": "500", "reason": "Internal error"}`,
+					),
+				),
+				CombineHandlers(
+					VerifyFormKV("page", "3"),
+					RespondWithJSON(
+						http.StatusInternalServerError,
+						`{"kind": "Error", "id": "500", "reason": "Internal error"}`,
+					),
+				),
+				CombineHandlers(
+					VerifyFormKV("page", "3"),
+					RespondWithJSON(
+						http.StatusInternalServerError,
+						`{"kind": "Error", "id": "500", "reason": "Internal error"}`,
+					),
+				),
+				CombineHandlers(
+					VerifyFormKV("page", "3"),
+					RespondWithJSON(http.StatusOK, makePage(3, 5)),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--columns", "id",
+					"--no-headers",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Failed to retrieve page 3 of clusters, will retry in 2s",
+			))
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(205))
+			for i, line := range lines {
+				Expect(strings.TrimSpace(line)).To(Equal(fmt.Sprintf("%d", i)))
+			}
+			Expect(apiServer.ReceivedRequests()).To(HaveLen(6))
+		})
+
+		It("Doesn't retry a search expression rejected by the server", func() {
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusBadRequest,
+					`{"kind": "Error", "id": "400", "reason": "Invalid search"}`,
+				),
+			)
+			result := NewCommand().
+				ConfigString(config).
+				Args("list", "clusters", "--search", "name = 'x'").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).ToNot(ContainSubstring("will retry"))
+			Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("Sends the --search expression to the server", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyFormKV("search", "(name like 'prod%') and (managed = 't')"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "ClusterList",
+							"page": 1,
+							"size": 0,
+							"total": 0,
+							"items": []
+						}`,
+					),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--search", "name like 'prod%'",
+					"--managed",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
+		It("Rejects a --search expression with unbalanced quotes", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--search", "name like 'prod%",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Invalid search expression 'name like 'prod%': missing closing quote",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Reports the search expressions rejected by the server", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusBadRequest,
+					`{
+						"kind": "Error",
+						"id": "400",
+						"href": "/api/clusters_mgmt/v1/errors/400",
+						"code": "CLUSTERS-MGMT-400",
+						"reason": "Field 'junk' isn't supported"
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--search", "junk = 'x'",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				`The server rejected the search expression "junk = 'x'": Field 'junk' isn't supported`,
+			))
+		})
+
+		It("Writes the clusters using a Go template", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 2,
+						"total": 2,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"name": "my_cluster",
+								"cloud_provider": {
+									"id": "aws"
+								},
+								"state": "ready"
+							},
+							{
+								"kind": "Cluster",
+								"id": "456",
+								"name": "your_cluster",
+								"cloud_provider": {
+									"id": "gcp"
+								},
+								"state": "installing"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--output", `go-template={{ .id }} {{ upper .cloud_provider.id }} {{ .state }}{{ "\n" }}`,
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()).To(Equal([]string{
+				"123 AWS ready",
+				"456 GCP installing",
+			}))
+		})
+
+		It("Sorts the clusters by the field given with --sort-by", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 3,
+						"total": 3,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"openshift_version": "4.9.3"
+							},
+							{
+								"kind": "Cluster",
+								"id": "456",
+								"openshift_version": "4.12.1"
+							},
+							{
+								"kind": "Cluster",
+								"id": "789",
+								"openshift_version": "4.10.0"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--fields", "id,version",
+					"--sort-by", "-version",
+					"--no-headers",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(MatchRegexp(`^456\s+4\.12\.1\s*$`))
+			Expect(lines[1]).To(MatchRegexp(`^789\s+4\.10\.0\s*$`))
+			Expect(lines[2]).To(MatchRegexp(`^123\s+4\.9\.3\s*$`))
+		})
+
+		It("Rejects a --sort-by field that isn't displayed", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--fields", "id,name",
+					"--sort-by", "state",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Invalid sort field 'state', valid fields are id, name",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Writes the number of clusters in each state", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 4,
+						"total": 4,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"state": "installing"
+							},
+							{
+								"kind": "Cluster",
+								"id": "456",
+								"state": "ready"
+							},
+							{
+								"kind": "Cluster",
+								"id": "789",
+								"state": "error"
+							},
+							{
+								"kind": "Cluster",
+								"id": "012",
+								"state": "ready"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args("list", "clusters", "--summary").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(5))
+			Expect(lines[0]).To(MatchRegexp(`^STATE\s+COUNT$`))
+			Expect(lines[1]).To(MatchRegexp(`^ready\s+2$`))
+			Expect(lines[2]).To(MatchRegexp(`^error\s+1$`))
+			Expect(lines[3]).To(MatchRegexp(`^installing\s+1$`))
+			Expect(lines[4]).To(MatchRegexp(`^TOTAL\s+4$`))
+		})
+
+		It("Groups the clusters by the field given with --group-by", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 3,
+						"total": 3,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"openshift_version": "4.12.1"
+							},
+							{
+								"kind": "Cluster",
+								"id": "456",
+								"openshift_version": "4.12.1"
+							},
+							{
+								"kind": "Cluster",
+								"id": "789"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--group-by", "version",
+					"--output", "csv",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()).To(Equal([]string{
+				"version,count",
+				"4.12.1,2",
+				"unknown,1",
+			}))
+		})
+
+		It("Rejects an unknown --group-by field", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--group-by", "color",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Invalid group 'color', options are state, region, provider, version",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Rejects --summary together with --fields", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--summary",
+					"--fields", "id,name",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Option '--summary' can't be used together with '--fields' or '--columns'",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Writes only the identifiers with --id-only", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "ClusterList",
+						"page": 1,
+						"size": 2,
+						"total": 2,
+						"items": [
+							{
+								"kind": "Cluster",
+								"id": "123",
+								"name": "my_cluster",
+								"openshift_version": "4.9.3"
+							},
+							{
+								"kind": "Cluster",
+								"id": "456",
+								"name": "your_cluster",
+								"openshift_version": "4.12.1"
+							}
+						]
+					}`,
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--id-only",
+					"--sort-by", "-version",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutLines()).To(Equal([]string{
+				"456",
+				"123",
+			}))
+		})
+
+		It("Rejects --id-only together with --output", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--id-only",
+					"--output", "csv",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Option '--id-only' can't be used together with '--output'",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Names --group-by when it is used together with --id-only", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--id-only",
+					"--group-by", "state",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Option '--id-only' can't be used together with '--group-by'",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("Rejects a Go template that can't be parsed", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args(
+					"list", "clusters",
+					"--output", "go-template={{ .id ",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("Failed to parse template"))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
 	})
 })
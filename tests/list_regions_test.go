@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("List regions", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	When("The provider is valid", func() {
+		BeforeEach(func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/cloud_providers/aws/regions"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "CloudRegionList",
+							"page": 1,
+							"size": 3,
+							"total": 3,
+							"items": [
+								{
+									"kind": "CloudRegion",
+									"id": "us-east-1",
+									"enabled": true,
+									"supports_multi_az": true
+								},
+								{
+									"kind": "CloudRegion",
+									"id": "us-west-1",
+									"enabled": false,
+									"supports_multi_az": false
+								},
+								{
+									"kind": "CloudRegion",
+									"id": "ap-south-2",
+									"enabled": true,
+									"ccs_only": true,
+									"supports_multi_az": false
+								}
+							]
+						}`,
+					),
+				),
+			)
+		})
+
+		It("Writes the enabled regions with their multi-AZ support", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("list", "regions", "--provider", "aws").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(MatchRegexp(`^ID\s+ON RED HAT INFRA\s+CCS ONLY\s+SUPPORTS MULTI-AZ\s*$`))
+			Expect(lines[1]).To(MatchRegexp(`^us-east-1\s+true\s+false\s+true\s*$`))
+			Expect(lines[2]).To(MatchRegexp(`^ap-south-2\s+false\s+true\s+false\s*$`))
+		})
+
+		It("Writes the enabled regions in JSON format", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("list", "regions", "--provider", "aws", "--output", "json").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutString()).To(MatchJSON(`[
+				{
+					"kind": "CloudRegion",
+					"id": "us-east-1",
+					"enabled": true,
+					"supports_multi_az": true
+				},
+				{
+					"kind": "CloudRegion",
+					"id": "ap-south-2",
+					"enabled": true,
+					"ccs_only": true,
+					"supports_multi_az": false
+				}
+			]`))
+		})
+	})
+
+	It("Rejects an unknown provider", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "regions", "--provider", "azure").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid provider 'azure', options are [aws gcp]",
+		))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("Rejects an unknown output format", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "regions", "--provider", "aws", "--output", "yaml").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("Invalid output format 'yaml'"))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("List versions", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+
+		// Prepare the server:
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions"),
+				VerifyFormKV("search", "enabled = 'true' AND channel_group = 'stable'"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "VersionList",
+						"page": 1,
+						"size": 2,
+						"total": 2,
+						"items": [
+							{
+								"kind": "Version",
+								"id": "openshift-v4.10.1",
+								"channel_group": "stable",
+								"enabled": true,
+								"default": false
+							},
+							{
+								"kind": "Version",
+								"id": "openshift-v4.9.3",
+								"channel_group": "stable",
+								"enabled": true,
+								"default": true
+							}
+						]
+					}`,
+				),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	It("Writes the versions sorted, with the default and enabled status", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "versions", "--channel-group", "stable").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		lines := result.OutLines()
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(MatchRegexp(`^\s*VERSION\s+DEFAULT\s+ENABLED\s*$`))
+		Expect(lines[1]).To(MatchRegexp(`^\s*4\.9\.3\s+true\s+true\s*$`))
+		Expect(lines[2]).To(MatchRegexp(`^\s*4\.10\.1\s+false\s+true\s*$`))
+	})
+
+	It("Writes the versions in JSON format", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "versions", "--output", "json").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(MatchJSON(`[
+			{
+				"kind": "Version",
+				"id": "openshift-v4.9.3",
+				"channel_group": "stable",
+				"enabled": true,
+				"default": true
+			},
+			{
+				"kind": "Version",
+				"id": "openshift-v4.10.1",
+				"channel_group": "stable",
+				"enabled": true,
+				"default": false
+			}
+		]`))
+	})
+
+	It("Writes only the default version", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "versions", "--default").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(Equal("4.9.3\n"))
+	})
+})
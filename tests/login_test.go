@@ -18,6 +18,7 @@ package tests
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
@@ -86,6 +87,53 @@ var _ = Describe("Login", func() {
 		})
 	})
 
+	When("Using a token file", func() {
+		It("Reads the token from the standard input", func() {
+			// Create the tokens:
+			accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+			// Run the command:
+			result := NewCommand().
+				InString("\n"+accessToken+"\n").
+				Args(
+					"login",
+					"--token-file", "-",
+					"--token-url", ssoServer.URL(),
+				).
+				Run(ctx)
+
+			// Check that the token was saved without the white space:
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.ConfigString()).To(ContainSubstring(`"access_token": "` + accessToken + `"`))
+		})
+
+		It("Rejects the token file together with the token", func() {
+			accessToken := MakeTokenString("Bearer", 15*time.Minute)
+			result := NewCommand().
+				InString(accessToken).
+				Args(
+					"login",
+					"--token", accessToken,
+					"--token-file", "-",
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Options '--token' and '--token-file' can't be used together",
+			))
+		})
+
+		It("Rejects an empty token file", func() {
+			result := NewCommand().
+				InString(" \n").
+				Args("login", "--token-file", "-").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("Token file '-' is empty"))
+		})
+	})
+
 	When("Using client credentials grant", func() {
 		It("Creates the configuration file", func() {
 			// Create the token:
@@ -182,4 +230,132 @@ var _ = Describe("Login", func() {
 			))
 		})
 	})
+
+	When("Using the device code flow", func() {
+		var tokenURL string
+
+		BeforeEach(func() {
+			tokenURL = ssoServer.URL() + "/auth/realms/redhat-external/protocol/openid-connect/token"
+		})
+
+		It("Saves the tokens once the login is completed", func() {
+			// Create the tokens:
+			accessToken := MakeTokenString("Bearer", 15*time.Minute)
+			refreshToken := MakeTokenString("Refresh", 10*time.Hour)
+
+			// Prepare the server:
+			ssoServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(
+						http.MethodPost,
+						"/auth/realms/redhat-external/protocol/openid-connect/auth/device",
+					),
+					VerifyFormKV("client_id", "ocm-cli"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"device_code": "my-device-code",
+							"user_code": "ABCD-EFGH",
+							"verification_uri": "https://sso.example.com/device",
+							"verification_uri_complete": "https://sso.example.com/device?user_code=ABCD-EFGH",
+							"expires_in": 600,
+							"interval": 1
+						}`,
+					),
+				),
+				RespondWithTokenError("authorization_pending", "The user hasn't logged in yet"),
+				CombineHandlers(
+					VerifyRequest(
+						http.MethodPost,
+						"/auth/realms/redhat-external/protocol/openid-connect/token",
+					),
+					VerifyFormKV("grant_type", "urn:ietf:params:oauth:grant-type:device_code"),
+					VerifyFormKV("device_code", "my-device-code"),
+					VerifyFormKV("client_id", "ocm-cli"),
+					RespondWithAccessAndRefreshTokens(accessToken, refreshToken),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				Args(
+					"login",
+					"--use-device-code",
+					"--token-url", tokenURL,
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).To(BeEmpty())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"To log in open 'https://sso.example.com/device' in a browser and enter " +
+					"the code 'ABCD-EFGH'",
+			))
+			Expect(result.ConfigString()).To(MatchJSONTemplate(
+				`{
+					"url": "{{ .url }}",
+					"token_url": "{{ .tokenURL }}",
+					"client_id": "ocm-cli",
+					"scopes": [
+						{{ range $i, $scope := .scopes }}
+							{{ if gt $i 0 }},{{ end }}
+							"{{ $scope }}"
+						{{ end }}
+					],
+					"access_token": "{{ .accessToken }}",
+					"refresh_token": "{{ .refreshToken }}"
+				}`,
+				"url", sdk.DefaultURL,
+				"tokenURL", tokenURL,
+				"scopes", sdk.DefaultScopes,
+				"accessToken", accessToken,
+				"refreshToken", refreshToken,
+			))
+		})
+
+		It("Fails if the login is denied", func() {
+			// Prepare the server:
+			ssoServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"device_code": "my-device-code",
+						"user_code": "ABCD-EFGH",
+						"verification_uri": "https://sso.example.com/device",
+						"expires_in": 600,
+						"interval": 1
+					}`,
+				),
+				RespondWithTokenError("access_denied", "The user denied the login"),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				Args(
+					"login",
+					"--use-device-code",
+					"--token-url", tokenURL,
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Can't complete device code login: the login was denied",
+			))
+			Expect(result.ConfigString()).To(BeEmpty())
+		})
+
+		It("Can't be used together with a token", func() {
+			accessToken := MakeTokenString("Bearer", 15*time.Minute)
+			result := NewCommand().
+				Args(
+					"login",
+					"--use-device-code",
+					"--token", accessToken,
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Option '--use-device-code' can't be used together with '--token'",
+			))
+		})
+	})
 })
@@ -18,10 +18,12 @@ package tests
 
 import (
 	"context"
+	"net/http"
 	"time"
 
-	. "github.com/onsi/ginkgo/v2" // nolint
-	. "github.com/onsi/gomega"    // nolint
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
 
 	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
 )
@@ -124,4 +126,67 @@ var _ = Describe("Logout", func() {
 			"pager": "less"
 		}`))
 	})
+
+	When("Logged in with a refresh token", func() {
+		var ssoServer *Server
+		var config string
+		var refreshToken string
+
+		BeforeEach(func() {
+			ssoServer = MakeTCPServer()
+			refreshToken = MakeTokenString("Refresh", 10*time.Hour)
+			config = EvaluateTemplate(
+				`{
+					"client_id": "my-client",
+					"refresh_token": "{{ .refreshToken }}",
+					"token_url": "{{ .tokenURL }}/my-realm/protocol/openid-connect/token",
+					"url": "http://my-api.example.com"
+				}`,
+				"refreshToken", refreshToken,
+				"tokenURL", ssoServer.URL(),
+			)
+		})
+
+		AfterEach(func() {
+			ssoServer.Close()
+		})
+
+		It("Revokes the refresh token", func() {
+			ssoServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/my-realm/protocol/openid-connect/revoke"),
+					VerifyFormKV("token", refreshToken),
+					VerifyFormKV("token_type_hint", "refresh_token"),
+					VerifyFormKV("client_id", "my-client"),
+					RespondWith(http.StatusOK, nil),
+				),
+			)
+
+			result := NewCommand().
+				ConfigString(config).
+				Args("logout").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.ConfigString()).To(MatchJSON(`{}`))
+			Expect(ssoServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("Removes the tokens even if they can't be revoked", func() {
+			ssoServer.AppendHandlers(
+				RespondWith(http.StatusServiceUnavailable, nil),
+			)
+
+			result := NewCommand().
+				ConfigString(config).
+				Args("logout").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Warning: failed to revoke the refresh token",
+			))
+			Expect(result.ErrString()).To(ContainSubstring("unexpected status code 503"))
+			Expect(result.ConfigString()).To(MatchJSON(`{}`))
+		})
+	})
 })
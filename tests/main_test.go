@@ -63,6 +63,11 @@ type CommandRunner struct {
 	args   []string
 	config string
 	in     []byte
+	open   bool
+
+	// Keys typed in the terminal, when the command runs in one:
+	terminal bool
+	answers  []terminalAnswer
 }
 
 // CommandResult contains the result of executing a CLI command.
@@ -111,6 +116,32 @@ func (r *CommandRunner) InString(value string) *CommandRunner {
 	return r
 }
 
+// InOpen keeps the standard input of the CLI command open and without data, like a terminal where
+// nobody types anything.
+func (r *CommandRunner) InOpen() *CommandRunner {
+	r.open = true
+	return r
+}
+
+// Terminal runs the CLI command in a pseudo terminal, like when it is used by a person. The
+// standard output and the standard error are both written to the terminal, so all the text is
+// returned as the standard output of the result.
+func (r *CommandRunner) Terminal() *CommandRunner {
+	r.terminal = true
+	return r
+}
+
+// Answer types the given keys in the terminal when the CLI command writes the given text after
+// the text of the previous answer. It implies Terminal.
+func (r *CommandRunner) Answer(text, keys string) *CommandRunner {
+	r.terminal = true
+	r.answers = append(r.answers, terminalAnswer{
+		text: text,
+		keys: keys,
+	})
+	return r
+}
+
 // Run runs the command.
 func (r *CommandRunner) Run(ctx context.Context) *CommandResult {
 	var err error
@@ -173,11 +204,24 @@ func (r *CommandRunner) Run(ctx context.Context) *CommandResult {
 	cmd := exec.Command(binary, r.args...) //nolint:gosec
 	cmd.Env = envList
 	cmd.Stdin = inBuf
+	if r.open {
+		// The command would wait for the copy to the pipe to finish if the input weren't a
+		// file, so use a pipe directly, and close it only when the command has finished:
+		inReader, inWriter, err := os.Pipe()
+		ExpectWithOffset(1, err).ToNot(HaveOccurred())
+		defer inReader.Close()
+		defer inWriter.Close()
+		cmd.Stdin = inReader
+	}
 	cmd.Stdout = outBuf
 	cmd.Stderr = errBuf
 
 	// Run the command:
-	err = cmd.Run()
+	if r.terminal {
+		err = runInTerminal(cmd, r.answers, outBuf)
+	} else {
+		err = cmd.Run()
+	}
 	switch err.(type) {
 	case *exec.ExitError:
 		// Nothing, this is a normal situation and the caller is expected to check it using
@@ -248,6 +292,21 @@ func (r *CommandResult) ErrString() string {
 	return string(r.err)
 }
 
+// ErrLines returns the standard error output of the CLI command as an array of strings.
+func (r *CommandResult) ErrLines() []string {
+	// Split the output into lines:
+	lines := strings.Split(string(r.err), "\n")
+
+	// If there is a blank line at the end remove it:
+	count := len(lines)
+	if count > 0 && lines[count-1] == "" {
+		lines = lines[0 : count-1]
+	}
+
+	// Return the lines:
+	return lines
+}
+
 // ExitCode returns the exit code of the CLI command.
 func (r *CommandResult) ExitCode() int {
 	return r.exitCode
@@ -0,0 +1,237 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Organization quota", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	When("Listing the quota of the current organization", func() {
+		BeforeEach(func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/accounts_mgmt/v1/current_account"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "Account",
+							"id": "123",
+							"organization": {
+								"kind": "Organization",
+								"id": "456"
+							}
+						}`,
+					),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/accounts_mgmt/v1/organizations/456/quota_cost"),
+					VerifyFormKV("fetchRelatedResources", "true"),
+					RespondWithJSON(
+						http.StatusOK,
+						`{
+							"kind": "QuotaCostList",
+							"page": 1,
+							"size": 2,
+							"total": 2,
+							"items": [
+								{
+									"kind": "QuotaCost",
+									"quota_id": "cluster|byoc|osd",
+									"allowed": 5,
+									"consumed": 5,
+									"related_resources": [
+										{
+											"resource_type": "cluster.aws",
+											"resource_name": "osd",
+											"cost": 1
+										},
+										{
+											"resource_type": "cluster.gcp",
+											"resource_name": "osd",
+											"cost": 1
+										}
+									]
+								},
+								{
+									"kind": "QuotaCost",
+									"quota_id": "compute.node|cpu|byoc|osd",
+									"allowed": 80,
+									"consumed": 24,
+									"related_resources": [
+										{
+											"resource_type": "compute.node",
+											"resource_name": "gen2",
+											"cost": 4
+										}
+									]
+								}
+							]
+						}`,
+					),
+				),
+			)
+		})
+
+		It("Writes a table", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("org", "quota").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			lines := result.OutLines()
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(MatchRegexp(
+				`^QUOTA ID\s+RESOURCE TYPES\s+CONSUMED\s+ALLOWED\s+AVAILABLE$`,
+			))
+			Expect(lines[1]).To(MatchRegexp(
+				`^cluster\|byoc\|osd\s+cluster\.aws,cluster\.gcp\s+5\s+5\s+0$`,
+			))
+			Expect(lines[2]).To(MatchRegexp(
+				`^compute\.node\|cpu\|byoc\|osd\s+compute\.node\s+24\s+80\s+56$`,
+			))
+		})
+
+		It("Writes JSON", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("org", "quota", "--output", "json").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.OutString()).To(MatchJSON(`[
+				{
+					"quota_id": "cluster|byoc|osd",
+					"allowed": 5,
+					"consumed": 5,
+					"related_resources": [
+						{
+							"resource_type": "cluster.aws",
+							"resource_name": "osd",
+							"cost": 1
+						},
+						{
+							"resource_type": "cluster.gcp",
+							"resource_name": "osd",
+							"cost": 1
+						}
+					]
+				},
+				{
+					"quota_id": "compute.node|cpu|byoc|osd",
+					"allowed": 80,
+					"consumed": 24,
+					"related_resources": [
+						{
+							"resource_type": "compute.node",
+							"resource_name": "gen2",
+							"cost": 4
+						}
+					]
+				}
+			]`))
+		})
+	})
+
+	It("Uses the organization given with --org", func() {
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/accounts_mgmt/v1/organizations/789/quota_cost"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "QuotaCostList",
+						"page": 1,
+						"size": 0,
+						"total": 0,
+						"items": []
+					}`,
+				),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("org", "quota", "--org", "789").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutLines()).To(HaveLen(1))
+	})
+
+	It("Rejects an invalid output format", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args("org", "quota", "--output", "yaml").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid output format 'yaml', the only option is 'json'",
+		))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+})
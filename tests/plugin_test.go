@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2" // nolint
+	. "github.com/onsi/gomega"    // nolint
+)
+
+var _ = Describe("Plugin", func() {
+	var ctx context.Context
+	var tmp string
+
+	BeforeEach(func() {
+		var err error
+
+		// The plugin is a shell script:
+		if runtime.GOOS == "windows" {
+			Skip("Plugins are shell scripts")
+		}
+
+		// Create a context:
+		ctx = context.Background()
+
+		// Create a temporary directory for the plugins:
+		tmp, err = os.MkdirTemp("", "ocm-test-*.d")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create a plugin that writes its arguments and the environment variables that
+		// describe the connection:
+		script := "#!/bin/sh\n" +
+			"echo \"args=$*\"\n" +
+			"echo \"url=$OCM_URL\"\n" +
+			"echo \"token_url=$OCM_TOKEN_URL\"\n" +
+			"echo \"client_id=$OCM_CLIENT_ID\"\n" +
+			"echo \"profile=$OCM_PROFILE\"\n" +
+			"echo \"config=$OCM_CONFIG\"\n"
+		err = os.WriteFile(filepath.Join(tmp, "ocm-hello"), []byte(script), 0700)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Delete the temporary plugins directory:
+		err := os.RemoveAll(tmp)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Passes the arguments and the connection details", func() {
+		result := NewCommand().
+			ConfigString(`{
+				"url": "https://my.api.com",
+				"token_url": "https://my.sso.com/token",
+				"client_id": "my-client"
+			}`).
+			Env("PATH", tmp).
+			Args("hello", "--my-flag", "my-value").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		lines := result.OutLines()
+		Expect(lines).To(HaveLen(6))
+		Expect(lines[0]).To(Equal("args=--my-flag my-value"))
+		Expect(lines[1]).To(Equal("url=https://my.api.com"))
+		Expect(lines[2]).To(Equal("token_url=https://my.sso.com/token"))
+		Expect(lines[3]).To(Equal("client_id=my-client"))
+		Expect(lines[4]).To(Equal("profile=default"))
+		Expect(lines[5]).To(MatchRegexp(`^config=.+$`))
+	})
+
+	It("Doesn't pass the connection details if not logged in", func() {
+		result := NewCommand().
+			Env("PATH", tmp).
+			Args("hello").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		lines := result.OutLines()
+		Expect(lines).To(HaveLen(6))
+		Expect(lines[1]).To(Equal("url="))
+		Expect(lines[4]).To(Equal("profile=default"))
+	})
+})
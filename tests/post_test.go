@@ -104,6 +104,40 @@ var _ = Describe("Post", func() {
 			Expect(result.ErrString()).To(BeEmpty())
 		})
 
+		It("Reads the standard input when the body is '-'", func() {
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyBody([]byte(`{ "my_field": "my_value" }`)),
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+
+			// Run the command:
+			result := NewCommand().
+				ConfigString(config).
+				Args("post", "--body", "-", "/api/my_service/v1/my_object").
+				InString(`{ "my_field": "my_value" }`).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+		})
+
+		It("Rejects a body that isn't valid JSON without sending it", func() {
+			// Run the command. Note that no handler is prepared, so sending the request
+			// would fail the test.
+			result := NewCommand().
+				ConfigString(config).
+				Args("post", "/api/my_service/v1/my_object").
+				InString("{\n  \"my_field\": my_value\n}").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"Can't read body: invalid JSON at line 2, column 15",
+			))
+			Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+		})
+
 		It("Honours the --parameter flag", func() {
 			// Prepare the server:
 			apiServer.AppendHandlers(
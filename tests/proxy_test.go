@@ -0,0 +1,156 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Proxy", func() {
+	var ctx context.Context
+	var proxyServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create a context:
+		ctx = context.Background()
+
+		// Create the server that acts as proxy:
+		proxyServer = MakeTCPServer()
+
+		// Requests to local addresses never use the proxy, so the configuration points to a
+		// server that is only reachable through it:
+		config = EvaluateTemplate(
+			`{
+				"access_token": "{{ .AccessToken }}",
+				"url": "http://api.example.com",
+				"token_url": "http://sso.example.com"
+			}`,
+			"AccessToken", MakeTokenString("Bearer", 15*time.Minute),
+		)
+	})
+
+	AfterEach(func() {
+		// Close the server:
+		proxyServer.Close()
+	})
+
+	// proxiedHandler returns the handler that checks that the proxy receives the request for the
+	// API server.
+	proxiedHandler := func() http.HandlerFunc {
+		return CombineHandlers(
+			VerifyRequest(http.MethodGet, "/api/my_service/v1/my_object"),
+			func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Host).To(Equal("api.example.com"))
+			},
+			RespondWithJSON(http.StatusOK, `{}`),
+		)
+	}
+
+	It("Uses the proxy from the environment", func() {
+		proxyServer.AppendHandlers(proxiedHandler())
+		result := NewCommand().
+			ConfigString(config).
+			Env("HTTP_PROXY", proxyServer.URL()).
+			Args("get", "/api/my_service/v1/my_object").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(Equal("{}\n"))
+	})
+
+	It("Gives precedence to the --proxy flag", func() {
+		proxyServer.AppendHandlers(proxiedHandler())
+		result := NewCommand().
+			ConfigString(config).
+			Env("HTTP_PROXY", "http://127.0.0.1:1").
+			Args(
+				"get",
+				"--proxy", proxyServer.URL(),
+				"/api/my_service/v1/my_object",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+	})
+
+	It("Gives precedence to the --no-proxy flag", func() {
+		proxyServer.AppendHandlers(proxiedHandler())
+		result := NewCommand().
+			ConfigString(config).
+			Env("NO_PROXY", "api.example.com").
+			Args(
+				"get",
+				"--proxy", proxyServer.URL(),
+				"--no-proxy", "other.example.com",
+				"/api/my_service/v1/my_object",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+	})
+
+	It("Rejects an invalid --proxy flag", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"get",
+				"--proxy", "ftp://proxy.example.com",
+				"/api/my_service/v1/my_object",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid value 'ftp://proxy.example.com' for --proxy: unsupported scheme 'ftp'",
+		))
+	})
+
+	It("Rejects an invalid proxy environment variable", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Env("HTTPS_PROXY", "http://:3128").
+			Args("get", "/api/my_service/v1/my_object").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid value 'http://:3128' for the 'HTTPS_PROXY' environment variable",
+		))
+	})
+
+	It("Explains that the proxy isn't reachable", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"get",
+				"--proxy", "http://127.0.0.1:1",
+				"/api/my_service/v1/my_object",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"can't connect to proxy 'http://127.0.0.1:1'",
+		))
+	})
+})
@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/creack/pty"
+	"golang.org/x/sys/unix"
+)
+
+// terminalAnswer contains the keys that are typed in the terminal when the command writes a text.
+type terminalAnswer struct {
+	text string
+	keys string
+}
+
+// cursorQuery is the escape sequence that the prompts write to ask for the position of the
+// cursor, and cursorReport is the answer, as if the cursor were in the last row and column.
+const (
+	cursorQuery  = "\x1b[6n"
+	cursorReport = "\x1b[24;80R"
+)
+
+// terminalTimeout is the maximum time that a command can run in the terminal, so that a command
+// that waits for keys that are never typed doesn't block the tests forever.
+const terminalTimeout = time.Minute
+
+// terminalIdle is the time that the command has to be without writing anything before typing the
+// keys of an answer. The prompts read the answers to the cursor position queries with buffers that
+// would discard keys typed too early.
+const terminalIdle = 200 * time.Millisecond
+
+// runInTerminal runs the given command in a pseudo terminal, typing the keys of the answers when
+// their texts are written, and copying everything that is written to the given writer.
+func runInTerminal(cmd *exec.Cmd, answers []terminalAnswer, out io.Writer) error {
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+	terminal, err := pty.StartWithSize(cmd, &pty.Winsize{
+		Rows: 24,
+		Cols: 80,
+	})
+	if err != nil {
+		return err
+	}
+	defer terminal.Close()
+	timer := time.AfterFunc(terminalTimeout, func() {
+		_ = cmd.Process.Kill()
+	})
+	defer timer.Stop()
+
+	// Reading fails when the command finishes and closes the terminal:
+	chunks := make(chan []byte)
+	go func() {
+		defer close(chunks)
+		buffer := make([]byte, 4096)
+		for {
+			n, err := terminal.Read(buffer)
+			if n > 0 {
+				chunks <- append([]byte(nil), buffer[:n]...)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var written []byte
+	queries := 0
+	start := 0
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				return cmd.Wait()
+			}
+			written = append(written, chunk...)
+			_, _ = out.Write(chunk)
+
+			// The prompts wait for the position of the cursor before reading the keys:
+			for ; queries < bytes.Count(written, []byte(cursorQuery)); queries++ {
+				_, err = terminal.WriteString(cursorReport)
+				if err != nil {
+					return err
+				}
+			}
+		case <-time.After(terminalIdle):
+			if len(answers) == 0 {
+				continue
+			}
+			index := bytes.Index(written[start:], []byte(answers[0].text))
+			if index < 0 {
+				continue
+			}
+
+			// Keys typed before the prompt puts the terminal in raw mode would be processed
+			// as lines, and the prompt wouldn't see them as it expects:
+			termios, err := unix.IoctlGetTermios(int(terminal.Fd()), unix.TCGETS)
+			if err != nil {
+				return err
+			}
+			if termios.Lflag&unix.ICANON != 0 {
+				continue
+			}
+			start += index + len(answers[0].text)
+			_, err = terminal.WriteString(answers[0].keys)
+			if err != nil {
+				return err
+			}
+			answers = answers[1:]
+		}
+	}
+}
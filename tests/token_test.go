@@ -20,8 +20,9 @@ import (
 	"context"
 	"time"
 
-	. "github.com/onsi/ginkgo/v2" // nolint
-	. "github.com/onsi/gomega"    // nolint
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
 
 	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
 )
@@ -73,6 +74,67 @@ var _ = Describe("Token", func() {
 		})
 	})
 
+	When("Generating a new access token", func() {
+		var ssoServer *Server
+		var accessToken string
+
+		BeforeEach(func() {
+			ssoServer = MakeTCPServer()
+			accessToken = MakeTokenString("Bearer", 10*time.Minute)
+		})
+
+		AfterEach(func() {
+			ssoServer.Close()
+		})
+
+		It("Uses the refresh token even if the access token is still valid", func() {
+			newAccessToken := MakeTokenString("Bearer", 15*time.Minute)
+			ssoServer.AppendHandlers(
+				CombineHandlers(
+					VerifyFormKV("grant_type", "refresh_token"),
+					RespondWithAccessToken(newAccessToken),
+				),
+			)
+			result := NewCommand().
+				ConfigString(
+					`{
+						"refresh_token": "{{ .refreshToken }}",
+						"access_token": "{{ .accessToken }}",
+						"url": "http://my-server.example.com",
+						"token_url": "{{ .tokenURL }}"
+					}`,
+					"accessToken", accessToken,
+					"refreshToken", MakeTokenString("Refresh", 10*time.Hour),
+					"tokenURL", ssoServer.URL(),
+				).
+				Args("token", "--generate").
+				Run(ctx)
+			Expect(result.ErrString()).To(BeEmpty())
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).To(Equal(newAccessToken + "\n"))
+		})
+
+		It("Asks to log in again if the refresh token is expired", func() {
+			result := NewCommand().
+				ConfigString(
+					`{
+						"refresh_token": "{{ .refreshToken }}",
+						"access_token": "{{ .accessToken }}",
+						"url": "http://my-server.example.com",
+						"token_url": "{{ .tokenURL }}"
+					}`,
+					"accessToken", accessToken,
+					"refreshToken", MakeTokenString("Refresh", -1*time.Hour),
+					"tokenURL", ssoServer.URL(),
+				).
+				Args("token", "--generate").
+				Run(ctx)
+			Expect(result.ExitCode()).ToNot(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("refresh token has expired"))
+			Expect(result.ErrString()).To(ContainSubstring("ocm login"))
+		})
+	})
+
 	When("Not logged in", func() {
 		BeforeEach(func() {
 			cmd = NewCommand().Arg("token")
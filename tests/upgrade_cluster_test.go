@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Upgrade cluster", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	// prepareCluster adds the handlers that return the cluster and its current version:
+	prepareCluster := func() {
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"openshift_version": "4.9.3",
+					"version": {
+						"kind": "Version",
+						"id": "openshift-v4.9.3",
+						"channel_group": "stable"
+					},
+					"product": {
+						"id": "osd"
+					}
+				}`,
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions/openshift-v4.9.3"),
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "Version",
+						"id": "openshift-v4.9.3",
+						"channel_group": "stable",
+						"available_upgrades": [
+							"4.9.5",
+							"4.10.1"
+						]
+					}`,
+				),
+			),
+		)
+	}
+
+	It("Rejects a schedule that isn't in the future", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"upgrade", "cluster", "mycluster",
+				"--version", "4.10.1",
+				"--schedule", "2020-01-01T10:00:00Z",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("isn't in the future"))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("Rejects a schedule that isn't in RFC3339 format", func() {
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"upgrade", "cluster", "mycluster",
+				"--version", "4.10.1",
+				"--schedule", "tomorrow",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Invalid value 'tomorrow' for --schedule",
+		))
+	})
+
+	It("Rejects a version that isn't an available upgrade", func() {
+		prepareCluster()
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"upgrade", "cluster", "mycluster",
+				"--version", "4.11.0",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Version '4.11.0' isn't a valid upgrade for cluster 'mycluster' from version " +
+				"'4.9.3', available upgrades are: 4.9.5, 4.10.1",
+		))
+	})
+
+	It("Creates the upgrade policy", func() {
+		prepareCluster()
+		apiServer.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+				VerifyJSON(`{
+					"kind": "UpgradePolicy",
+					"next_run": "2030-06-01T10:00:00Z",
+					"schedule_type": "manual",
+					"version": "4.10.1"
+				}`),
+				RespondWithJSON(http.StatusCreated, `{
+					"kind": "UpgradePolicy",
+					"id": "789"
+				}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args(
+				"upgrade", "cluster", "mycluster",
+				"--version", "openshift-v4.10.1",
+				"--schedule", "2030-06-01T10:00:00Z",
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal(
+			"Created upgrade policy '789' to upgrade cluster 'mycluster' to version 4.10.1 " +
+				"at 2030-06-01T10:00:00Z\n",
+		))
+	})
+})
@@ -0,0 +1,236 @@
+/*
+Copyright (c) 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"    // nolint
+	. "github.com/onsi/gomega"       // nolint
+	. "github.com/onsi/gomega/ghttp" // nolint
+
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Upgrade policies", func() {
+	var ctx context.Context
+	var ssoServer *Server
+	var apiServer *Server
+	var config string
+
+	BeforeEach(func() {
+		// Create the context:
+		ctx = context.Background()
+
+		// Create the servers:
+		ssoServer = MakeTCPServer()
+		apiServer = MakeTCPServer()
+
+		// Create the token:
+		accessToken := MakeTokenString("Bearer", 15*time.Minute)
+
+		// Prepare the server:
+		ssoServer.AppendHandlers(
+			RespondWithAccessToken(accessToken),
+		)
+
+		// Login:
+		result := NewCommand().
+			Args(
+				"login",
+				"--client-id", "my-client",
+				"--client-secret", "my-secret",
+				"--token-url", ssoServer.URL(),
+				"--url", apiServer.URL(),
+			).
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		config = result.ConfigString()
+	})
+
+	AfterEach(func() {
+		// Close the servers:
+		ssoServer.Close()
+		apiServer.Close()
+	})
+
+	// prepareCluster adds the handlers that return the cluster:
+	prepareCluster := func() {
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "SubscriptionList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "Subscription",
+							"id": "456",
+							"status": "Active",
+							"cluster_id": "123"
+						}
+					]
+				}`,
+			),
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "Cluster",
+					"id": "123",
+					"name": "mycluster",
+					"state": "ready",
+					"openshift_version": "4.9.3",
+					"version": {
+						"kind": "Version",
+						"id": "openshift-v4.9.3",
+						"channel_group": "stable"
+					},
+					"product": {
+						"id": "osd"
+					}
+				}`,
+			),
+		)
+	}
+
+	It("Lists the upgrade policies with their state", func() {
+		prepareCluster()
+		apiServer.AppendHandlers(
+			RespondWithJSON(
+				http.StatusOK,
+				`{
+					"kind": "UpgradePolicyList",
+					"page": 1,
+					"size": 1,
+					"total": 1,
+					"items": [
+						{
+							"kind": "UpgradePolicy",
+							"id": "789",
+							"schedule_type": "manual",
+							"version": "4.10.1",
+							"next_run": "2030-06-01T10:00:00Z"
+						}
+					]
+				}`,
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/upgrade_policies/789/state",
+				),
+				RespondWithJSON(http.StatusOK, `{
+					"kind": "UpgradePolicyState",
+					"value": "scheduled"
+				}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("list", "upgrade-policies", "--cluster", "mycluster").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		lines := result.OutLines()
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(MatchRegexp(
+			`^ID\s+SCHEDULE TYPE\s+SCHEDULE\s+UPGRADE VERSION\s+STATE\s+NEXT RUN\s*$`,
+		))
+		Expect(lines[1]).To(MatchRegexp(
+			`^789\s+manual\s+-\s+4\.10\.1\s+scheduled\s+2030-06-01T10:00:00Z\s*$`,
+		))
+	})
+
+	It("Deletes a pending upgrade policy", func() {
+		prepareCluster()
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "UpgradePolicyState",
+				"value": "scheduled"
+			}`),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/upgrade_policies/789",
+				),
+				RespondWithJSON(http.StatusNoContent, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("delete", "upgrade-policy", "--cluster", "mycluster", "789").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(Equal(
+			"Deleted upgrade policy '789' on cluster 'mycluster'\n",
+		))
+	})
+
+	It("Requires --yes to delete an upgrade policy in progress", func() {
+		prepareCluster()
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "UpgradePolicyState",
+				"value": "started"
+			}`),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("delete", "upgrade-policy", "--cluster", "mycluster", "789").
+			Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("already in progress"))
+		Expect(result.ErrString()).To(ContainSubstring("use --yes to delete it anyway"))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("Deletes an upgrade policy in progress with --yes", func() {
+		prepareCluster()
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"kind": "UpgradePolicyState",
+				"value": "started"
+			}`),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/upgrade_policies/789",
+				),
+				RespondWithJSON(http.StatusNoContent, `{}`),
+			),
+		)
+
+		result := NewCommand().
+			ConfigString(config).
+			Args("delete", "upgrade-policy", "--cluster", "mycluster", "--yes", "789").
+			Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.OutString()).To(BeEmpty())
+		Expect(result.ErrString()).To(ContainSubstring("already in progress"))
+		Expect(result.ErrString()).To(ContainSubstring(
+			"Deleted upgrade policy '789' on cluster 'mycluster'\n",
+		))
+	})
+})
@@ -18,9 +18,15 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"time"
 
-	. "github.com/onsi/ginkgo/v2" // nolint
-	. "github.com/onsi/gomega"    // nolint
+	. "github.com/onsi/ginkgo/v2"                      // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
 
 	"github.com/openshift-online/ocm-cli/pkg/info"
 )
@@ -38,4 +44,108 @@ var _ = Describe("Version", func() {
 		Expect(result.ErrString()).To(BeEmpty())
 		Expect(result.ExitCode()).To(BeZero())
 	})
+
+	It("Prints the versions in JSON format when not logged in", func() {
+		ctx := context.Background()
+		result := NewCommand().Args("version", "--output", "json").Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		var data map[string]interface{}
+		err := json.Unmarshal([]byte(result.OutString()), &data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(HaveKeyWithValue("version", info.Version))
+		Expect(data).To(HaveKeyWithValue("go_version", runtime.Version()))
+		Expect(data).To(HaveKey("sdk_version"))
+		Expect(data).ToNot(HaveKey("server_version"))
+	})
+
+	It("Prints the versions in YAML format", func() {
+		ctx := context.Background()
+		result := NewCommand().Args("version", "--output", "yaml").Run(ctx)
+		Expect(result.ExitCode()).To(BeZero())
+		Expect(result.ErrString()).To(BeEmpty())
+		Expect(result.OutString()).To(ContainSubstring("version: " + info.Version + "\n"))
+		Expect(result.OutString()).To(ContainSubstring("go_version: " + runtime.Version() + "\n"))
+	})
+
+	It("Rejects unknown output formats", func() {
+		ctx := context.Background()
+		result := NewCommand().Args("version", "--output", "xml").Run(ctx)
+		Expect(result.ExitCode()).ToNot(BeZero())
+		Expect(result.ErrString()).To(ContainSubstring("Invalid output format 'xml'"))
+	})
+
+	When("Logged in", func() {
+		var ctx context.Context
+		var ssoServer *Server
+		var apiServer *Server
+		var config string
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			// Create the servers:
+			ssoServer = MakeTCPServer()
+			apiServer = MakeTCPServer()
+
+			// Login:
+			ssoServer.AppendHandlers(
+				RespondWithAccessToken(MakeTokenString("Bearer", 15*time.Minute)),
+			)
+			result := NewCommand().
+				Args(
+					"login",
+					"--client-id", "my-client",
+					"--client-secret", "my-secret",
+					"--token-url", ssoServer.URL(),
+					"--url", apiServer.URL(),
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			config = result.ConfigString()
+		})
+
+		AfterEach(func() {
+			// Close the servers:
+			ssoServer.Close()
+			apiServer.Close()
+		})
+
+		It("Includes the version of the server", func() {
+			apiServer.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1"),
+					RespondWithJSON(http.StatusOK, `{
+						"server_version": "abc123"
+					}`),
+				),
+			)
+			result := NewCommand().
+				ConfigString(config).
+				Args("version", "--output", "json").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(BeEmpty())
+			var data map[string]interface{}
+			err := json.Unmarshal([]byte(result.OutString()), &data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(HaveKeyWithValue("server_version", "abc123"))
+		})
+
+		It("Warns if the server version can't be retrieved", func() {
+			apiServer.AppendHandlers(
+				RespondWithJSON(http.StatusForbidden, `{
+					"kind": "Error",
+					"reason": "Forbidden"
+				}`),
+			)
+			result := NewCommand().
+				ConfigString(config).
+				Args("version", "--output", "json").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("Can't get the version of the server"))
+			Expect(result.OutString()).ToNot(ContainSubstring("server_version"))
+		})
+	})
 })
@@ -19,6 +19,7 @@ package tests
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 
@@ -113,4 +114,105 @@ var _ = Describe("Whoami", func() {
 			))
 		})
 	})
+
+	When("Logged in", func() {
+		var ssoServer *Server
+		var apiServer *Server
+		var config string
+
+		BeforeEach(func() {
+			// Create the servers:
+			ssoServer = MakeTCPServer()
+			apiServer = MakeTCPServer()
+
+			// Login:
+			ssoServer.AppendHandlers(
+				RespondWithAccessToken(MakeTokenString("Bearer", 15*time.Minute)),
+			)
+			result := NewCommand().
+				Args(
+					"login",
+					"--client-id", "my-client",
+					"--client-secret", "my-secret",
+					"--token-url", ssoServer.URL(),
+					"--url", apiServer.URL(),
+				).
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			config = result.ConfigString()
+
+			// Prepare the server:
+			apiServer.AppendHandlers(
+				RespondWithJSON(
+					http.StatusOK,
+					`{
+						"kind": "Account",
+						"id": "123",
+						"username": "myuser",
+						"email": "myuser@example.com",
+						"first_name": "My",
+						"organization": {
+							"kind": "Organization",
+							"id": "456",
+							"name": "My organization"
+						}
+					}`,
+				),
+			)
+		})
+
+		AfterEach(func() {
+			// Close the servers:
+			ssoServer.Close()
+			apiServer.Close()
+		})
+
+		It("Writes the identity in JSON format", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("whoami", "--output", "json").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).To(MatchJSON(`{
+				"account_id": "123",
+				"username": "myuser",
+				"email": "myuser@example.com",
+				"organization_id": "456",
+				"organization_name": "My organization"
+			}`))
+		})
+
+		It("Warns when TLS verification is disabled without saving it", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("whoami", "--insecure-skip-tls-verify").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring(
+				"WARNING: TLS certificate verification is disabled"))
+			Expect(result.ConfigString()).ToNot(ContainSubstring("insecure"))
+		})
+
+		It("Logs the HTTP requests redacting the authorization header", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("whoami", "--debug-http").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.ErrString()).To(ContainSubstring("> GET " + apiServer.URL() +
+				"/api/accounts_mgmt/v1/current_account"))
+			Expect(result.ErrString()).To(ContainSubstring("> Authorization: REDACTED"))
+			Expect(result.ErrString()).To(MatchRegexp(`< HTTP/1.1 200 OK after \d+`))
+			Expect(result.ErrString()).ToNot(ContainSubstring("Bearer"))
+		})
+
+		It("Writes the complete account by default", func() {
+			result := NewCommand().
+				ConfigString(config).
+				Args("whoami").
+				Run(ctx)
+			Expect(result.ExitCode()).To(BeZero())
+			Expect(result.OutString()).To(ContainSubstring(`"first_name": "My"`))
+		})
+	})
 })